@@ -0,0 +1,25 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/styx-oracle/styx/oracle"
+)
+
+// handleConsensusDeath votes on a death proposal from a cluster peer
+func (s *Server) handleConsensusDeath(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var p oracle.DeathProposal
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(oracle.DeathVote{Accept: s.oracleFor(r).VoteDeath(p)})
+}
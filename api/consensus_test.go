@@ -0,0 +1,96 @@
+package api
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/styx-oracle/styx/oracle"
+	"github.com/styx-oracle/styx/types"
+)
+
+// reportDead feeds o varied, confident dead reports for target
+func reportDead(o *oracle.Oracle, target types.NodeID) {
+	o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.0, 0.95, 0.05))
+	o.ReceiveReport(types.NewNodeID(11), target, types.MustBelief(0.04, 0.9, 0.06))
+	o.ReceiveReport(types.NewNodeID(12), target, types.MustBelief(0.0, 0.9, 0.1))
+}
+
+// startCluster starts three peers, the first accepting of them see target dead
+func startCluster(t *testing.T, accepting int, target types.NodeID) []string {
+	t.Helper()
+	urls := make([]string, 3)
+	for i := range urls {
+		s := NewServer(uint64(i + 2))
+		if i < accepting {
+			reportDead(s.oracle, target)
+		} else {
+			s.oracle.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.8, 0.1, 0.1))
+		}
+		srv := httptest.NewServer(s.Handler())
+		t.Cleanup(srv.Close)
+		urls[i] = srv.URL
+	}
+	return urls
+}
+
+func TestClusterMajorityDeclaresDeath(t *testing.T) {
+	target := types.NewNodeID(42)
+	self := oracle.New(types.NewNodeID(1))
+	reportDead(self, target)
+	oracle.NewCluster(self, startCluster(t, 2, target))
+
+	if err := self.DeclareDeath(target, true); err != nil {
+		t.Fatalf("2 of 3 accepted but death not declared: %v", err)
+	}
+	if !self.Query(target).Dead {
+		t.Error("target not dead after consensus")
+	}
+}
+
+func TestClusterMinorityBlocksDeath(t *testing.T) {
+	target := types.NewNodeID(42)
+	self := oracle.New(types.NewNodeID(1))
+	reportDead(self, target)
+	oracle.NewCluster(self, startCluster(t, 1, target))
+
+	if err := self.DeclareDeath(target, true); !errors.Is(err, oracle.ErrNoConsensus) {
+		t.Fatalf("1 of 3 accepted: expected ErrNoConsensus, got %v", err)
+	}
+	if self.Query(target).Dead {
+		t.Error("target declared dead without majority")
+	}
+}
+
+func TestClusterVotesOnTheProposedGeneration(t *testing.T) {
+	first, reborn := types.NewNodeID(42), types.WithGeneration(42, 1)
+
+	// every peer buried the first generation and sees its successor alive
+	urls := make([]string, 3)
+	for i := range urls {
+		s := NewServer(uint64(i + 2))
+		reportDead(s.oracle, first)
+		if err := s.oracle.DeclareDeath(first, true); err != nil {
+			t.Fatal(err)
+		}
+		s.oracle.ReceiveReport(types.NewNodeID(10), reborn, types.MustBelief(0.8, 0.1, 0.1))
+		srv := httptest.NewServer(s.Handler())
+		t.Cleanup(srv.Close)
+		urls[i] = srv.URL
+	}
+
+	self := oracle.New(types.NewNodeID(1))
+	reportDead(self, reborn)
+	oracle.NewCluster(self, urls)
+	if err := self.DeclareDeath(reborn, true); !errors.Is(err, oracle.ErrNoConsensus) {
+		t.Fatalf("peers voted on the dead first generation: got %v, want ErrNoConsensus", err)
+	}
+
+	// and accept once they see the successor dead as well
+	self = oracle.New(types.NewNodeID(1))
+	reportDead(self, reborn)
+	oracle.NewCluster(self, startCluster(t, 2, reborn))
+	if err := self.DeclareDeath(reborn, true); err != nil {
+		t.Fatalf("2 of 3 saw the successor dead: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+// SSE defaults
+const (
+	// DefaultSSEHeartbeat keeps idle connections alive through proxies
+	DefaultSSEHeartbeat = 30 * time.Second
+	// DefaultSSEIdleTimeout closes streams with no belief changes
+	DefaultSSEIdleTimeout = 5 * time.Minute
+)
+
+// BeliefEventResponse is the JSON payload of a streamed belief change
+type BeliefEventResponse struct {
+	Target          uint64  `json:"target"`
+	AliveConfidence float64 `json:"alive_confidence"`
+	DeadConfidence  float64 `json:"dead_confidence"`
+	Unknown         float64 `json:"unknown"`
+	Dominant        string  `json:"dominant"`
+	Timestamp       uint64  `json:"timestamp"`
+}
+
+// WithSSEHeartbeat sets how often an empty comment is sent on idle streams
+func (s *Server) WithSSEHeartbeat(d time.Duration) *Server {
+	s.sseHeartbeat = d
+	return s
+}
+
+// WithSSEIdleTimeout sets how long a stream may go without events before it is closed
+func (s *Server) WithSSEIdleTimeout(d time.Duration) *Server {
+	s.sseIdleTimeout = d
+	return s
+}
+
+// handleEvents streams belief changes as server-sent events
+// GET /events?target=N
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	targetStr := r.URL.Query().Get("target")
+	if targetStr == "" {
+		http.Error(w, "missing target parameter", http.StatusBadRequest)
+		return
+	}
+	targetID, err := strconv.ParseUint(targetStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid target id", http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events, cancel := s.oracleFor(r).Subscribe(types.NewNodeID(targetID), 0)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(s.sseHeartbeat)
+	defer heartbeat.Stop()
+	idle := time.NewTimer(s.sseIdleTimeout)
+	defer idle.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-idle.C:
+			return
+
+		case <-heartbeat.C:
+			if _, err := w.Write([]byte(": heartbeat\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(BeliefEventResponse{
+				Target:          targetID,
+				AliveConfidence: ev.New.Alive().Value(),
+				DeadConfidence:  ev.New.Dead().Value(),
+				Unknown:         ev.New.Unknown().Value(),
+				Dominant:        ev.New.Dominant().String(),
+				Timestamp:       ev.Timestamp.Value(),
+			})
+			if err != nil {
+				return
+			}
+			if _, err := w.Write([]byte("data: " + string(data) + "\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+
+			if !idle.Stop() {
+				<-idle.C
+			}
+			idle.Reset(s.sseIdleTimeout)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+// startHTTP2 serves the API over TLS with HTTP/2 enabled
+func startHTTP2(t *testing.T, s *Server) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(s.Handler())
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// openStream connects to /events and forwards each received line
+// The stream is torn down before the server closes
+func openStream(t *testing.T, ctx context.Context, srv *httptest.Server, target string) <-chan string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(ctx)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events?target="+target, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ProtoMajor != 2 {
+		t.Errorf("expected HTTP/2, got %s", resp.Proto)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	lines := make(chan string, 64)
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	go func() {
+		defer close(done)
+		defer close(lines)
+		defer resp.Body.Close()
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			if sc.Text() == "" {
+				continue
+			}
+			select {
+			case lines <- sc.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return lines
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestEventsStreamBeliefChanges(t *testing.T) {
+	s := NewServer(1)
+	srv := startHTTP2(t, s)
+	target := types.NewNodeID(42)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lines := openStream(t, ctx, srv, "42")
+	waitFor(t, func() bool { return s.oracle.SubscriberCount(target) == 1 })
+
+	s.oracle.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.8, 0.1, 0.1))
+
+	select {
+	case line := <-lines:
+		if !strings.HasPrefix(line, "data: ") || !strings.Contains(line, `"dominant":"ALIVE"`) {
+			t.Errorf("unexpected event line %q", line)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event received")
+	}
+}
+
+func TestEventsClientDisconnect(t *testing.T) {
+	s := NewServer(1)
+	srv := startHTTP2(t, s)
+	target := types.NewNodeID(42)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	openStream(t, ctx, srv, "42")
+	openStream(t, context.Background(), srv, "42")
+	waitFor(t, func() bool { return s.oracle.SubscriberCount(target) == 2 })
+
+	cancel()
+	waitFor(t, func() bool { return s.oracle.SubscriberCount(target) == 1 })
+}
+
+func TestEventsHeartbeat(t *testing.T) {
+	s := NewServer(1).WithSSEHeartbeat(20 * time.Millisecond)
+	srv := startHTTP2(t, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	start := time.Now()
+	lines := openStream(t, ctx, srv, "42")
+
+	for i := 0; i < 3; i++ {
+		select {
+		case line := <-lines:
+			if line != ": heartbeat" {
+				t.Fatalf("expected heartbeat, got %q", line)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("heartbeat %d not received", i+1)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("heartbeats arrived faster than scheduled: %v", elapsed)
+	}
+}
+
+func TestEventsIdleTimeout(t *testing.T) {
+	s := NewServer(1).WithSSEIdleTimeout(50 * time.Millisecond)
+	srv := startHTTP2(t, s)
+
+	lines := openStream(t, context.Background(), srv, "42")
+	select {
+	case _, ok := <-lines:
+		if ok {
+			t.Fatal("expected stream to close without events")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("idle stream was not closed")
+	}
+	waitFor(t, func() bool { return s.oracle.SubscriberCount(types.NewNodeID(42)) == 0 })
+}
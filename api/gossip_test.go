@@ -0,0 +1,165 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/styx-oracle/styx/evidence"
+	"github.com/styx-oracle/styx/oracle"
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+func TestGossipRelayForwardsReportsBetweenOracles(t *testing.T) {
+	a, b := NewServer(1), NewServer(2)
+	srvA := httptest.NewServer(a.Handler())
+	defer srvA.Close()
+	srvB := httptest.NewServer(b.Handler())
+	defer srvB.Close()
+
+	// Peers gossip both ways, so the echo back to A must be dropped as a loop
+	relayA, relayB := oracle.NewGossipRelay(srvB.URL), oracle.NewGossipRelay(srvA.URL)
+	a.oracle.WithGossipRelay(relayA)
+	b.oracle.WithGossipRelay(relayB)
+
+	resp, err := http.Post(srvA.URL+"/report", "application/json", strings.NewReader(validReport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	target := types.NewNodeID(42)
+	waitFor(t, func() bool { return b.oracle.Query(target).WitnessCount == 1 })
+
+	relayA.Wait()
+	relayB.Wait()
+	if n := a.oracle.Query(target).WitnessCount; n != 1 {
+		t.Errorf("origin witness count %d after gossip echo, want 1", n)
+	}
+}
+
+func TestGossipRelayForwardsRepeatedBeliefs(t *testing.T) {
+	a, b := NewServer(1), NewServer(2)
+	srvB := httptest.NewServer(b.Handler())
+	defer srvB.Close()
+
+	relay := oracle.NewGossipRelay(srvB.URL)
+	a.oracle.WithGossipRelay(relay)
+
+	// a steady witness reporting the same belief twice
+	postReport(a.Handler(), validReport)
+	postReport(a.Handler(), validReport)
+	relay.Wait()
+
+	if n := b.oracle.ReportCount(types.NewNodeID(42)); n != 2 {
+		t.Errorf("peer kept %d of 2 observations", n)
+	}
+}
+
+func TestGossipRelayStopsAtMaxHops(t *testing.T) {
+	a, b := NewServer(1), NewServer(2)
+	srvB := httptest.NewServer(b.Handler())
+	defer srvB.Close()
+
+	relay := oracle.NewGossipRelay(srvB.URL).WithMaxHops(0)
+	a.oracle.WithGossipRelay(relay)
+
+	postReport(a.Handler(), validReport)
+	relay.Wait()
+
+	if n := b.oracle.Query(types.NewNodeID(42)).WitnessCount; n != 0 {
+		t.Errorf("report forwarded past max hops, peer witness count %d", n)
+	}
+}
+
+func TestGossipRelayKeepsGenerations(t *testing.T) {
+	a, b := NewServer(1), NewServer(2)
+	srvB := httptest.NewServer(b.Handler())
+	defer srvB.Close()
+
+	relay := oracle.NewGossipRelay(srvB.URL)
+	a.oracle.WithGossipRelay(relay)
+
+	w, target := types.WithGeneration(10, 2), types.WithGeneration(42, 1)
+	a.oracle.ReceiveReport(w, target, types.MustBelief(0.8, 0.1, 0.1))
+	relay.Wait()
+
+	if n := b.oracle.Query(target).WitnessCount; n != 1 {
+		t.Errorf("peer witness count for the reborn target %d, want 1", n)
+	}
+	if n := b.oracle.Query(types.NewNodeID(42)).WitnessCount; n != 0 {
+		t.Errorf("relayed report landed on generation 0: %d witnesses", n)
+	}
+}
+
+func TestGossipRelayKeepsBasis(t *testing.T) {
+	a, b := NewServer(1), NewServer(2)
+	srvB := httptest.NewServer(b.Handler())
+	defer srvB.Close()
+
+	relay := oracle.NewGossipRelay(srvB.URL)
+	a.oracle.WithGossipRelay(relay)
+
+	postReport(a.Handler(), `{"version":3,"basis":"timeout","witness":10,"target":42,"alive":0.05,"dead":0.9,"unknown":0.05}`)
+	relay.Wait()
+
+	// the peer holds the silence as timeout evidence, not as a dead report
+	exp := b.oracle.Explain(types.NewNodeID(42))
+	if len(exp.Sources) != 1 || len(exp.Sources[0].Evidence) != 1 || exp.Sources[0].Evidence[0].Kind != evidence.KindTimeout {
+		t.Fatalf("peer evidence %+v, want one timeout", exp.Sources)
+	}
+
+	// a second copy over another path counts once
+	postReport(b.Handler(), `{"version":3,"basis":"timeout","relay_path":[3],"witness":10,"target":42,"alive":0.05,"dead":0.9,"unknown":0.05}`)
+	if n := len(b.oracle.Explain(types.NewNodeID(42)).Sources[0].Evidence); n != 1 {
+		t.Errorf("duplicate relayed timeout stored: %d records", n)
+	}
+}
+
+func TestGossipRelayForwardsSignedReports(t *testing.T) {
+	a, b := NewServer(1), NewServer(2)
+	srvB := httptest.NewServer(b.Handler())
+	defer srvB.Close()
+
+	relay := oracle.NewGossipRelay(srvB.URL)
+	a.oracle.WithGossipRelay(relay)
+
+	w, target := types.NewNodeID(10), types.NewNodeID(42)
+	pub, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []*Server{a, b} {
+		if err := s.oracle.SetPublicKey(w, pub); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	signed := witness.WitnessReport{Witness: w, Target: target, Belief: types.MustBelief(0.8, 0.1, 0.1), Timestamp: 7}.Sign(key)
+	body := fmt.Sprintf(`{"version":6,"witness":10,"target":42,"alive":0.8,"dead":0.1,"unknown":0.1,"timestamp":7,"signature":%q}`,
+		base64.StdEncoding.EncodeToString(signed.Signature))
+	if rec := postReport(a.Handler(), body); rec.Code != http.StatusAccepted {
+		t.Fatalf("signed report: status %d: %s", rec.Code, rec.Body.String())
+	}
+	relay.Wait()
+
+	if n := b.oracle.ReportCount(target); n != 1 {
+		t.Fatalf("keyed peer kept %d relayed reports, want 1", n)
+	}
+	if clock := b.oracle.Clock(); clock <= 7 {
+		t.Errorf("peer clock %d not past the origin timestamp 7", clock)
+	}
+
+	// a relayed copy whose belief was changed on the way is dropped
+	forged := `{"version":6,"relay_path":[3],"witness":10,"target":42,"alive":0.1,"dead":0.8,"unknown":0.1,"timestamp":8,"signature":` +
+		fmt.Sprintf("%q}", base64.StdEncoding.EncodeToString(signed.Signature))
+	postReport(b.Handler(), forged)
+	if n := b.oracle.ReportCount(target); n != 1 {
+		t.Errorf("forged relay stored, %d reports", n)
+	}
+}
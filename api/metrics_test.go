@@ -0,0 +1,113 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/styx-oracle/styx/metrics"
+	"github.com/styx-oracle/styx/types"
+)
+
+// text exposition format 0.0.4
+var (
+	helpLine   = regexp.MustCompile(`^# HELP ([a-zA-Z_:][a-zA-Z0-9_:]*) (.*)$`)
+	typeLine   = regexp.MustCompile(`^# TYPE ([a-zA-Z_:][a-zA-Z0-9_:]*) (counter|gauge|histogram|summary|untyped)$`)
+	sampleLine = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)` +
+		`(\{[a-zA-Z_][a-zA-Z0-9_]*="(?:[^"\\\n]|\\.)*"(?:,[a-zA-Z_][a-zA-Z0-9_]*="(?:[^"\\\n]|\\.)*")*\})?` +
+		` ([-+]?(?:[0-9]*\.?[0-9]+(?:[eE][-+]?[0-9]+)?|Inf|NaN))( -?[0-9]+)?$`)
+)
+
+func TestMetricsPrometheusFormat(t *testing.T) {
+	s := NewServer(1).WithMetrics(&metrics.Metrics{})
+	h := s.Handler()
+	postReport(h, validReport)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/query?target=42", nil))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body := rec.Body.String()
+	if !strings.HasSuffix(body, "\n") {
+		t.Fatal("output must end with a newline")
+	}
+
+	seen := make(map[string]bool)
+	var family, helped, kind string
+	samples := make(map[string]int)
+	for i, line := range strings.Split(strings.TrimSuffix(body, "\n"), "\n") {
+		if m := helpLine.FindStringSubmatch(line); m != nil {
+			if seen[m[1]] {
+				t.Errorf("line %d: family %s declared twice", i+1, m[1])
+			}
+			seen[m[1]] = true
+			helped, family = m[1], ""
+			continue
+		}
+		if m := typeLine.FindStringSubmatch(line); m != nil {
+			if m[1] != helped {
+				t.Errorf("line %d: TYPE %s not preceded by its HELP", i+1, m[1])
+			}
+			family, kind = m[1], m[2]
+			continue
+		}
+		m := sampleLine.FindStringSubmatch(line)
+		if m == nil {
+			t.Errorf("line %d: malformed %q", i+1, line)
+			continue
+		}
+		name := m[1]
+		if kind == "summary" || kind == "histogram" {
+			name = strings.TrimSuffix(strings.TrimSuffix(name, "_sum"), "_count")
+		}
+		if name != family {
+			t.Errorf("line %d: sample %s outside its family (%s)", i+1, m[1], family)
+		}
+		samples[name]++
+	}
+	for name := range seen {
+		if samples[name] == 0 {
+			t.Errorf("family %s has no samples", name)
+		}
+	}
+
+	labels := `{namespace="default",target="` + types.NewNodeID(42).String() + `"} `
+	for _, want := range []string{
+		"styx_up 1\n",
+		"styx_current_belief_alive" + labels,
+		"styx_current_belief_dead" + labels,
+		"styx_current_belief_unknown" + labels,
+		`styx_queries_total{namespace="default"} `,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q", want)
+		}
+	}
+	if strings.Contains(body, "styx_node_belief") {
+		t.Error("second belief family in the output")
+	}
+}
+
+func TestMetricsScopedToNamespace(t *testing.T) {
+	h := NewServer(1).Handler()
+	for _, ns := range []string{"a", "b"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/witnesses?ns="+ns, strings.NewReader(`{"id":10}`)))
+	}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/query?target=42&ns=a", nil))
+
+	scrape := func(ns string) string {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics?ns="+ns, nil))
+		return rec.Body.String()
+	}
+	if body := scrape("a"); !strings.Contains(body, `styx_queries_total{namespace="a"} 1`+"\n") {
+		t.Errorf("namespace a lost its query:\n%s", body)
+	}
+	if body := scrape("b"); !strings.Contains(body, `styx_queries_total{namespace="b"} 0`+"\n") {
+		t.Errorf("namespace b counts another namespace's query:\n%s", body)
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/styx-oracle/styx/observer"
+	"github.com/styx-oracle/styx/types"
+)
+
+// ProberStatsResponse is the JSON response for observer stats
+type ProberStatsResponse struct {
+	Target uint64 `json:"target"`
+
+	JitterSamples int     `json:"jitter_samples"`
+	MeanJitter    float64 `json:"mean_jitter"`
+	MaxJitter     float64 `json:"max_jitter"`
+	JitterFactor  float64 `json:"jitter_factor"`
+
+	EntropySamples int     `json:"entropy_samples"`
+	MeanLatencyMS  float64 `json:"mean_latency_ms"`
+	MinLatencyMS   float64 `json:"min_latency_ms"`
+	MaxLatencyMS   float64 `json:"max_latency_ms"`
+	Entropy        float64 `json:"entropy"`
+}
+
+// WithProber attaches a local prober so its stats are exposed over HTTP
+func (s *Server) WithProber(p *observer.Prober) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prober = p
+	return s
+}
+
+func (s *Server) getProber() *observer.Prober {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.prober
+}
+
+// handleObserverStats returns jitter and per-target entropy stats
+// GET /observer/stats?target=N
+func (s *Server) handleObserverStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	p := s.getProber()
+	if p == nil {
+		http.Error(w, "no prober attached", http.StatusNotFound)
+		return
+	}
+
+	targetStr := r.URL.Query().Get("target")
+	if targetStr == "" {
+		http.Error(w, "missing target parameter", http.StatusBadRequest)
+		return
+	}
+	targetID, err := strconv.ParseUint(targetStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid target id", http.StatusBadRequest)
+		return
+	}
+
+	stats := p.Stats(types.NewNodeID(targetID))
+	resp := ProberStatsResponse{
+		Target:         targetID,
+		JitterSamples:  stats.Jitter.SampleCount,
+		MeanJitter:     stats.Jitter.MeanJitter,
+		MaxJitter:      stats.Jitter.MaxJitter,
+		JitterFactor:   stats.Jitter.JitterFactor,
+		EntropySamples: stats.Entropy.SampleCount,
+		MeanLatencyMS:  float64(stats.Entropy.MeanLatency.Microseconds()) / 1000,
+		MinLatencyMS:   float64(stats.Entropy.MinLatency.Microseconds()) / 1000,
+		MaxLatencyMS:   float64(stats.Entropy.MaxLatency.Microseconds()) / 1000,
+		Entropy:        stats.Entropy.Entropy,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handleObserverHealth returns the health of the attached prober
+// GET /observer/health
+func (s *Server) handleObserverHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	p := s.getProber()
+	if p == nil {
+		http.Error(w, "no prober attached", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p.HealthReport())
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/styx-oracle/styx/observer"
+	"github.com/styx-oracle/styx/types"
+)
+
+func TestObserverStatsEndpoint(t *testing.T) {
+	h := NewServer(1).Handler()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/observer/stats?target=2", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without prober, got %d", rec.Code)
+	}
+
+	p := observer.NewProber(types.NewNodeID(1), time.Second)
+	p.SetProbeFunc(func(target types.NodeID) observer.ProbeResult {
+		return observer.ProbeResult{Target: target, Success: true, Latency: 5 * time.Millisecond}
+	})
+	for i := 0; i < 4; i++ {
+		p.Probe(types.NewNodeID(2))
+	}
+
+	h = NewServer(1).WithProber(p).Handler()
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/observer/stats?target=2", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var resp ProberStatsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.JitterSamples != 4 || resp.EntropySamples != 4 {
+		t.Errorf("unexpected sample counts: %+v", resp)
+	}
+	if resp.MeanLatencyMS != 5 {
+		t.Errorf("unexpected mean latency: %f", resp.MeanLatencyMS)
+	}
+}
+
+func TestObserverHealthEndpoint(t *testing.T) {
+	h := NewServer(1).Handler()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/observer/health", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without prober, got %d", rec.Code)
+	}
+
+	p := observer.NewProber(types.NewNodeID(1), time.Second)
+	p.SetProbeFunc(func(target types.NodeID) observer.ProbeResult {
+		return observer.ProbeResult{Target: target, Success: true, Latency: 5 * time.Millisecond}
+	})
+	for i := 0; i < 3; i++ {
+		p.Probe(types.NewNodeID(2))
+		p.Probe(types.NewNodeID(3))
+	}
+
+	h = NewServer(1).WithProber(p).Handler()
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/observer/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var resp observer.ObserverHealth
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.TrackedTargets != 2 || resp.AliveTargets != 2 {
+		t.Errorf("unexpected health: %+v", resp)
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// PartitionTransitionResponse is one partition state change
+type PartitionTransitionResponse struct {
+	From         string    `json:"from"`
+	To           string    `json:"to"`
+	At           time.Time `json:"at"`
+	Disagreement float64   `json:"disagreement"`
+	Target       uint64    `json:"target"`
+}
+
+// PartitionHistoryResponse is the JSON response for partition history
+type PartitionHistoryResponse struct {
+	State       string                        `json:"state"`
+	DurationMS  int64                         `json:"duration_ms"`
+	Transitions []PartitionTransitionResponse `json:"transitions"`
+}
+
+// handlePartitionHistory returns recent partition state transitions
+// GET /partition/history?limit=N, no limit returns everything kept
+func (s *Server) handlePartitionHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	o := s.oracleFor(r)
+	history := o.PartitionHistory(limit)
+	resp := PartitionHistoryResponse{
+		State:       o.PartitionState().String(),
+		DurationMS:  o.PartitionDuration().Milliseconds(),
+		Transitions: make([]PartitionTransitionResponse, len(history)),
+	}
+	for i, t := range history {
+		resp.Transitions[i] = PartitionTransitionResponse{
+			From:         t.From.String(),
+			To:           t.To.String(),
+			At:           t.At,
+			Disagreement: t.Disagreement,
+			Target:       t.Target.Base,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
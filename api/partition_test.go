@@ -0,0 +1,45 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPartitionHistoryEndpoint(t *testing.T) {
+	h := NewServer(1).Handler()
+	for _, body := range []string{
+		`{"witness":1,"target":42,"alive":0.9,"dead":0.05,"unknown":0.05}`,
+		`{"witness":2,"target":42,"alive":0.9,"dead":0.05,"unknown":0.05}`,
+		`{"witness":3,"target":42,"alive":0.05,"dead":0.9,"unknown":0.05}`,
+		`{"witness":4,"target":42,"alive":0.05,"dead":0.9,"unknown":0.05}`,
+	} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/report", strings.NewReader(body)))
+	}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/query?target=42", nil))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/partition/history?limit=5", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var resp PartitionHistoryResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.State != "CONFIRMED_PARTITION" || len(resp.Transitions) != 1 {
+		t.Fatalf("unexpected history %+v", resp)
+	}
+	if tr := resp.Transitions[0]; tr.From != "NO_PARTITION" || tr.To != "CONFIRMED_PARTITION" || tr.Target != 42 {
+		t.Errorf("unexpected transition %+v", tr)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/partition/history?limit=x", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("bad limit: expected 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/styx-oracle/styx/evidence"
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+// Report schema versions accepted by POST /report
+// v1 is everything before versioning: belief, quality and relay_path
+// v2 adds alive_interval, the witness's own credible interval
+// v3 adds basis, what the witness based its belief on
+// v4 adds the generations of the witness, the target and the relay path
+// v5 adds timestamp, the logical time the observation was first given
+// v6 adds signature, the witness's Ed25519 signature of the report
+const (
+	ReportSchemaV1      = 1
+	ReportSchemaV2      = 2
+	ReportSchemaV3      = 3
+	ReportSchemaV4      = 4
+	ReportSchemaV5      = 5
+	ReportSchemaV6      = 6
+	CurrentReportSchema = ReportSchemaV6
+)
+
+// Report bases accepted in the v3 basis field
+const (
+	BasisDirect  = "direct"
+	BasisCausal  = "causal"
+	BasisTimeout = "timeout"
+	BasisWitness = "witness"
+)
+
+var (
+	// ErrUnsupportedSchema is returned for report versions this server cant read
+	ErrUnsupportedSchema = errors.New("unsupported report schema version")
+	// ErrUnknownBasis is returned for a basis not listed above
+	ErrUnknownBasis = errors.New("unknown report basis")
+	// ErrRelayGenerations is returned when relay_generations does not match relay_path
+	ErrRelayGenerations = errors.New("relay_generations must match relay_path")
+)
+
+var basisKinds = map[string]evidence.EvidenceKind{
+	BasisDirect:  evidence.KindDirectResponse,
+	BasisCausal:  evidence.KindCausalEvent,
+	BasisTimeout: evidence.KindTimeout,
+	BasisWitness: evidence.KindWitnessReport,
+}
+
+// schemaVersion returns the version a request was written against
+// a missing version is a v1 client that predates the field
+func (req ReportRequest) schemaVersion() (int, error) {
+	switch {
+	case req.Version == 0:
+		return ReportSchemaV1, nil
+	case req.Version < 0 || req.Version > CurrentReportSchema:
+		return 0, fmt.Errorf("%w: %d", ErrUnsupportedSchema, req.Version)
+	}
+	return req.Version, nil
+}
+
+// witnessReport converts a request into the report the oracle ingests
+// fields newer than the request's version are ignored, so a v1 client
+// sending a stray alive_interval is read exactly as before
+func (req ReportRequest) witnessReport(belief types.Belief) (witness.WitnessReport, error) {
+	version, err := req.schemaVersion()
+	if err != nil {
+		return witness.WitnessReport{}, err
+	}
+
+	report := witness.WitnessReport{
+		Witness: types.NewNodeID(req.Witness),
+		Target:  types.NewNodeID(req.Target),
+		Belief:  belief,
+		Quality: req.Quality,
+	}
+	if len(req.RelayPath) > 0 {
+		report.RelayPath = make([]types.NodeID, len(req.RelayPath))
+		for i, hop := range req.RelayPath {
+			report.RelayPath[i] = types.NewNodeID(hop)
+		}
+	}
+	if version >= ReportSchemaV2 {
+		report.AliveInterval = req.AliveInterval
+	}
+	if version >= ReportSchemaV4 {
+		if len(req.RelayGenerations) > 0 && len(req.RelayGenerations) != len(req.RelayPath) {
+			return witness.WitnessReport{}, ErrRelayGenerations
+		}
+		report.Witness.Generation = req.WitnessGeneration
+		report.Target.Generation = req.TargetGeneration
+		for i, gen := range req.RelayGenerations {
+			report.RelayPath[i].Generation = gen
+		}
+	}
+	if version >= ReportSchemaV5 {
+		report.Timestamp = styxtime.LogicalTimestamp(req.Timestamp)
+	}
+	if version >= ReportSchemaV6 {
+		report.Signature = req.Signature
+	}
+	return report, nil
+}
+
+// basisKind returns the evidence kind the report is based on
+// before v3, or without a basis, it is a generic witness report
+func (req ReportRequest) basisKind() (evidence.EvidenceKind, error) {
+	if req.Version < ReportSchemaV3 || req.Basis == "" {
+		return evidence.KindWitnessReport, nil
+	}
+	kind, ok := basisKinds[req.Basis]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownBasis, req.Basis)
+	}
+	return kind, nil
+}
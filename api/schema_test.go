@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/styx-oracle/styx/oracle"
+)
+
+// ingest posts reports to a fresh server and returns the query for target 42
+func ingest(t *testing.T, bodies ...string) QueryResponse {
+	t.Helper()
+	h := NewServer(1).Handler()
+	for _, body := range bodies {
+		if rec := postReport(h, body); rec.Code != http.StatusAccepted {
+			t.Fatalf("POST %s: status %d: %s", body, rec.Code, rec.Body.String())
+		}
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/query?target=42", nil))
+	var resp QueryResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode query: %v", err)
+	}
+	return resp
+}
+
+func TestReportSchemaV1AndV2(t *testing.T) {
+	const confident = `{"witness":10,"target":42,"alive":0.7,"dead":0.2,"unknown":0.1}`
+
+	v1 := ingest(t, confident, `{"witness":11,"target":42,"alive":0.2,"dead":0.3,"unknown":0.5}`)
+	if v1.WitnessCount != 2 {
+		t.Fatalf("v1 witness count = %d, want 2", v1.WitnessCount)
+	}
+
+	// a v1 body carrying alive_interval is read exactly as before
+	stray := ingest(t, confident, `{"witness":11,"target":42,"alive":0.2,"dead":0.3,"unknown":0.5,"alive_interval":[0,0.8]}`)
+	if stray.AliveConfidence != v1.AliveConfidence {
+		t.Errorf("v1 honored alive_interval: %f != %f", stray.AliveConfidence, v1.AliveConfidence)
+	}
+
+	// v2 honors it: the unsure witness counts for less
+	v2 := ingest(t, confident, `{"version":2,"witness":11,"target":42,"alive":0.2,"dead":0.3,"unknown":0.5,"alive_interval":[0,0.8]}`)
+	if v2.WitnessCount != 2 {
+		t.Fatalf("v2 witness count = %d, want 2", v2.WitnessCount)
+	}
+	if v2.AliveConfidence <= v1.AliveConfidence {
+		t.Errorf("v2 alive %f should exceed v1 alive %f", v2.AliveConfidence, v1.AliveConfidence)
+	}
+}
+
+func TestReportSchemaUnsupportedVersion(t *testing.T) {
+	h := NewServer(1).Handler()
+	for _, body := range []string{
+		`{"version":7,"witness":10,"target":42,"alive":0.8,"dead":0.1,"unknown":0.1}`,
+		`{"version":-1,"witness":10,"target":42,"alive":0.8,"dead":0.1,"unknown":0.1}`,
+	} {
+		if rec := postReport(h, body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", body, rec.Code)
+		}
+	}
+}
+
+func TestReportSchemaV4Generations(t *testing.T) {
+	// a report about the reborn target is not about generation 0
+	if got := ingest(t, `{"version":4,"witness":10,"target":42,"target_generation":1,"alive":0.8,"dead":0.1,"unknown":0.1}`); got.WitnessCount != 0 {
+		t.Errorf("generation 1 report counted for generation 0: %d witnesses", got.WitnessCount)
+	}
+	// before v4 the generations are ignored
+	if got := ingest(t, `{"version":3,"witness":10,"target":42,"target_generation":1,"alive":0.8,"dead":0.1,"unknown":0.1}`); got.WitnessCount != 1 {
+		t.Errorf("v3 honored target_generation: %d witnesses", got.WitnessCount)
+	}
+
+	// the reborn target is queried by its generation
+	h := NewServer(3).Handler()
+	postReport(h, `{"version":4,"witness":10,"target":42,"target_generation":1,"alive":0.8,"dead":0.1,"unknown":0.1}`)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/query?target=42&target_generation=1", nil))
+	var reborn QueryResponse
+	if err := json.NewDecoder(rec.Body).Decode(&reborn); err != nil || reborn.WitnessCount != 1 {
+		t.Errorf("query for generation 1: %d witnesses, %v", reborn.WitnessCount, err)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/query?target=42&target_generation=x", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid target_generation: status %d, want 400", rec.Code)
+	}
+
+	body := `{"version":4,"witness":10,"target":42,"alive":0.8,"dead":0.1,"unknown":0.1,"relay_path":[1,2],"relay_generations":[1]}`
+	if rec := postReport(NewServer(3).Handler(), body); rec.Code != http.StatusBadRequest {
+		t.Errorf("relay_generations shorter than relay_path: status %d, want 400", rec.Code)
+	}
+}
+
+func TestReportBasisFinalityEligibility(t *testing.T) {
+	deadReports := func(basis string) []string {
+		var bodies []string
+		for _, b := range [][4]float64{{10, 0, 0.95, 0.05}, {11, 0.04, 0.9, 0.06}, {12, 0, 0.9, 0.1}} {
+			bodies = append(bodies, fmt.Sprintf(
+				`{"version":3,"basis":%q,"witness":%d,"target":42,"alive":%g,"dead":%g,"unknown":%g}`,
+				basis, int(b[0]), b[1], b[2], b[3]))
+		}
+		return bodies
+	}
+	vote := func(h http.Handler) bool {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/consensus/death", strings.NewReader(`{"target":42}`)))
+		var v oracle.DeathVote
+		if err := json.NewDecoder(rec.Body).Decode(&v); err != nil {
+			t.Fatalf("decode vote: %v", err)
+		}
+		return v.Accept
+	}
+
+	for _, tt := range []struct {
+		basis    string
+		eligible bool
+	}{
+		{BasisWitness, true},
+		{BasisTimeout, false}, // P15: silence alone cannot kill
+		{BasisDirect, false},  // a direct response is proof of life, whatever the belief
+		{BasisCausal, false},
+	} {
+		h := NewServer(1).Handler()
+		for _, body := range deadReports(tt.basis) {
+			if rec := postReport(h, body); rec.Code != http.StatusAccepted {
+				t.Fatalf("%s: status %d: %s", tt.basis, rec.Code, rec.Body.String())
+			}
+		}
+		if got := vote(h); got != tt.eligible {
+			t.Errorf("%s basis: finality vote %v, want %v", tt.basis, got, tt.eligible)
+		}
+	}
+
+	// a relayed timeout is still only silence
+	h := NewServer(1).Handler()
+	for _, body := range deadReports(BasisTimeout) {
+		relayed := strings.Replace(body, `"version":3,`, `"version":3,"relay_path":[7],`, 1)
+		if rec := postReport(h, relayed); rec.Code != http.StatusAccepted {
+			t.Fatalf("relayed timeout: status %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+	if vote(h) {
+		t.Error("relayed timeout basis reports made the target finality eligible")
+	}
+
+	if rec := postReport(NewServer(1).Handler(), `{"version":3,"basis":"rumour","witness":10,"target":42,"alive":0.8,"dead":0.1,"unknown":0.1}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown basis: status %d, want 400", rec.Code)
+	}
+}
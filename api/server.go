@@ -1,26 +1,94 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"sync"
+	"time"
 
+	"github.com/styx-oracle/styx/evidence"
+	"github.com/styx-oracle/styx/metrics"
+	"github.com/styx-oracle/styx/observer"
 	"github.com/styx-oracle/styx/oracle"
 	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
 )
 
+// DefaultMaxRequestBodySize limits request bodies (64KB)
+const DefaultMaxRequestBodySize int64 = 64 << 10
+
+// DefaultQueryTimeout bounds how long /query waits for the oracle
+const DefaultQueryTimeout = 500 * time.Millisecond
+
 // Server provides HTTP API for STYX Oracle
 type Server struct {
-	oracle *oracle.Oracle
-	mu     sync.RWMutex
+	oracle         *oracle.Oracle // default namespace
+	broker         *oracle.Broker
+	mu             sync.RWMutex
+	maxBodySize    int64
+	sseHeartbeat   time.Duration
+	sseIdleTimeout time.Duration
+	prober         *observer.Prober
+	queryTimeout   time.Duration
+	apiVersion     int
 }
 
 // NewServer creates a new API server
 func NewServer(selfID uint64) *Server {
+	broker := oracle.NewBroker(types.NewNodeID(selfID))
 	return &Server{
-		oracle: oracle.New(types.NewNodeID(selfID)),
+		oracle:         broker.GetOrCreate(oracle.DefaultNamespace),
+		broker:         broker,
+		maxBodySize:    DefaultMaxRequestBodySize,
+		sseHeartbeat:   DefaultSSEHeartbeat,
+		sseIdleTimeout: DefaultSSEIdleTimeout,
+		queryTimeout:   DefaultQueryTimeout,
+		apiVersion:     LatestAPIVersion,
+	}
+}
+
+// WithMaxRequestBodySize limits request bodies to the given number of bytes
+// Larger bodies are rejected with 413
+func (s *Server) WithMaxRequestBodySize(bytes int64) *Server {
+	s.maxBodySize = bytes
+	return s
+}
+
+// WithQueryTimeout bounds how long /query waits for the oracle
+// slower queries are answered with 503, d <= 0 waits forever
+func (s *Server) WithQueryTimeout(d time.Duration) *Server {
+	s.queryTimeout = d
+	return s
+}
+
+// WithMetrics sets the metrics of the default namespace, see
+// Oracle.WithMetrics, every other namespace keeps its Oracle's own
+func (s *Server) WithMetrics(m *metrics.Metrics) *Server {
+	s.oracle.WithMetrics(m)
+	return s
+}
+
+// WithMaxNamespaces bounds how many namespaces ?ns= may create, the default
+// namespace included (oracle.DefaultMaxNamespaces by default, 0 = no limit)
+// writes naming a new namespace past the limit are answered with 404
+func (s *Server) WithMaxNamespaces(n int) *Server {
+	s.broker.WithMaxNamespaces(n)
+	return s
+}
+
+// oracleFor picks the Oracle named by the ?ns= query parameter
+// no parameter means the default namespace
+// limitNamespaces already opened it
+func (s *Server) oracleFor(r *http.Request) *oracle.Oracle {
+	ns := r.URL.Query().Get("ns")
+	if ns == "" || ns == oracle.DefaultNamespace {
+		return s.oracle
 	}
+	return s.broker.GetOrCreate(ns)
 }
 
 // QueryResponse is the JSON response for queries
@@ -39,32 +107,123 @@ type QueryResponse struct {
 }
 
 // ReportRequest is the JSON request for reporting beliefs
+// RelayPath is set by gossiping peers, its length is the hop count
+// Quality is the observation quality in (0,1], omitted = full quality
+// Version is the report schema, omitted = ReportSchemaV1
+// AliveInterval is the v2 self reported [low, high] alive interval
+// Basis is the v3 reason for the belief, one of the Basis constants
+// the v4 generations tell reborn nodes apart, omitted = generation 0,
+// RelayGenerations is parallel to RelayPath
+// Timestamp is the v5 logical time of the observation
+// Signature is the v6 witness signature over witness, target, belief and
+// timestamp (witness.WitnessReport.Sign), base64 in JSON
 type ReportRequest struct {
-	Witness uint64  `json:"witness"`
-	Target  uint64  `json:"target"`
-	Alive   float64 `json:"alive"`
-	Dead    float64 `json:"dead"`
-	Unknown float64 `json:"unknown"`
+	Version           int        `json:"version,omitempty"`
+	Witness           uint64     `json:"witness"`
+	WitnessGeneration uint64     `json:"witness_generation,omitempty"`
+	Target            uint64     `json:"target"`
+	TargetGeneration  uint64     `json:"target_generation,omitempty"`
+	Alive             float64    `json:"alive"`
+	Dead              float64    `json:"dead"`
+	Unknown           float64    `json:"unknown"`
+	RelayPath         []uint64   `json:"relay_path,omitempty"`
+	RelayGenerations  []uint64   `json:"relay_generations,omitempty"`
+	Quality           float64    `json:"quality,omitempty"`
+	AliveInterval     [2]float64 `json:"alive_interval,omitempty"`
+	Basis             string     `json:"basis,omitempty"`
+	Timestamp         uint64     `json:"timestamp,omitempty"`
+	Signature         []byte     `json:"signature,omitempty"`
+}
+
+// ReportResponse acknowledges an accepted report
+// Timestamp is the Oracle logical time assigned to it
+type ReportResponse struct {
+	Status    string `json:"status"`
+	Timestamp uint64 `json:"timestamp"`
 }
 
 // Handler returns the HTTP handler
+// every route is served unversioned and under each mounted /vN/ prefix,
+// see WithAPIVersion
 func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
 
-	mux.HandleFunc("/query", s.handleQuery)
-	mux.HandleFunc("/report", s.handleReport)
-	mux.HandleFunc("/health", s.handleHealth)
-	mux.HandleFunc("/witnesses", s.handleWitnesses)
-	mux.HandleFunc("/metrics", s.handleMetrics)
+	s.mount(mux, "/query", s.handleQuery)
+	s.mount(mux, "/report", s.handleReport)
+	s.mount(mux, "/health", s.handleHealth)
+	s.mount(mux, "/witnesses", s.handleWitnesses)
+	s.mount(mux, "/witnesses/summary", s.handleWitnessSummary)
+	s.mount(mux, "/witnesses/{id}", s.handleWitness)
+	s.mount(mux, "/witnesses/bulk", s.handleWitnessesBulk)
+	s.mount(mux, "/metrics", s.handleMetrics)
+	s.mount(mux, "/events", s.handleEvents)
+	s.mount(mux, "/observer/stats", s.handleObserverStats)
+	s.mount(mux, "/observer/health", s.handleObserverHealth)
+	s.mount(mux, "/consensus/death", s.handleConsensusDeath)
+	s.mount(mux, "/partition/history", s.handlePartitionHistory)
+
+	return s.limitBody(s.limitNamespaces(mux))
+}
+
+// limitNamespaces creates the namespace a write names, up to the broker's
+// namespace limit, so clients cannot create Oracles without bound
+// only writes (POST) create namespaces, reads of a namespace nobody wrote
+// to are answered with 404 and leave nothing behind
+func (s *Server) limitNamespaces(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ns := r.URL.Query().Get("ns"); ns != "" && ns != oracle.DefaultNamespace {
+			if r.Method != http.MethodPost {
+				if _, ok := s.broker.Get(ns); !ok {
+					http.Error(w, fmt.Sprintf("unknown namespace %q", ns), http.StatusNotFound)
+					return
+				}
+			} else if _, err := s.broker.Open(ns); err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
 
-	return mux
+// limitBody caps request body size to prevent large payload attacks
+func (s *Server) limitBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body != nil && s.maxBodySize > 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, s.maxBodySize)
+		}
+		next.ServeHTTP(w, r)
+	})
 }
 
+// writeDecodeError reports a request body decode failure
+// bodies over the size limit get 413, anything else is bad json
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		w.Write([]byte(`{"error":"request body too large"}`))
+		return
+	}
+	http.Error(w, "invalid json", http.StatusBadRequest)
+}
+
+// handleMetrics serves the Prometheus text exposition format
+// server liveness and the metrics of the requested namespace, every one of
+// them labeled with the namespace
 func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
-	w.Write([]byte("# HELP styx_up STYX server is up\n"))
-	w.Write([]byte("# TYPE styx_up gauge\n"))
-	w.Write([]byte("styx_up 1\n"))
+	o := s.oracleFor(r)
+	families := []metrics.Family{
+		{Name: "styx_up", Type: metrics.TypeGauge, Help: "STYX server is up", Samples: []metrics.Sample{metrics.Int(1)}},
+	}
+	if m := o.Metrics(); m != nil {
+		ns := metrics.Label{Name: "namespace", Value: o.Namespace()}
+		families = append(families, metrics.WithLabel(m.Families(), ns)...)
+	}
+
+	w.Header().Set("Content-Type", metrics.ContentType)
+	metrics.WriteFamilies(w, families)
 }
 
 func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
@@ -84,8 +243,34 @@ func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid target id", http.StatusBadRequest)
 		return
 	}
+	// reborn targets are queried by generation, like v4 reports name them
+	var generation uint64
+	if genStr := r.URL.Query().Get("target_generation"); genStr != "" {
+		if generation, err = strconv.ParseUint(genStr, 10, 64); err != nil {
+			http.Error(w, "invalid target generation", http.StatusBadRequest)
+			return
+		}
+	}
 
-	result := s.oracle.Query(types.NewNodeID(targetID))
+	ctx := r.Context()
+	if s.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.queryTimeout)
+		defer cancel()
+	}
+
+	o := s.oracleFor(r)
+	result, err := o.QueryCtx(ctx, types.WithGeneration(targetID, generation))
+	if err != nil {
+		// a slow oracle is refused like any other answer it cant give in time
+		if m := o.Metrics(); m != nil && errors.Is(err, context.DeadlineExceeded) {
+			m.RecordQueryTimeout()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":"oracle timeout","refused":true}`))
+		return
+	}
 
 	resp := QueryResponse{
 		Target:          targetID,
@@ -102,7 +287,18 @@ func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	if apiVersion(r) < APIVersion2 {
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+	json.NewEncoder(w).Encode(QueryResponseV2{
+		QueryResponse:  resp,
+		Confidence:     result.Confidence.String(),
+		GradeRationale: result.GradeRationale,
+		AliveInterval:  result.AliveInterval,
+		DeadInterval:   result.DeadInterval,
+		Timestamp:      uint64(result.Timestamp),
+	})
 }
 
 func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
@@ -113,7 +309,7 @@ func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
 
 	var req ReportRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid json", http.StatusBadRequest)
+		writeDecodeError(w, err)
 		return
 	}
 
@@ -123,12 +319,47 @@ func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.oracle.ReceiveReport(
-		types.NewNodeID(req.Witness),
-		types.NewNodeID(req.Target),
-		belief,
-	)
+	report, err := req.witnessReport(belief)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	basis, err := req.basisKind()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
+	if len(report.RelayPath) > 0 {
+		s.handleRelayedReport(w, r, report, basis)
+		return
+	}
+
+	ts, err := s.oracleFor(r).ReceiveReportWithBasis(report, basis)
+	if err != nil {
+		code := http.StatusBadRequest
+		if errors.Is(err, oracle.ErrWitnessEvicted) {
+			code = http.StatusForbidden
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(ReportResponse{Status: "accepted", Timestamp: uint64(ts)})
+}
+
+// handleRelayedReport accepts a report gossiped by a peer oracle
+// loops and duplicates are expected in gossip, they are dropped not failed
+func (s *Server) handleRelayedReport(w http.ResponseWriter, r *http.Request, report witness.WitnessReport, basis evidence.EvidenceKind) {
+	err := s.oracleFor(r).ReceiveRelayedReportWithBasis(report, basis)
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"status": "dropped", "reason": err.Error()})
+		return
+	}
 	w.WriteHeader(http.StatusAccepted)
 	w.Write([]byte(`{"status":"accepted"}`))
 }
@@ -145,10 +376,10 @@ func (s *Server) handleWitnesses(w http.ResponseWriter, r *http.Request) {
 			ID uint64 `json:"id"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "invalid json", http.StatusBadRequest)
+			writeDecodeError(w, err)
 			return
 		}
-		s.oracle.RegisterWitness(types.NewNodeID(req.ID))
+		s.oracleFor(r).RegisterWitness(types.NewNodeID(req.ID))
 		w.WriteHeader(http.StatusCreated)
 		w.Write([]byte(`{"status":"registered"}`))
 		return
@@ -157,6 +388,64 @@ func (s *Server) handleWitnesses(w http.ResponseWriter, r *http.Request) {
 	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 }
 
+// handleWitness removes a witness and everything it reported
+// 204 when removed, 404 when it is not registered
+func (s *Server) handleWitness(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid witness id", http.StatusBadRequest)
+		return
+	}
+	if err := s.oracleFor(r).RemoveWitness(types.NewNodeID(id)); err != nil {
+		if errors.Is(err, oracle.ErrWitnessNotFound) {
+			http.Error(w, "witness not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleWitnessesBulk registers a JSON array of witness ids
+// idempotent, registered witnesses are left untouched
+func (s *Server) handleWitnessesBulk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var raw []uint64
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	ids := make([]types.NodeID, len(raw))
+	for i, id := range raw {
+		ids[i] = types.NewNodeID(id)
+	}
+	s.oracleFor(r).RegisterWitnesses(ids)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{"status": "registered", "count": len(ids)})
+}
+
+// handleWitnessSummary reports fleet wide witness trust health
+func (s *Server) handleWitnessSummary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.oracleFor(r).WitnessSummary())
+}
+
 // ListenAndServe starts the server
 func (s *Server) ListenAndServe(addr string) error {
 	return http.ListenAndServe(addr, s.Handler())
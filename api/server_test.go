@@ -0,0 +1,285 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/styx-oracle/styx/metrics"
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+const validReport = `{"witness":10,"target":42,"alive":0.8,"dead":0.1,"unknown":0.1}`
+
+// padReport left-pads a valid report with whitespace to exactly size bytes
+func padReport(size int) string {
+	return strings.Repeat(" ", size-len(validReport)) + validReport
+}
+
+func postReport(h http.Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/report", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReportEchoesTimestamp(t *testing.T) {
+	h := NewServer(1).Handler()
+
+	var last uint64
+	for i := 0; i < 3; i++ {
+		rec := postReport(h, validReport)
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("expected 202, got %d", rec.Code)
+		}
+		var resp ReportResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Status != "accepted" || resp.Timestamp <= last {
+			t.Errorf("report %d: got %+v after timestamp %d", i, resp, last)
+		}
+		last = resp.Timestamp
+	}
+}
+
+func TestMaxRequestBodySize(t *testing.T) {
+	const limit = 1024
+	h := NewServer(1).WithMaxRequestBodySize(limit).Handler()
+
+	if rec := postReport(h, padReport(100)); rec.Code != http.StatusAccepted {
+		t.Errorf("100 byte body: expected 202, got %d", rec.Code)
+	}
+
+	if rec := postReport(h, padReport(limit)); rec.Code != http.StatusAccepted {
+		t.Errorf("body at limit: expected 202, got %d", rec.Code)
+	}
+
+	rec := postReport(h, padReport(limit+1))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("body over limit: expected 413, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"error"`) {
+		t.Errorf("expected json error body, got %q", rec.Body.String())
+	}
+}
+
+func TestDefaultMaxRequestBodySize(t *testing.T) {
+	h := NewServer(1).Handler()
+
+	rec := postReport(h, padReport(int(DefaultMaxRequestBodySize)+1))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413 over default limit, got %d", rec.Code)
+	}
+}
+
+func TestNamespaceParameterIsolatesOracles(t *testing.T) {
+	s := NewServer(1)
+	h := s.Handler()
+
+	req := httptest.NewRequest(http.MethodPost, "/report?ns=production", strings.NewReader(validReport))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	req = httptest.NewRequest(http.MethodPost, "/witnesses?ns=staging", strings.NewReader(`{"id":10}`))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	query := func(url string) string {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		return rec.Body.String()
+	}
+	if body := query("/query?target=42&ns=production"); !strings.Contains(body, `"witness_count":1`) {
+		t.Errorf("production query missed its report: %s", body)
+	}
+	if body := query("/query?target=42&ns=staging"); !strings.Contains(body, `"witness_count":0`) {
+		t.Errorf("staging saw production report: %s", body)
+	}
+	if body := query("/query?target=42"); !strings.Contains(body, `"witness_count":0`) {
+		t.Errorf("default namespace saw production report: %s", body)
+	}
+}
+
+func TestNamespaceLimit(t *testing.T) {
+	s := NewServer(1).WithMaxNamespaces(3)
+	h := s.Handler()
+
+	for i := range 10 {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/report?ns=ns%d", i), strings.NewReader(validReport))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if want := i < 2; (rec.Code == http.StatusAccepted) != want {
+			t.Errorf("namespace %d: status %d, want served %v", i, rec.Code, want)
+		}
+	}
+	if got := s.broker.List(); len(got) != 3 {
+		t.Errorf("namespaces %v, want default and two more", got)
+	}
+
+	// namespaces that exist keep working
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/query?target=42&ns=ns1", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("existing namespace refused: %d", rec.Code)
+	}
+}
+
+func TestNamespaceReadsCreateNothing(t *testing.T) {
+	s := NewServer(1)
+	h := s.Handler()
+
+	for _, url := range []string{"/query?target=42&ns=x", "/health?ns=x", "/metrics?ns=x", "/witnesses?ns=x", "/events?target=42&ns=x"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s: status %d, want 404", url, rec.Code)
+		}
+	}
+	if got := s.broker.List(); len(got) != 1 {
+		t.Errorf("reads created namespaces %v", got)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/report?ns=x", strings.NewReader(validReport))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/query?target=42&ns=x", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"witness_count":1`) {
+		t.Errorf("namespace created by a write: status %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestQueryTimeoutReturns503(t *testing.T) {
+	m := &metrics.Metrics{}
+	s := NewServer(1).WithMetrics(m)
+
+	// a deadline already behind the request, the oracle cannot answer in time
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/query?target=42", nil).WithContext(ctx))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+	if body := strings.TrimSpace(rec.Body.String()); body != `{"error":"oracle timeout","refused":true}` {
+		t.Errorf("unexpected body %q", body)
+	}
+	if m.QueryTimeoutsTotal != 1 {
+		t.Errorf("QueryTimeoutsTotal = %d, want 1", m.QueryTimeoutsTotal)
+	}
+}
+
+func TestQueryCancelledWithRequest(t *testing.T) {
+	m := &metrics.Metrics{}
+	s := NewServer(1).WithMetrics(m).WithQueryTimeout(time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/query?target=42", nil).WithContext(ctx))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", rec.Code)
+	}
+	if m.QueryTimeoutsTotal != 0 {
+		t.Errorf("cancelled request counted as a timeout: %d", m.QueryTimeoutsTotal)
+	}
+}
+
+func TestWitnessSummaryEndpoint(t *testing.T) {
+	s := NewServer(1)
+	for id := uint64(10); id < 13; id++ {
+		s.oracle.RegisterWitness(types.NewNodeID(id))
+	}
+	s.oracle.EvictWitness(types.NewNodeID(12))
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/witnesses/summary", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d", rec.Code)
+	}
+	var got witness.TrustSummary
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.TotalWitnesses != 2 || got.HighTrust != 2 || got.EvictedCount != 1 {
+		t.Errorf("summary = %+v", got)
+	}
+}
+
+func TestDeleteWitness(t *testing.T) {
+	h := NewServer(1).Handler()
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+	witnessCount := func() int {
+		var resp QueryResponse
+		if err := json.NewDecoder(serve(http.MethodGet, "/query?target=42", "").Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.WitnessCount
+	}
+
+	if rec := serve(http.MethodPost, "/witnesses", `{"id":10}`); rec.Code != http.StatusCreated {
+		t.Fatalf("register: status %d", rec.Code)
+	}
+	if rec := postReport(h, validReport); rec.Code != http.StatusAccepted {
+		t.Fatalf("report: status %d", rec.Code)
+	}
+	if n := witnessCount(); n != 1 {
+		t.Fatalf("before delete: %d witnesses, want 1", n)
+	}
+
+	if rec := serve(http.MethodDelete, "/witnesses/10", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("delete: status %d, want 204", rec.Code)
+	}
+	if n := witnessCount(); n != 0 {
+		t.Errorf("after delete: %d witnesses, want 0", n)
+	}
+	if rec := serve(http.MethodDelete, "/witnesses/10", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("second delete: status %d, want 404", rec.Code)
+	}
+	if rec := serve(http.MethodDelete, "/witnesses/x", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad id: status %d, want 400", rec.Code)
+	}
+	if rec := serve(http.MethodGet, "/witnesses/summary", ""); rec.Code != http.StatusOK {
+		t.Errorf("summary shadowed by /witnesses/{id}: status %d", rec.Code)
+	}
+}
+
+func TestBulkRegisterWitnesses(t *testing.T) {
+	s := NewServer(1)
+	h := s.Handler()
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/witnesses/bulk", strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := post(`[10, 11, 11, 12]`); rec.Code != http.StatusCreated {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := post(`[12, 13]`); rec.Code != http.StatusCreated {
+		t.Fatalf("second batch: status %d", rec.Code)
+	}
+
+	records := s.oracle.Witnesses()
+	if len(records) != 4 {
+		t.Fatalf("registered %d witnesses, want 4: %+v", len(records), records)
+	}
+	for i, rec := range records {
+		if rec.ID != types.NewNodeID(uint64(10+i)) || rec.Trust != witness.DefaultTrust {
+			t.Errorf("witness %d = %s trust %v", i, rec.ID, rec.Trust)
+		}
+	}
+
+	if rec := post(`{"id": 10}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("object body: status %d, want 400", rec.Code)
+	}
+}
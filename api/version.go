@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// API versions mounted under /v1/, /v2/, ...
+// v1 is the contract from before versioning, frozen
+// v2 adds the confidence grade, credible intervals and answer timestamp to /query
+// new response fields go to a new version, never into a mounted one
+const (
+	APIVersion1      = 1
+	APIVersion2      = 2
+	LatestAPIVersion = APIVersion2
+)
+
+// vendorMediaPrefix and vendorMediaSuffix frame the versioned media type,
+// application/vnd.styx.v1+json asks for v1
+const (
+	vendorMediaPrefix = "application/vnd.styx.v"
+	vendorMediaSuffix = "+json"
+)
+
+// QueryResponseV2 is the v2 /query response, v1 plus the answer quality
+type QueryResponseV2 struct {
+	QueryResponse
+	Confidence     string     `json:"confidence"`
+	GradeRationale string     `json:"grade_rationale,omitempty"`
+	AliveInterval  [2]float64 `json:"alive_interval"`
+	DeadInterval   [2]float64 `json:"dead_interval"`
+	Timestamp      uint64     `json:"timestamp"`
+}
+
+// WithAPIVersion sets the newest API version served, /v1/ up to /vN/ are
+// mounted and unversioned paths are aliases of vN
+// pins a deployment to an older contract while clients migrate
+// versions outside 1..LatestAPIVersion are ignored, call before Handler
+func (s *Server) WithAPIVersion(v int) *Server {
+	if v >= APIVersion1 && v <= LatestAPIVersion {
+		s.apiVersion = v
+	}
+	return s
+}
+
+type apiVersionKey struct{}
+
+// apiVersion returns the API version a request is served under
+func apiVersion(r *http.Request) int {
+	if v, ok := r.Context().Value(apiVersionKey{}).(int); ok {
+		return v
+	}
+	return LatestAPIVersion
+}
+
+// mount registers h at path and under every mounted version prefix
+func (s *Server) mount(mux *http.ServeMux, path string, h http.HandlerFunc) {
+	mux.Handle(path, s.negotiate(0, h))
+	for v := APIVersion1; v <= s.apiVersion; v++ {
+		mux.Handle("/v"+strconv.Itoa(v)+path, s.negotiate(v, h))
+	}
+}
+
+// negotiate picks the version h serves a request under
+// a versioned path wins, an unversioned path takes the version from the
+// Accept header or else the newest mounted one and names the versioned
+// path it aliases in Content-Location
+// asking for an unmounted version, or for another version than the path,
+// is answered with 406
+func (s *Server) negotiate(pathVersion int, h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accepted := acceptedVersion(r.Header.Get("Accept"))
+		if accepted < 0 || accepted > s.apiVersion || (accepted != 0 && pathVersion != 0 && accepted != pathVersion) {
+			http.Error(w, "unsupported api version", http.StatusNotAcceptable)
+			return
+		}
+
+		v := pathVersion
+		if v == 0 {
+			v = accepted
+			if v == 0 {
+				v = s.apiVersion
+			}
+			w.Header().Set("Content-Location", "/v"+strconv.Itoa(v)+r.URL.RequestURI())
+		}
+		h(w, r.WithContext(context.WithValue(r.Context(), apiVersionKey{}, v)))
+	})
+}
+
+// acceptedVersion returns the version named by a vendor media type in an
+// Accept header, 0 when none is named and -1 when it cant be parsed
+func acceptedVersion(accept string) int {
+	for _, media := range strings.Split(accept, ",") {
+		media, _, _ = strings.Cut(media, ";")
+		media = strings.TrimSpace(media)
+		if !strings.HasPrefix(media, vendorMediaPrefix) || !strings.HasSuffix(media, vendorMediaSuffix) {
+			continue
+		}
+		v, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(media, vendorMediaPrefix), vendorMediaSuffix))
+		if err != nil || v < APIVersion1 {
+			return -1
+		}
+		return v
+	}
+	return 0
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// v1QueryFields is the frozen v1 /query contract
+var v1QueryFields = []string{
+	"alive_confidence", "dead", "dead_confidence", "disagreement", "evidence",
+	"partition_state", "refused", "target", "unknown", "witness_count",
+}
+
+func getVersioned(h http.Handler, path, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeFields(t *testing.T, rec *httptest.ResponseRecorder) map[string]json.RawMessage {
+	t.Helper()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var fields map[string]json.RawMessage
+	if err := json.NewDecoder(rec.Body).Decode(&fields); err != nil {
+		t.Fatal(err)
+	}
+	return fields
+}
+
+func fieldNames(fields map[string]json.RawMessage) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestVersionedQueryContracts(t *testing.T) {
+	h := NewServer(1).Handler()
+	if rec := postReport(h, validReport); rec.Code != http.StatusAccepted {
+		t.Fatalf("report: expected 202, got %d", rec.Code)
+	}
+
+	v1 := decodeFields(t, getVersioned(h, "/v1/query?target=42", ""))
+	if got := fieldNames(v1); strings.Join(got, ",") != strings.Join(v1QueryFields, ",") {
+		t.Errorf("v1 fields %v, want %v", got, v1QueryFields)
+	}
+
+	v2 := decodeFields(t, getVersioned(h, "/v2/query?target=42", ""))
+	for _, name := range append([]string{"confidence", "alive_interval", "dead_interval", "timestamp"}, v1QueryFields...) {
+		if _, ok := v2[name]; !ok {
+			t.Errorf("v2 response missing %q: %v", name, fieldNames(v2))
+		}
+	}
+	var ts uint64
+	if err := json.Unmarshal(v2["timestamp"], &ts); err != nil || ts == 0 {
+		t.Errorf("v2 timestamp %s, want the answer's logical time", v2["timestamp"])
+	}
+
+	// the unversioned path is the latest version
+	rec := getVersioned(h, "/query?target=42", "")
+	if loc := rec.Header().Get("Content-Location"); loc != "/v2/query?target=42" {
+		t.Errorf("Content-Location %q, want /v2/query?target=42", loc)
+	}
+	if got := fieldNames(decodeFields(t, rec)); len(got) != len(v2) {
+		t.Errorf("/query fields %v, want the v2 fields", got)
+	}
+}
+
+func TestAcceptHeaderSelectsVersion(t *testing.T) {
+	h := NewServer(1).Handler()
+
+	rec := getVersioned(h, "/query?target=42", "text/html, application/vnd.styx.v1+json;q=0.9")
+	if loc := rec.Header().Get("Content-Location"); loc != "/v1/query?target=42" {
+		t.Errorf("Content-Location %q, want /v1/query?target=42", loc)
+	}
+	if got := fieldNames(decodeFields(t, rec)); len(got) != len(v1QueryFields) {
+		t.Errorf("Accept v1 got fields %v", got)
+	}
+	if fields := decodeFields(t, getVersioned(h, "/query?target=42", "application/vnd.styx.v2+json")); fields["confidence"] == nil {
+		t.Error("Accept v2 got a v1 response")
+	}
+
+	for _, tc := range []struct{ path, accept string }{
+		{"/query?target=42", "application/vnd.styx.v3+json"},
+		{"/query?target=42", "application/vnd.styx.vx+json"},
+		{"/v2/query?target=42", "application/vnd.styx.v1+json"},
+	} {
+		if rec := getVersioned(h, tc.path, tc.accept); rec.Code != http.StatusNotAcceptable {
+			t.Errorf("%s with %s: expected 406, got %d", tc.path, tc.accept, rec.Code)
+		}
+	}
+}
+
+func TestWithAPIVersionPinsOlderContract(t *testing.T) {
+	h := NewServer(1).WithAPIVersion(APIVersion1).Handler()
+
+	if rec := getVersioned(h, "/v2/query?target=42", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("/v2 on a v1 server: expected 404, got %d", rec.Code)
+	}
+	if rec := getVersioned(h, "/query?target=42", "application/vnd.styx.v2+json"); rec.Code != http.StatusNotAcceptable {
+		t.Errorf("Accept v2 on a v1 server: expected 406, got %d", rec.Code)
+	}
+	if got := fieldNames(decodeFields(t, getVersioned(h, "/query?target=42", ""))); len(got) != len(v1QueryFields) {
+		t.Errorf("/query on a v1 server got fields %v", got)
+	}
+	if rec := getVersioned(h, "/v1/health", ""); rec.Code != http.StatusOK {
+		t.Errorf("/v1/health: expected 200, got %d", rec.Code)
+	}
+}
@@ -2,10 +2,14 @@ package chaos
 
 import (
 	"math/rand"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/styx-oracle/styx/evidence"
 	"github.com/styx-oracle/styx/oracle"
+	"github.com/styx-oracle/styx/partition"
 	"github.com/styx-oracle/styx/types"
 )
 
@@ -16,68 +20,81 @@ func init() {
 // TestByzantineWitnesses tests with lying witnesses
 // 30% of witnesses always report opposite of majority
 // STYX should still get correct answer from honest majority
+// Reports arrive in a seeded random order, replayed across seeds
 func TestByzantineWitnesses(t *testing.T) {
-	orc := oracle.New(types.NewNodeID(1))
 	target := types.NewNodeID(99)
 
-	// Reality: node is alive
-	// Honest witnesses (7): report alive
-	// Byzantine witnesses (3): report dead
-
-	for i := 1; i <= 7; i++ {
-		// Honest witnesses say alive
-		orc.ReceiveReport(
-			types.NewNodeID(uint64(i)),
-			target,
-			types.MustBelief(0.85, 0.05, 0.10),
-		)
-	}
-
-	for i := 8; i <= 10; i++ {
-		// Byzantine witnesses lie - say dead
-		orc.ReceiveReport(
-			types.NewNodeID(uint64(i)),
-			target,
-			types.MustBelief(0.05, 0.85, 0.10),
-		)
+	scenario := func(h *oracle.Harness) {
+		// Reality: node is alive
+		// Honest witnesses (7): report alive
+		// Byzantine witnesses (3): report dead
+		witnesses := h.Rand().Perm(10)
+		for _, w := range witnesses {
+			id := uint64(w + 1)
+			if id <= 7 {
+				// Honest witnesses say alive
+				h.ReceiveReport(types.NewNodeID(id), target, types.MustBelief(0.85, 0.05, 0.10))
+			} else {
+				// Byzantine witnesses lie - say dead
+				h.ReceiveReport(types.NewNodeID(id), target, types.MustBelief(0.05, 0.85, 0.10))
+			}
+		}
 	}
 
-	result := orc.Query(target)
+	h := oracle.NewHarness(types.NewNodeID(1), 1).Run(scenario)
+	for seed := int64(1); seed <= 20; seed++ {
+		run := h.Replay(seed)
+		result := run.Query(target)
 
-	// Should still lean alive despite liars
-	if result.Refused {
-		// Acceptable - high disagreement triggers caution
-		t.Logf("Oracle refused due to disagreement: %s", result.RefusalReason)
-		return
-	}
+		// Should still lean alive despite liars
+		if result.Refused {
+			// Acceptable - high disagreement triggers caution
+			t.Logf("seed %d: Oracle refused due to disagreement: %s", seed, result.RefusalReason)
+			continue
+		}
 
-	if result.Belief.Dead().Value() > result.Belief.Alive().Value() {
-		t.Errorf("Byzantine attack succeeded: dead=%f > alive=%f",
-			result.Belief.Dead().Value(),
-			result.Belief.Alive().Value())
+		if result.Belief.Dead().Value() > result.Belief.Alive().Value() {
+			t.Errorf("seed %d: Byzantine attack succeeded: dead=%f > alive=%f",
+				seed,
+				result.Belief.Dead().Value(),
+				result.Belief.Alive().Value())
+		}
 	}
 }
 
 // TestFlappyNode simulates rapid up/down transitions
 // STYX should increase uncertainty, not flip wildly
 func TestFlappyNode(t *testing.T) {
-	orc := oracle.New(types.NewNodeID(1))
 	target := types.NewNodeID(99)
 
-	// Simulate 20 rapid state changes
-	for i := 0; i < 20; i++ {
-		witness := types.NewNodeID(uint64(100 + i))
-
-		if i%2 == 0 {
-			// Even: alive
-			orc.ReceiveReport(witness, target, types.MustBelief(0.8, 0.1, 0.1))
-		} else {
-			// Odd: dead
-			orc.ReceiveReport(witness, target, types.MustBelief(0.1, 0.8, 0.1))
+	scenario := func(h *oracle.Harness) {
+		// Simulate 20 rapid state changes with seeded noise
+		for i := 0; i < 20; i++ {
+			witness := types.NewNodeID(uint64(100 + i))
+			noise := h.Rand().Float64() * 0.05
+			h.AdvanceClock(uint64(1 + h.Rand().Intn(3)))
+
+			if i%2 == 0 {
+				// Even: alive
+				h.ReceiveReport(witness, target, types.MustBelief(0.8-noise, 0.1, 0.1+noise))
+			} else {
+				// Odd: dead
+				h.ReceiveReport(witness, target, types.MustBelief(0.1, 0.8-noise, 0.1+noise))
+			}
 		}
 	}
 
-	result := orc.Query(target)
+	h := oracle.NewHarness(types.NewNodeID(1), 42).Run(scenario)
+	result := h.Query(target)
+
+	// Same seed must reproduce the same result exactly
+	again := h.Replay(42)
+	if !again.Query(target).Belief.Equal(result.Belief) {
+		t.Errorf("replay with same seed diverged")
+	}
+	if len(again.Calls()) != len(h.Calls()) {
+		t.Errorf("replay recorded %d calls, want %d", len(again.Calls()), len(h.Calls()))
+	}
 
 	// With flapping, should have HIGH uncertainty or refuse
 	if !result.Refused {
@@ -130,6 +147,39 @@ func TestTimeoutStorm(t *testing.T) {
 		result.Belief.Unknown().Value())
 }
 
+// TestCheckpointThroughStorm tests a timeout storm on top of crash reports
+// The same storm declares death unless its witness saw an application
+// checkpoint, which must hold finality off (P15)
+func TestCheckpointThroughStorm(t *testing.T) {
+	target := types.NewNodeID(99)
+	storm := func(checkpoint bool) error {
+		orc := oracle.New(types.NewNodeID(1))
+
+		// Three witnesses saw the node crash
+		orc.ReceiveReport(types.NewNodeID(1), target, types.MustBelief(0.0, 0.95, 0.05))
+		orc.ReceiveReport(types.NewNodeID(2), target, types.MustBelief(0.04, 0.9, 0.06))
+		orc.ReceiveReport(types.NewNodeID(3), target, types.MustBelief(0.0, 0.9, 0.1))
+
+		// A fourth only timed out, until it saw a checkpoint
+		stormer := types.NewNodeID(4)
+		for i := 0; i < 10; i++ {
+			orc.ReceiveEvidence(stormer, target, evidence.NewTimeout(0, 100, 2000, stormer, target))
+		}
+		if checkpoint {
+			orc.ReceiveEvidence(stormer, target,
+				evidence.NewApplicationCheckpoint(0, 1, stormer, target))
+		}
+		return orc.DeclareDeath(target, true)
+	}
+
+	if err := storm(false); err != nil {
+		t.Fatalf("storm without a checkpoint should declare death, the test proves nothing: %v", err)
+	}
+	if err := storm(true); err == nil {
+		t.Error("P15 VIOLATED: Timeout storm declared death despite a checkpoint")
+	}
+}
+
 // TestCorrelatedWitnesses tests when all witnesses are too similar
 // Should detect correlation and reduce confidence (P11)
 func TestCorrelatedWitnesses(t *testing.T) {
@@ -212,6 +262,54 @@ func TestResurrectionAttack(t *testing.T) {
 		result.Refused)
 }
 
+// TestResurrectionByRebirth checks Property 3 (restart != resurrection)
+// across types, witness, finality and oracle
+// a node declared dead stays dead, its restart is a new generation that
+// has to earn its own belief
+func TestResurrectionByRebirth(t *testing.T) {
+	orc := oracle.New(types.NewNodeID(1))
+	n := types.NewNodeID(99)
+
+	// 1. declare N dead through proper finality
+	orc.ReceiveReport(types.NewNodeID(10), n, types.MustBelief(0.0, 0.95, 0.05))
+	orc.ReceiveReport(types.NewNodeID(11), n, types.MustBelief(0.04, 0.9, 0.06))
+	orc.ReceiveReport(types.NewNodeID(12), n, types.MustBelief(0.0, 0.9, 0.1))
+	if err := orc.DeclareDeath(n, true); err != nil {
+		t.Fatalf("DeclareDeath: %v", err)
+	}
+
+	// 2. N is dead
+	if !orc.Query(n).Dead {
+		t.Fatal("N not dead after finality")
+	}
+
+	// 3. N restarts as a new generation
+	n2 := n.Rebirth()
+	if !n2.IsRebirthOf(n) {
+		t.Errorf("%s is not a rebirth of %s", n2, n)
+	}
+	if n.Generation >= n2.Generation {
+		t.Errorf("generation did not advance: %d -> %d", n.Generation, n2.Generation)
+	}
+
+	// 4. the same witnesses now see N2 alive
+	orc.ReceiveReport(types.NewNodeID(10), n2, types.MustBelief(0.9, 0.05, 0.05))
+	orc.ReceiveReport(types.NewNodeID(11), n2, types.MustBelief(0.85, 0.05, 0.1))
+	orc.ReceiveReport(types.NewNodeID(12), n2, types.MustBelief(0.8, 0.1, 0.1))
+
+	// 5. N stays dead, N2 is alive in its own right
+	if !orc.Query(n).Dead {
+		t.Error("P14: N resurrected by its rebirth")
+	}
+	result := orc.Query(n2)
+	if result.Dead {
+		t.Error("P3: N2 inherited the death of N")
+	}
+	if result.Belief.Alive().Value() == 0 {
+		t.Errorf("N2 has no alive confidence: %v", result.Belief)
+	}
+}
+
 // TestScaleStress tests with 500 witnesses
 func TestScaleStress(t *testing.T) {
 	orc := oracle.New(types.NewNodeID(1))
@@ -291,6 +389,215 @@ func TestPartitionChaos(t *testing.T) {
 	}
 }
 
+// TestNetworkAsymmetry simulates a one way link failure
+// Group A reaches the target and reports alive
+// Group B cannot reach it and only has silence, so it reports unknown
+// Silence is not a dead vote: no confirmed partition, no death (P15)
+func TestNetworkAsymmetry(t *testing.T) {
+	target := types.NewNodeID(99)
+
+	scenario := func(h *oracle.Harness) {
+		reachable := []types.Belief{
+			types.MustBelief(0.85, 0.05, 0.10),
+			types.MustBelief(0.80, 0.05, 0.15),
+			types.MustBelief(0.90, 0.03, 0.07),
+		}
+		// Cut off side: nothing heard, nothing concluded
+		silent := types.MustBelief(0.05, 0.05, 0.90)
+
+		for _, w := range h.Rand().Perm(7) {
+			id := types.NewNodeID(uint64(w + 1))
+			if w < len(reachable) {
+				h.ReceiveReport(id, target, reachable[w])
+			} else {
+				h.ReceiveReport(id, target, silent)
+			}
+		}
+	}
+
+	h := oracle.NewHarness(types.NewNodeID(1), 1).Run(scenario)
+	for seed := int64(1); seed <= 10; seed++ {
+		run := h.Replay(seed)
+		result := run.Query(target)
+
+		// (a) one side being blind is not a split reality
+		if result.PartitionState == partition.ConfirmedPartition {
+			t.Errorf("seed %d: asymmetric link confirmed as partition", seed)
+		}
+		if result.Refused {
+			t.Errorf("seed %d: refused: %s", seed, result.RefusalReason)
+			continue
+		}
+
+		// (b) group A's reports still carry the answer
+		alive, dead := result.Belief.Alive().Value(), result.Belief.Dead().Value()
+		if alive <= dead {
+			t.Errorf("seed %d: expected alive-leaning belief, got %s", seed, result.Belief)
+		}
+
+		// (c) P15: silence never turns into death
+		if result.Dead || result.Belief.Dominant() == types.StateDead {
+			t.Errorf("seed %d: silence treated as death: %s", seed, result.Belief)
+		}
+		if err := run.Oracle().DeclareDeath(target, false); err == nil {
+			t.Errorf("seed %d: death declared from silence", seed)
+		}
+	}
+}
+
+// TestHighWitnessChurn replaces most of the witness set mid-flight
+// 20 witnesses report, 15 of them die and are evicted, 15 new ones join
+// the belief must come from the living witnesses only
+// reports, evictions and queries run concurrently, run with -race
+func TestHighWitnessChurn(t *testing.T) {
+	orc := oracle.New(types.NewNodeID(1))
+	target := types.NewNodeID(99)
+
+	// The 15 doomed witnesses wrongly think the target is dead,
+	// the 5 survivors see it alive
+	var wg sync.WaitGroup
+	for i := 1; i <= 20; i++ {
+		wg.Add(1)
+		go func(id uint64) {
+			defer wg.Done()
+			jitter := float64(id%5) * 0.01
+			if id <= 15 {
+				orc.ReceiveReport(types.NewNodeID(id), target, types.MustBelief(0.05+jitter, 0.85-jitter, 0.10))
+			} else {
+				orc.ReceiveReport(types.NewNodeID(id), target, types.MustBelief(0.85-jitter, 0.05+jitter, 0.10))
+			}
+		}(uint64(i))
+	}
+	wg.Wait()
+
+	before := orc.Query(target)
+	if !before.Refused && before.Belief.Alive().Value() > before.Belief.Dead().Value() {
+		t.Fatalf("setup: doomed majority should not lean alive: %s", before.Belief)
+	}
+
+	// Evict the dead witnesses while new ones join and readers poll
+	for i := 1; i <= 15; i++ {
+		wg.Add(1)
+		go func(id uint64) {
+			defer wg.Done()
+			if !orc.EvictWitness(types.NewNodeID(id)) {
+				t.Errorf("witness %d should have been registered", id)
+			}
+		}(uint64(i))
+	}
+	for i := 101; i <= 115; i++ {
+		wg.Add(1)
+		go func(id uint64) {
+			defer wg.Done()
+			jitter := float64(id%5) * 0.01
+			orc.RegisterWitness(types.NewNodeID(id))
+			orc.ReceiveReport(types.NewNodeID(id), target, types.MustBelief(0.85-jitter, 0.05+jitter, 0.10))
+		}(uint64(i))
+	}
+	wg.Wait()
+
+	// the dead keep talking, eviction must stick until they register again
+	for i := 1; i <= 15; i++ {
+		wg.Add(1)
+		go func(id uint64) {
+			defer wg.Done()
+			orc.SetWitnessHalfLife(types.NewNodeID(id), 10)
+			orc.ReceiveReport(types.NewNodeID(id), target, types.MustBelief(0.05, 0.85, 0.10))
+		}(uint64(i))
+	}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				orc.Query(target)
+				orc.Witnesses()
+			}
+		}()
+	}
+	wg.Wait()
+
+	result := orc.Query(target)
+	if result.WitnessCount != 20 {
+		t.Errorf("only the 5 survivors and 15 newcomers should count, got %d reports", result.WitnessCount)
+	}
+	if result.Refused {
+		t.Fatalf("living witnesses agree, should not refuse: %s", result.RefusalReason)
+	}
+	if result.Belief.Alive().Value() <= result.Belief.Dead().Value() {
+		t.Errorf("evicted witnesses still influence the belief: %s", result.Belief)
+	}
+
+	snapshot := orc.Witnesses()
+	if len(snapshot) != 20 {
+		t.Fatalf("snapshot should hold 20 living witnesses, got %d", len(snapshot))
+	}
+	for _, w := range snapshot {
+		if w.ID.Base <= 15 {
+			t.Errorf("evicted witness %s still in snapshot", w.ID)
+		}
+	}
+
+	t.Logf("Churn test: before=%s after=%s", before.Belief, result.Belief)
+}
+
+// memoryBoundBytes is the heap TestMemoryBound allows after the flood
+const memoryBoundBytes = 50 << 20
+
+// TestMemoryBound floods one target with 10,000 reports and another with
+// 10,000 evidence items
+// the per-target report cap and the per-witness evidence cap must hold and
+// the heap must stay bounded
+func TestMemoryBound(t *testing.T) {
+	const reports, maxReports = 10000, 100
+	const witnesses, maxEvidence = 50, 20
+
+	orc := oracle.New(types.NewNodeID(1)).WithMaxReportsPerTarget(maxReports).WithMaxEvidencePerWitness(maxEvidence)
+	target, observed := types.NewNodeID(99), types.NewNodeID(98)
+
+	for i := 0; i < reports; i++ {
+		alive := 0.7 + float64(i%20)*0.01
+		orc.ReceiveReport(types.NewNodeID(uint64(i%50+1)), target, types.MustBelief(alive, 0.05, 0.95-alive))
+		if n := orc.ReportCount(target); n > maxReports {
+			t.Fatalf("after %d reports %d are stored, cap is %d", i+1, n, maxReports)
+		}
+	}
+
+	result := orc.Query(target)
+	if result.WitnessCount != maxReports {
+		t.Errorf("query saw %d reports, want the newest %d", result.WitnessCount, maxReports)
+	}
+
+	for i := 0; i < reports; i++ {
+		w := types.NewNodeID(uint64(i%witnesses + 1))
+		e := evidence.NewDirectResponse(0, uint64(5+i%50), w, observed)
+		if i%3 == 0 {
+			e = evidence.NewTimeout(0, 100, 300, w, observed)
+		}
+		if err := orc.ReceiveEvidence(w, observed, e); err != nil {
+			t.Fatalf("evidence %d: %v", i, err)
+		}
+		if n := orc.EvidenceCount(observed); n > witnesses*maxEvidence {
+			t.Fatalf("after %d evidence items %d are stored, cap is %d", i+1, n, witnesses*maxEvidence)
+		}
+	}
+	if n := orc.EvidenceCount(observed); n != witnesses*maxEvidence {
+		t.Errorf("%d evidence items stored, want %d per witness", n, maxEvidence)
+	}
+	if result := orc.Query(observed); result.WitnessCount != witnesses {
+		t.Errorf("query saw %d evidence witnesses, want %d", result.WitnessCount, witnesses)
+	}
+
+	runtime.GC()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	if mem.HeapAlloc > memoryBoundBytes {
+		t.Errorf("heap %d bytes after %d reports, bound %d", mem.HeapAlloc, reports, memoryBoundBytes)
+	}
+
+	t.Logf("Memory bound: %d reports and %d evidence items stored, heap %.1f MB", orc.ReportCount(target), orc.EvidenceCount(observed), float64(mem.HeapAlloc)/(1<<20))
+}
+
 // TestWitnessTrustDecay tests that bad witnesses lose influence
 func TestWitnessTrustDecay(t *testing.T) {
 	orc := oracle.New(types.NewNodeID(1))
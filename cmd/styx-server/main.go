@@ -27,6 +27,7 @@ func main() {
 	fmt.Println("  GET  /query?target=ID - query node status")
 	fmt.Println("  POST /report          - submit witness report")
 	fmt.Println("  POST /witnesses       - register witness")
+	fmt.Println("  GET  /events?target=ID - stream belief changes")
 
 	if err := server.ListenAndServe(addr); err != nil {
 		log.Fatal(err)
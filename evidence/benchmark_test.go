@@ -0,0 +1,98 @@
+package evidence
+
+import (
+	"testing"
+
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+)
+
+// seedSet fills a set with alternating direct responses and timeouts
+func seedSet(n int) *EvidenceSet {
+	source := types.NewNodeID(1)
+	target := types.NewNodeID(2)
+	es := NewEvidenceSet()
+	for i := 0; i < n; i++ {
+		ts := styxtime.LogicalTimestamp(i)
+		if i%2 == 0 {
+			es.Add(NewDirectResponse(ts, 10, source, target))
+		} else {
+			es.Add(NewTimeout(ts, 100, 500, source, target))
+		}
+	}
+	return es
+}
+
+func benchmarkComputeBelief(b *testing.B, n int) {
+	es := seedSet(n)
+	now := styxtime.LogicalTimestamp(n)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		es.ComputeBelief(now)
+	}
+}
+
+// ComputeBelief should scale linearly: compare ns/op across these three
+func BenchmarkComputeBelief1K(b *testing.B)   { benchmarkComputeBelief(b, 1_000) }
+func BenchmarkComputeBelief10K(b *testing.B)  { benchmarkComputeBelief(b, 10_000) }
+func BenchmarkComputeBelief100K(b *testing.B) { benchmarkComputeBelief(b, 100_000) }
+
+func BenchmarkComputeBeliefParallel10K(b *testing.B) {
+	es := seedSet(10_000)
+	now := styxtime.LogicalTimestamp(10_000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			es.ComputeBelief(now)
+		}
+	})
+}
+
+func BenchmarkEvidenceSetAdd10K(b *testing.B) {
+	source := types.NewNodeID(1)
+	target := types.NewNodeID(2)
+	ev := NewDirectResponse(styxtime.LogicalTimestamp(1), 10, source, target)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		es := NewEvidenceSet()
+		for j := 0; j < 10_000; j++ {
+			es.Add(ev)
+		}
+	}
+}
+
+// filterLinear is FilterByTimeRange without the index
+func filterLinear(es *EvidenceSet, from, to styxtime.LogicalTimestamp) []Evidence {
+	result := make([]Evidence, 0)
+	for _, e := range es.All() {
+		if e.Timestamp >= from && e.Timestamp <= to {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// a fixed 10 item window: indexed cost stays flat as the set grows, the
+// linear scan grows with it
+func benchmarkFilterByTimeRange(b *testing.B, n int, indexed bool) {
+	es := seedSet(n)
+	from := styxtime.LogicalTimestamp(n / 2)
+	to := from + 9
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if indexed {
+			es.FilterByTimeRange(from, to)
+		} else {
+			filterLinear(es, from, to)
+		}
+	}
+}
+
+func BenchmarkFilterByTimeRange1K(b *testing.B)   { benchmarkFilterByTimeRange(b, 1_000, true) }
+func BenchmarkFilterByTimeRange10K(b *testing.B)  { benchmarkFilterByTimeRange(b, 10_000, true) }
+func BenchmarkFilterByTimeRange100K(b *testing.B) { benchmarkFilterByTimeRange(b, 100_000, true) }
+func BenchmarkFilterLinearScan10K(b *testing.B)   { benchmarkFilterByTimeRange(b, 10_000, false) }
+func BenchmarkFilterLinearScan100K(b *testing.B)  { benchmarkFilterByTimeRange(b, 100_000, false) }
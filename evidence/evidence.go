@@ -5,12 +5,17 @@
 package evidence
 
 import (
+	"errors"
 	"fmt"
+	"math"
 
 	styxtime "github.com/styx-oracle/styx/time"
 	"github.com/styx-oracle/styx/types"
 )
 
+// ErrInvalidEvidence is returned by Validate for self-inconsistent evidence.
+var ErrInvalidEvidence = errors.New("invalid evidence")
+
 // EventID is a unique identifier for a causal event.
 type EventID uint64
 
@@ -42,6 +47,11 @@ const (
 
 	// KindNetworkInstability - network issues detected on path.
 	KindNetworkInstability
+
+	// KindApplicationCheckpoint - the node flushed application state
+	// (WAL segment, checkpoint file). Strong proof of liveness: the process
+	// was running its own logic, not just answering pings.
+	KindApplicationCheckpoint
 )
 
 func (k EvidenceKind) String() string {
@@ -58,6 +68,8 @@ func (k EvidenceKind) String() string {
 		return "SchedulingJitter"
 	case KindNetworkInstability:
 		return "NetworkInstability"
+	case KindApplicationCheckpoint:
+		return "ApplicationCheckpoint"
 	default:
 		return "Unknown"
 	}
@@ -71,6 +83,10 @@ type Evidence struct {
 	Source    types.NodeID
 	Target    types.NodeID
 	Details   EvidenceDetails
+
+	// Origin is the timestamp the observation was first given, kept when
+	// the evidence is restamped or relayed. Zero if it never was.
+	Origin styxtime.LogicalTimestamp
 }
 
 // EvidenceDetails contains kind-specific details.
@@ -96,55 +112,45 @@ type EvidenceDetails struct {
 	// NetworkInstability
 	PacketLossRate    float64
 	LatencyVarianceMS uint64
+
+	// ApplicationCheckpoint
+	CheckpointSeq uint64
 }
 
 // NewDirectResponse creates evidence of a direct response.
 func NewDirectResponse(ts styxtime.LogicalTimestamp, latencyMS uint64, source, target types.NodeID) Evidence {
-	weight := 1.0
-	if latencyMS >= 100 && latencyMS < 1000 {
-		weight = 0.8
-	} else if latencyMS >= 1000 {
-		weight = 0.6
-	}
-	return Evidence{
-		Kind:      KindDirectResponse,
-		Timestamp: ts,
-		Weight:    weight,
-		Source:    source,
-		Target:    target,
-		Details:   EvidenceDetails{LatencyMS: latencyMS},
-	}
+	return DefaultWeightProfile().NewDirectResponse(ts, latencyMS, source, target)
 }
 
 // NewTimeout creates evidence of a timeout.
 // Per Property 4 and 15: timeouts are WEAK evidence, never proof of death.
 func NewTimeout(ts styxtime.LogicalTimestamp, expectedMS, waitedMS uint64, source, target types.NodeID) Evidence {
-	ratio := float64(waitedMS) / float64(expectedMS)
-	weight := 0.1
-	if ratio > 10.0 {
-		weight = 0.3 // Still weak - silence ≠ death
-	} else if ratio > 3.0 {
-		weight = 0.2
-	}
+	return DefaultWeightProfile().NewTimeout(ts, expectedMS, waitedMS, source, target)
+}
+
+// NewCausalEvent creates evidence of a causal event.
+func NewCausalEvent(ts styxtime.LogicalTimestamp, eventID EventID, source, target types.NodeID) Evidence {
 	return Evidence{
-		Kind:      KindTimeout,
+		Kind:      KindCausalEvent,
 		Timestamp: ts,
-		Weight:    weight,
+		Weight:    1.0,
 		Source:    source,
 		Target:    target,
-		Details:   EvidenceDetails{ExpectedMS: expectedMS, WaitedMS: waitedMS},
+		Details:   EvidenceDetails{EventID: eventID},
 	}
 }
 
-// NewCausalEvent creates evidence of a causal event.
-func NewCausalEvent(ts styxtime.LogicalTimestamp, eventID EventID, source, target types.NodeID) Evidence {
+// NewApplicationCheckpoint creates evidence of an application checkpoint.
+// Weighted like a direct response: the checkpoint proves the process was
+// doing real work at ts.
+func NewApplicationCheckpoint(ts styxtime.LogicalTimestamp, checkpointSeq uint64, source, target types.NodeID) Evidence {
 	return Evidence{
-		Kind:      KindCausalEvent,
+		Kind:      KindApplicationCheckpoint,
 		Timestamp: ts,
 		Weight:    1.0,
 		Source:    source,
 		Target:    target,
-		Details:   EvidenceDetails{EventID: eventID},
+		Details:   EvidenceDetails{CheckpointSeq: checkpointSeq},
 	}
 }
 
@@ -165,9 +171,62 @@ func NewSchedulingJitter(ts styxtime.LogicalTimestamp, delayMS uint64, source, t
 	}
 }
 
+// MaxNetworkInstabilityWeight caps the weight of network instability
+// evidence. Packet loss and latency variance make the path, not the node,
+// suspect: instability is a reason for doubt, never proof of anything about
+// the target.
+const MaxNetworkInstabilityWeight = 0.7
+
+// NewNetworkInstability creates evidence of an unstable network path.
+// The weight is capped at MaxNetworkInstabilityWeight.
+func NewNetworkInstability(ts styxtime.LogicalTimestamp, packetLossRate float64, latencyVarianceMS uint64, source, target types.NodeID) Evidence {
+	loss := packetLossRate
+	if math.IsNaN(loss) || loss < 0 {
+		loss = 0
+	} else if loss > 1 {
+		loss = 1
+	}
+	weight := math.Min(loss*0.5+float64(latencyVarianceMS)/10000.0*0.3, MaxNetworkInstabilityWeight)
+	return Evidence{
+		Kind:      KindNetworkInstability,
+		Timestamp: ts,
+		Weight:    weight,
+		Source:    source,
+		Target:    target,
+		Details: EvidenceDetails{
+			PacketLossRate:    loss,
+			LatencyVarianceMS: latencyVarianceMS,
+		},
+	}
+}
+
+// Validate checks that the evidence fields are self-consistent.
+//
+// Constructors always produce valid evidence, but the fields are exported
+// and may be changed afterwards. Validate rejects weights outside [0, 1],
+// unknown kinds, evidence a node reports about itself, and direct
+// responses without a latency.
+func (e Evidence) Validate() error {
+	if math.IsNaN(e.Weight) || e.Weight < 0 || e.Weight > 1 {
+		return fmt.Errorf("%w: weight %v outside [0,1]", ErrInvalidEvidence, e.Weight)
+	}
+	if e.Kind < KindDirectResponse || e.Kind > KindApplicationCheckpoint {
+		return fmt.Errorf("%w: unknown kind %d", ErrInvalidEvidence, int(e.Kind))
+	}
+	if e.Source == e.Target {
+		return fmt.Errorf("%w: %s reports about itself", ErrInvalidEvidence, e.Source)
+	}
+	if e.Kind == KindDirectResponse && e.Details.LatencyMS == 0 {
+		return fmt.Errorf("%w: direct response without latency", ErrInvalidEvidence)
+	}
+	return nil
+}
+
 // SuggestsAlive returns true if this evidence suggests the target is alive.
 func (e Evidence) SuggestsAlive() bool {
-	return e.Kind == KindDirectResponse || e.Kind == KindCausalEvent
+	return e.Kind == KindDirectResponse ||
+		e.Kind == KindCausalEvent ||
+		e.Kind == KindApplicationCheckpoint
 }
 
 // SuggestsDead returns true if this evidence suggests the target MIGHT be dead.
@@ -176,6 +235,12 @@ func (e Evidence) SuggestsDead() bool {
 	return e.Kind == KindTimeout
 }
 
+// SuggestsNetworkIssue returns true if this evidence points at the network
+// path rather than the target itself.
+func (e Evidence) SuggestsNetworkIssue() bool {
+	return e.Kind == KindNetworkInstability
+}
+
 // EffectiveWeight returns weight adjusted for age decay.
 func (e Evidence) EffectiveWeight(now styxtime.LogicalTimestamp, halfLife uint64) float64 {
 	age := e.Timestamp.AgeSince(now)
@@ -183,18 +248,22 @@ func (e Evidence) EffectiveWeight(now styxtime.LogicalTimestamp, halfLife uint64
 	return e.Weight * decayFactor
 }
 
+// pow raises base to a non-negative exp.
+// The integer part is exact; the fractional part is linearly approximated.
+// Non-finite or huge exponents (e.g. zero half-life, ancient evidence)
+// are resolved directly instead of looping.
 func pow(base, exp float64) float64 {
-	if exp == 0 {
+	if exp == 0 || math.IsNaN(exp) {
 		return 1
 	}
-	result := 1.0
-	for exp >= 1 {
-		result *= base
-		exp--
+	if math.IsInf(exp, 1) {
+		return math.Pow(base, exp)
 	}
+	whole, frac := math.Modf(exp)
+	result := math.Pow(base, whole)
 	// Approximate for fractional part
-	if exp > 0 {
-		result *= 1 + exp*(base-1)
+	if frac > 0 {
+		result *= 1 + frac*(base-1)
 	}
 	return result
 }
@@ -2,6 +2,7 @@ package evidence
 
 import (
 	"math"
+	"sort"
 
 	styxtime "github.com/styx-oracle/styx/time"
 	"github.com/styx-oracle/styx/types"
@@ -10,12 +11,23 @@ import (
 // DefaultHalfLife for evidence decay (in logical time units).
 const DefaultHalfLife uint64 = 100
 
+// EvidenceSizeBytes is the estimated memory footprint of one Evidence record.
+const EvidenceSizeBytes = 128
+
 // EvidenceSet aggregates evidence about a single node.
 // Implements Property 5: Evidence is monotonic (append-only).
 // Implements Property 9: Conflicting evidence widens belief.
 type EvidenceSet struct {
-	evidence []Evidence
-	halfLife uint64
+	evidence    []Evidence
+	byTime      []timeIndex // evidence positions sorted by timestamp
+	halfLife    uint64
+	maxEvidence int // 0 = unbounded
+}
+
+// timeIndex locates one evidence record in the timestamp index.
+type timeIndex struct {
+	ts  styxtime.LogicalTimestamp
+	pos int
 }
 
 // NewEvidenceSet creates a new, empty evidence set.
@@ -34,9 +46,126 @@ func WithHalfLife(halfLife uint64) *EvidenceSet {
 	}
 }
 
+// WithMaxEvidence creates an evidence set bounded to maxItems records.
+// Every Add trims the set back to maxItems (see TrimToSize).
+// A non-positive maxItems means unbounded.
+func WithMaxEvidence(maxItems int) *EvidenceSet {
+	es := NewEvidenceSet()
+	if maxItems > 0 {
+		es.maxEvidence = maxItems
+	}
+	return es
+}
+
 // Add appends new evidence (monotonic, per Property 5).
+// Bounded sets are trimmed afterwards.
 func (es *EvidenceSet) Add(e Evidence) {
 	es.evidence = append(es.evidence, e)
+	es.index(e.Timestamp, len(es.evidence)-1)
+	if es.maxEvidence > 0 && len(es.evidence) > es.maxEvidence {
+		es.TrimToSize(es.maxEvidence)
+	}
+}
+
+// AddValidated is Add for evidence that passed Validate.
+// Invalid evidence is not added and the Validate error is returned.
+func (es *EvidenceSet) AddValidated(e Evidence) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	es.Add(e)
+	return nil
+}
+
+// TrimToSize evicts the oldest evidence (by logical timestamp) until at
+// most maxItems records remain.
+//
+// The most recent alive evidence and the most recent dead evidence are
+// never evicted, so the direction of the belief survives trimming. If
+// maxItems is smaller than the number of protected records, the set is
+// left with just those. A non-positive maxItems is ignored.
+func (es *EvidenceSet) TrimToSize(maxItems int) {
+	if maxItems <= 0 || len(es.evidence) <= maxItems {
+		return
+	}
+
+	latestAlive, latestDead := -1, -1
+	for i, e := range es.evidence {
+		if e.SuggestsAlive() && (latestAlive < 0 || e.Timestamp >= es.evidence[latestAlive].Timestamp) {
+			latestAlive = i
+		}
+		if e.SuggestsDead() && (latestDead < 0 || e.Timestamp >= es.evidence[latestDead].Timestamp) {
+			latestDead = i
+		}
+	}
+
+	// the index already lists candidates oldest first
+	evictCount := len(es.evidence) - maxItems
+	evict := make(map[int]bool, evictCount)
+	for _, ix := range es.byTime {
+		if len(evict) == evictCount {
+			break
+		}
+		if ix.pos != latestAlive && ix.pos != latestDead {
+			evict[ix.pos] = true
+		}
+	}
+
+	kept := make([]Evidence, 0, len(es.evidence)-len(evict))
+	for i, e := range es.evidence {
+		if !evict[i] {
+			kept = append(kept, e)
+		}
+	}
+	es.evidence = kept
+	es.reindex()
+}
+
+// index inserts the record at pos into the timestamp index.
+// Records with equal timestamps stay in insertion order. Evidence mostly
+// arrives in timestamp order, which makes this an append.
+func (es *EvidenceSet) index(ts styxtime.LogicalTimestamp, pos int) {
+	i := sort.Search(len(es.byTime), func(i int) bool { return es.byTime[i].ts > ts })
+	es.byTime = append(es.byTime, timeIndex{})
+	copy(es.byTime[i+1:], es.byTime[i:])
+	es.byTime[i] = timeIndex{ts: ts, pos: pos}
+}
+
+// reindex rebuilds the timestamp index after records were removed.
+func (es *EvidenceSet) reindex() {
+	es.byTime = es.byTime[:0]
+	for i, e := range es.evidence {
+		es.byTime = append(es.byTime, timeIndex{ts: e.Timestamp, pos: i})
+	}
+	sort.SliceStable(es.byTime, func(a, b int) bool { return es.byTime[a].ts < es.byTime[b].ts })
+}
+
+// searchTime returns the first index position with a timestamp >= ts.
+func (es *EvidenceSet) searchTime(ts styxtime.LogicalTimestamp) int {
+	return sort.Search(len(es.byTime), func(i int) bool { return es.byTime[i].ts >= ts })
+}
+
+// FilterByTimeRange returns the evidence stamped within [from, to], oldest
+// first. The lookup is a binary search on the timestamp index, so the cost
+// depends on the size of the range rather than of the set.
+func (es *EvidenceSet) FilterByTimeRange(from, to styxtime.LogicalTimestamp) []Evidence {
+	if from > to {
+		return nil
+	}
+	start := es.searchTime(from)
+	result := make([]Evidence, 0)
+	for _, ix := range es.byTime[start:] {
+		if ix.ts > to {
+			break
+		}
+		result = append(result, es.evidence[ix.pos])
+	}
+	return result
+}
+
+// MemoryBytes estimates the memory held by evidence records.
+func (es *EvidenceSet) MemoryBytes() int {
+	return len(es.evidence) * EvidenceSizeBytes
 }
 
 // Len returns the number of evidence records.
@@ -62,14 +191,33 @@ func (es *EvidenceSet) All() []Evidence {
 //   - Property 9: Conflicting evidence widens belief (more conflict → more uncertainty)
 //   - Property 18: Confidence sums to 1
 func (es *EvidenceSet) ComputeBelief(now styxtime.LogicalTimestamp) types.Belief {
+	return es.ComputeBeliefWithHalfLife(now, es.halfLife)
+}
+
+// ZeroWeightHalfLives is the age, in half-lives, beyond which evidence
+// weight underflows to exactly zero.
+const ZeroWeightHalfLives = 1075
+
+// decayCutoff returns the oldest timestamp still carrying weight at now.
+func decayCutoff(now styxtime.LogicalTimestamp, halfLife uint64) styxtime.LogicalTimestamp {
+	if halfLife > math.MaxUint64/ZeroWeightHalfLives {
+		return 0
+	}
+	return now.Subtract(halfLife * ZeroWeightHalfLives)
+}
+
+// ComputeBeliefWithHalfLife is ComputeBelief with the set's half-life
+// replaced by halfLife, e.g. one configured for the witness that gathered
+// the evidence. A zero halfLife falls back to the set's own.
+func (es *EvidenceSet) ComputeBeliefWithHalfLife(now styxtime.LogicalTimestamp, halfLife uint64) types.Belief {
+	halfLife = es.resolveHalfLife(halfLife)
 	if es.IsEmpty() {
 		return types.UnknownBelief() // Property 8: Unknown is always allowed
 	}
 
 	var aliveWeight, deadWeight, totalWeight float64
-
-	for _, e := range es.evidence {
-		w := e.EffectiveWeight(now, es.halfLife)
+	add := func(e Evidence) {
+		w := e.EffectiveWeight(now, halfLife)
 		totalWeight += w
 
 		if e.SuggestsAlive() {
@@ -79,6 +227,18 @@ func (es *EvidenceSet) ComputeBelief(now styxtime.LogicalTimestamp) types.Belief
 		}
 	}
 
+	// Evidence older than the cutoff has decayed to exactly zero weight,
+	// the index skips it without looking at each record
+	if skip := es.searchTime(decayCutoff(now, halfLife)); skip > 0 {
+		for _, ix := range es.byTime[skip:] {
+			add(es.evidence[ix.pos])
+		}
+	} else {
+		for _, e := range es.evidence {
+			add(e)
+		}
+	}
+
 	if totalWeight < 1e-10 {
 		return types.UnknownBelief()
 	}
@@ -104,12 +264,7 @@ func (es *EvidenceSet) ComputeBelief(now styxtime.LogicalTimestamp) types.Belief
 	unknownConf := 1.0 - aliveConf - deadConf
 
 	// Property 8: Ensure unknown is never zero
-	if unknownConf < 0.05 {
-		excess := 0.05 - unknownConf
-		aliveConf -= excess / 2
-		deadConf -= excess / 2
-		unknownConf = 0.05
-	}
+	aliveConf, deadConf, unknownConf = types.ApplyUnknownFloor(aliveConf, deadConf, unknownConf)
 
 	belief, err := types.NewBelief(aliveConf, deadConf, unknownConf)
 	if err != nil {
@@ -118,6 +273,18 @@ func (es *EvidenceSet) ComputeBelief(now styxtime.LogicalTimestamp) types.Belief
 	return belief
 }
 
+// Contribution attributes the current belief to its sources.
+// It returns the total effective (decayed) weight of each evidence kind at
+// now; the values sum to the weight ComputeBelief works with, so dividing
+// by that sum gives each kind's share. Kinds with no evidence are absent.
+func (es *EvidenceSet) Contribution(now styxtime.LogicalTimestamp) map[EvidenceKind]float64 {
+	contrib := make(map[EvidenceKind]float64)
+	for _, e := range es.evidence {
+		contrib[e.Kind] += e.EffectiveWeight(now, es.halfLife)
+	}
+	return contrib
+}
+
 // ComputeBeliefNow computes belief using the latest evidence timestamp.
 func (es *EvidenceSet) ComputeBeliefNow() types.Belief {
 	var max styxtime.LogicalTimestamp
@@ -161,3 +328,55 @@ func (es *EvidenceSet) DeadEvidence() []Evidence {
 	}
 	return result
 }
+
+// FilterBySource returns the evidence gathered by source, in insertion order.
+// It scans the whole set.
+func (es *EvidenceSet) FilterBySource(source types.NodeID) []Evidence {
+	result := make([]Evidence, 0)
+	for _, e := range es.evidence {
+		if e.Source == source {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// FilterByTarget returns the evidence about target, in insertion order.
+// It scans the whole set.
+func (es *EvidenceSet) FilterByTarget(target types.NodeID) []Evidence {
+	result := make([]Evidence, 0)
+	for _, e := range es.evidence {
+		if e.Target == target {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// SumWeightBySource returns the total effective (decayed) weight at now of
+// the evidence gathered by source. Like ComputeBeliefWithHalfLife it decays
+// with halfLife, or with the set's half-life when halfLife is 0, so the
+// weights add up to what the belief was computed from.
+func (es *EvidenceSet) SumWeightBySource(source types.NodeID, now styxtime.LogicalTimestamp, halfLife uint64) float64 {
+	halfLife = es.resolveHalfLife(halfLife)
+	var sum float64
+	for _, e := range es.evidence {
+		if e.Source == source {
+			sum += e.EffectiveWeight(now, halfLife)
+		}
+	}
+	return sum
+}
+
+// HalfLife returns the half-life the set decays with unless told otherwise.
+func (es *EvidenceSet) HalfLife() uint64 {
+	return es.halfLife
+}
+
+// resolveHalfLife returns halfLife, or the set's half-life when it is 0.
+func (es *EvidenceSet) resolveHalfLife(halfLife uint64) uint64 {
+	if halfLife == 0 {
+		return es.halfLife
+	}
+	return halfLife
+}
@@ -0,0 +1,363 @@
+package evidence
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+)
+
+func setUnknownFloor(t *testing.T, floor float64) {
+	t.Helper()
+	prev := types.UnknownFloor()
+	if err := types.SetUnknownFloor(floor); err != nil {
+		t.Fatalf("SetUnknownFloor(%f): %v", floor, err)
+	}
+	t.Cleanup(func() { types.SetUnknownFloor(prev) })
+}
+
+// TestComputeBeliefHonorsUnknownFloor checks Property 8 with a raised floor
+func TestComputeBeliefHonorsUnknownFloor(t *testing.T) {
+	setUnknownFloor(t, 0.3)
+
+	source := types.NewNodeID(1)
+	target := types.NewNodeID(2)
+	es := NewEvidenceSet()
+	for i := 0; i < 20; i++ {
+		es.Add(NewDirectResponse(styxtime.LogicalTimestamp(10), 5, source, target))
+	}
+
+	belief := es.ComputeBelief(styxtime.LogicalTimestamp(10))
+	if belief.Unknown().Value() < 0.3-types.BeliefSumEpsilon {
+		t.Errorf("unknown below floor: %f", belief.Unknown().Value())
+	}
+	if !belief.IsValid() {
+		t.Errorf("invalid belief: %s", belief)
+	}
+	if belief.Dominant() != types.StateAlive {
+		t.Errorf("expected alive-leaning belief, got %s", belief)
+	}
+}
+
+func TestTrimToSize(t *testing.T) {
+	source := types.NewNodeID(1)
+	target := types.NewNodeID(2)
+	es := NewEvidenceSet()
+
+	// One early timeout, then a long run of healthy responses
+	es.Add(NewTimeout(styxtime.LogicalTimestamp(1), 100, 500, source, target))
+	for i := 2; i <= 1000; i++ {
+		es.Add(NewDirectResponse(styxtime.LogicalTimestamp(i), 5, source, target))
+	}
+
+	es.TrimToSize(100)
+
+	if es.Len() != 100 {
+		t.Fatalf("expected 100 items, got %d", es.Len())
+	}
+	if len(es.DeadEvidence()) != 1 {
+		t.Errorf("latest dead evidence was evicted")
+	}
+	if es.MemoryBytes() != 100*EvidenceSizeBytes {
+		t.Errorf("unexpected memory estimate: %d", es.MemoryBytes())
+	}
+
+	belief := es.ComputeBeliefNow()
+	if !belief.IsValid() {
+		t.Errorf("invalid belief after trim: %s", belief)
+	}
+	if belief.Dominant() != types.StateAlive {
+		t.Errorf("expected alive after trim, got %s", belief)
+	}
+}
+
+func TestWithMaxEvidenceTrimsOnAdd(t *testing.T) {
+	source := types.NewNodeID(1)
+	target := types.NewNodeID(2)
+	es := WithMaxEvidence(10)
+
+	for i := 1; i <= 50; i++ {
+		es.Add(NewDirectResponse(styxtime.LogicalTimestamp(i), 5, source, target))
+		if es.Len() > 10 {
+			t.Fatalf("set grew past bound: %d", es.Len())
+		}
+	}
+	if es.LatestTimestamp() != 50 {
+		t.Errorf("newest evidence was evicted")
+	}
+}
+
+func TestApplicationCheckpointCountersTimeouts(t *testing.T) {
+	src, target := types.NewNodeID(1), types.NewNodeID(2)
+	es := NewEvidenceSet()
+	for i := 1; i <= 10; i++ {
+		es.Add(NewTimeout(styxtime.LogicalTimestamp(i), 100, 1000, src, target))
+	}
+	stormOnly := es.ComputeBelief(11)
+
+	checkpoint := NewApplicationCheckpoint(11, 42, src, target)
+	if !checkpoint.SuggestsAlive() || checkpoint.Weight != 1.0 {
+		t.Fatalf("checkpoint should be full-weight alive evidence: %v", checkpoint)
+	}
+	if checkpoint.Details.CheckpointSeq != 42 {
+		t.Errorf("CheckpointSeq = %d, want 42", checkpoint.Details.CheckpointSeq)
+	}
+	es.Add(checkpoint)
+
+	// P15: one checkpoint pulls a timeout storm well away from death
+	b := es.ComputeBelief(11)
+	if b.Dead().Value() >= stormOnly.Dead().Value() {
+		t.Errorf("checkpoint did not reduce dead confidence: %v -> %v", stormOnly, b)
+	}
+	if b.Alive().Value() < 0.1 {
+		t.Errorf("checkpoint left almost no alive mass: %v", b)
+	}
+}
+
+func TestDefaultWeightProfileMatchesConstructors(t *testing.T) {
+	src, target := types.NewNodeID(1), types.NewNodeID(2)
+	p := DefaultWeightProfile()
+	for _, latency := range []uint64{0, 99, 100, 999, 1000, 5000} {
+		if got, want := p.NewDirectResponse(1, latency, src, target).Weight, NewDirectResponse(1, latency, src, target).Weight; got != want {
+			t.Errorf("latency %dms: profile weight %f, constructor %f", latency, got, want)
+		}
+	}
+	for _, waited := range []uint64{100, 400, 2000} {
+		if got, want := p.NewTimeout(1, 100, waited, src, target).Weight, NewTimeout(1, 100, waited, src, target).Weight; got != want {
+			t.Errorf("waited %dms: profile weight %f, constructor %f", waited, got, want)
+		}
+	}
+}
+
+func TestWANProfileTrustsSlowerResponses(t *testing.T) {
+	src, target := types.NewNodeID(1), types.NewNodeID(2)
+	lan := DefaultWeightProfile().NewDirectResponse(1, 500, src, target)
+	wan := WANWeightProfile().NewDirectResponse(1, 500, src, target)
+	if wan.Weight <= lan.Weight {
+		t.Errorf("500ms response: WAN weight %f should exceed LAN weight %f", wan.Weight, lan.Weight)
+	}
+
+	// P15: the profile changes response curves, not timeout strength
+	if w := WANWeightProfile().NewTimeout(1, 100, 10000, src, target).Weight; w > 0.3 {
+		t.Errorf("WAN timeout weight %f above cap", w)
+	}
+}
+
+func TestAddValidatedRejectsInconsistentEvidence(t *testing.T) {
+	src, target := types.NewNodeID(1), types.NewNodeID(2)
+
+	heavy := NewCausalEvent(1, 7, src, target)
+	heavy.Weight = 1.5
+	selfReport := NewTimeout(1, 100, 300, src, src)
+	noLatency := NewDirectResponse(1, 0, src, target)
+	unknownKind := NewTimeout(1, 100, 300, src, target)
+	unknownKind.Kind = EvidenceKind(99)
+
+	es := NewEvidenceSet()
+	for _, e := range []Evidence{heavy, selfReport, noLatency, unknownKind} {
+		if err := es.AddValidated(e); !errors.Is(err, ErrInvalidEvidence) {
+			t.Errorf("AddValidated(%v) = %v, want ErrInvalidEvidence", e, err)
+		}
+	}
+	if es.Len() != 0 {
+		t.Fatalf("rejected evidence was added: %d items", es.Len())
+	}
+
+	if err := es.AddValidated(NewDirectResponse(1, 5, src, target)); err != nil {
+		t.Errorf("valid evidence rejected: %v", err)
+	}
+
+	// Add stays unchecked for existing callers
+	es.Add(heavy)
+	if es.Len() != 2 {
+		t.Errorf("Add rejected evidence: %d items, want 2", es.Len())
+	}
+}
+
+func TestContributionAttributesEffectiveWeight(t *testing.T) {
+	src, dst := types.NewNodeID(1), types.NewNodeID(2)
+	es := NewEvidenceSet()
+	es.Add(NewTimeout(0, 100, 200, src, dst))
+	es.Add(NewDirectResponse(100, 5, src, dst))
+	es.Add(NewCausalEvent(100, 1, src, dst))
+	es.Add(NewSchedulingJitter(50, 10, src, dst))
+	es.Add(NewApplicationCheckpoint(100, 3, src, dst))
+
+	now := styxtime.LogicalTimestamp(100)
+	contrib := es.Contribution(now)
+
+	var sum, want float64
+	for _, w := range contrib {
+		sum += w
+	}
+	for _, e := range es.All() {
+		want += e.EffectiveWeight(now, DefaultHalfLife)
+	}
+	if math.Abs(sum-want) > 1e-9 {
+		t.Errorf("contributions sum to %v, want total effective weight %v", sum, want)
+	}
+
+	// A timeout one half-life old contributes half as much as a fresh one
+	fresh := NewEvidenceSet()
+	fresh.Add(NewTimeout(100, 100, 200, src, dst))
+	old, young := contrib[KindTimeout], fresh.Contribution(now)[KindTimeout]
+	if math.Abs(old-young/2) > 1e-9 {
+		t.Errorf("old timeout contributes %v, fresh %v, want half", old, young)
+	}
+	if _, ok := contrib[KindNetworkInstability]; ok {
+		t.Error("kind without evidence should be absent")
+	}
+}
+
+func TestNewNetworkInstabilityWeight(t *testing.T) {
+	src, dst := types.NewNodeID(1), types.NewNodeID(2)
+	tests := []struct {
+		loss     float64
+		variance uint64
+		want     float64
+	}{
+		{0, 0, 0},
+		{0.2, 1000, 0.13},
+		{0.8, 0, 0.4},
+		{1, 10000, 0.7}, // 0.8 capped
+		{math.NaN(), 0, 0},
+	}
+	for _, tt := range tests {
+		e := NewNetworkInstability(0, tt.loss, tt.variance, src, dst)
+		if math.Abs(e.Weight-tt.want) > 1e-9 {
+			t.Errorf("loss %v variance %d: weight %v, want %v", tt.loss, tt.variance, e.Weight, tt.want)
+		}
+		if !e.SuggestsNetworkIssue() || e.SuggestsAlive() || e.SuggestsDead() {
+			t.Errorf("network instability should only suggest a network issue")
+		}
+		if err := e.Validate(); err != nil {
+			t.Errorf("constructor produced invalid evidence: %v", err)
+		}
+	}
+}
+
+// checkIndex verifies the timestamp index lists every record once, in
+// timestamp order
+func checkIndex(t *testing.T, es *EvidenceSet) {
+	t.Helper()
+	if len(es.byTime) != len(es.evidence) {
+		t.Fatalf("index has %d entries for %d records", len(es.byTime), len(es.evidence))
+	}
+	seen := make(map[int]bool, len(es.byTime))
+	for i, ix := range es.byTime {
+		if seen[ix.pos] || es.evidence[ix.pos].Timestamp != ix.ts {
+			t.Fatalf("index entry %d %+v is stale", i, ix)
+		}
+		seen[ix.pos] = true
+		if i > 0 && es.byTime[i-1].ts > ix.ts {
+			t.Fatalf("index out of order at %d", i)
+		}
+	}
+}
+
+func TestFilterByTimeRange(t *testing.T) {
+	source := types.NewNodeID(1)
+	target := types.NewNodeID(2)
+	es := WithMaxEvidence(8)
+
+	// out of order arrivals and duplicate timestamps
+	for _, ts := range []styxtime.LogicalTimestamp{5, 3, 9, 3, 7, 1, 12, 10, 4, 11} {
+		es.Add(NewDirectResponse(ts, 5, source, target))
+		checkIndex(t, es)
+	}
+	es.Add(NewTimeout(2, 100, 500, source, target))
+	checkIndex(t, es)
+	es.TrimToSize(5)
+	checkIndex(t, es)
+
+	got := es.FilterByTimeRange(4, 11)
+	var want []styxtime.LogicalTimestamp
+	for _, e := range es.All() {
+		if e.Timestamp >= 4 && e.Timestamp <= 11 {
+			want = append(want, e.Timestamp)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d records in [4,11], want %d", len(got), len(want))
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i-1].Timestamp > got[i].Timestamp {
+			t.Errorf("range not oldest first: %v then %v", got[i-1].Timestamp, got[i].Timestamp)
+		}
+	}
+	if len(es.FilterByTimeRange(11, 4)) != 0 || len(es.FilterByTimeRange(100, 200)) != 0 {
+		t.Error("empty ranges returned evidence")
+	}
+}
+
+func TestComputeBeliefSkipsFullyDecayedEvidence(t *testing.T) {
+	source := types.NewNodeID(1)
+	target := types.NewNodeID(2)
+	old := WithHalfLife(1)
+	fresh := WithHalfLife(1)
+	for i := range 50 {
+		old.Add(NewTimeout(styxtime.LogicalTimestamp(i), 100, 500, source, target))
+	}
+	for _, es := range []*EvidenceSet{old, fresh} {
+		es.Add(NewDirectResponse(5000, 5, source, target))
+		es.Add(NewDirectResponse(5001, 8, source, target))
+	}
+
+	// the timeouts are thousands of half-lives old and weigh nothing
+	now := styxtime.LogicalTimestamp(5002)
+	if got, want := old.ComputeBelief(now), fresh.ComputeBelief(now); !got.Equal(want) {
+		t.Errorf("decayed evidence changed belief: %v, want %v", got, want)
+	}
+}
+
+func TestFilterBySource(t *testing.T) {
+	a, b, c := types.NewNodeID(1), types.NewNodeID(2), types.NewNodeID(3)
+	target, other := types.NewNodeID(9), types.NewNodeID(8)
+	es := NewEvidenceSet()
+	es.Add(NewDirectResponse(1, 5, a, target))
+	es.Add(NewTimeout(2, 100, 500, b, target))
+	es.Add(NewDirectResponse(3, 8, c, other))
+	es.Add(NewCausalEvent(4, 7, b, target))
+	es.Add(NewDirectResponse(5, 6, a, other))
+
+	got := es.FilterBySource(b)
+	if len(got) != 2 || got[0].Kind != KindTimeout || got[1].Kind != KindCausalEvent {
+		t.Fatalf("FilterBySource(b) = %v, want b's timeout then causal event", got)
+	}
+	for _, src := range []types.NodeID{a, b, c} {
+		for _, e := range es.FilterBySource(src) {
+			if e.Source != src {
+				t.Errorf("FilterBySource(%v) returned evidence from %v", src, e.Source)
+			}
+		}
+	}
+	if n := len(es.FilterBySource(a)) + len(es.FilterBySource(b)) + len(es.FilterBySource(c)); n != es.Len() {
+		t.Errorf("sources cover %d records, want %d", n, es.Len())
+	}
+	if len(es.FilterBySource(types.NewNodeID(4))) != 0 {
+		t.Error("unknown source returned evidence")
+	}
+	if got := es.FilterByTarget(other); len(got) != 2 || got[0].Source != c || got[1].Source != a {
+		t.Errorf("FilterByTarget(other) = %v", got)
+	}
+
+	now := styxtime.LogicalTimestamp(5)
+	var want float64
+	for _, e := range es.FilterBySource(b) {
+		want += e.EffectiveWeight(now, DefaultHalfLife)
+	}
+	if got := es.SumWeightBySource(b, now, 0); math.Abs(got-want) > 1e-9 || got == 0 {
+		t.Errorf("SumWeightBySource(b) = %v, want %v", got, want)
+	}
+
+	// an explicit half-life decays like ComputeBeliefWithHalfLife does
+	want = 0
+	for _, e := range es.FilterBySource(b) {
+		want += e.EffectiveWeight(now, 1)
+	}
+	if got := es.SumWeightBySource(b, now, 1); math.Abs(got-want) > 1e-9 {
+		t.Errorf("SumWeightBySource(b, half-life 1) = %v, want %v", got, want)
+	}
+}
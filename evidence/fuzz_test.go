@@ -0,0 +1,52 @@
+package evidence
+
+import (
+	"math"
+	"testing"
+
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+)
+
+// Run locally or in CI with:
+//
+//	go test -run=^$ -fuzz=FuzzComputeBelief -fuzztime=30s ./evidence
+
+// FuzzComputeBelief checks ComputeBelief never panics, hangs or yields an invalid belief
+func FuzzComputeBelief(f *testing.F) {
+	// nAlive, nDead, nJitter, weight, evidence ts, now, halfLife
+	f.Add(uint8(0), uint8(0), uint8(0), 1.0, uint64(0), uint64(0), DefaultHalfLife)        // empty set
+	f.Add(uint8(3), uint8(3), uint8(3), 0.0, uint64(5), uint64(5), DefaultHalfLife)        // all zero weight
+	f.Add(uint8(2), uint8(1), uint8(0), 7.5, uint64(5), uint64(10), DefaultHalfLife)       // weight > 1
+	f.Add(uint8(4), uint8(4), uint8(1), 1.0, uint64(0), uint64(math.MaxUint64), uint64(1)) // decays to zero
+	f.Add(uint8(1), uint8(1), uint8(1), 1.0, uint64(1), uint64(100), uint64(0))            // zero half-life
+	f.Add(uint8(5), uint8(0), uint8(0), math.Inf(1), uint64(1), uint64(1), DefaultHalfLife)
+	f.Add(uint8(0), uint8(5), uint8(0), math.NaN(), uint64(1), uint64(1), DefaultHalfLife)
+	f.Add(uint8(1), uint8(5), uint8(2), -1.0, uint64(1), uint64(1), DefaultHalfLife)
+
+	source := types.NewNodeID(1)
+	target := types.NewNodeID(2)
+
+	f.Fuzz(func(t *testing.T, nAlive, nDead, nJitter uint8, weight float64, ts, now, halfLife uint64) {
+		es := WithHalfLife(halfLife)
+		stamp := styxtime.LogicalTimestamp(ts)
+		add := func(n uint8, kind EvidenceKind) {
+			for i := uint8(0); i < n; i++ {
+				es.Add(Evidence{Kind: kind, Timestamp: stamp, Weight: weight, Source: source, Target: target})
+			}
+		}
+		add(nAlive, KindDirectResponse)
+		add(nDead, KindTimeout)
+		add(nJitter, KindSchedulingJitter)
+
+		b := es.ComputeBelief(styxtime.LogicalTimestamp(now))
+		for _, v := range []float64{b.Alive().Value(), b.Dead().Value(), b.Unknown().Value()} {
+			if math.IsNaN(v) {
+				t.Fatalf("NaN component in %s", b)
+			}
+		}
+		if !b.IsValid() {
+			t.Fatalf("invalid belief %s", b)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package evidence
+
+import (
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+)
+
+// WeightProfile parameterizes how latency and timeouts map to evidence
+// weight.
+//
+// Different environments want different curves: 500ms is a sluggish reply
+// on a LAN but a perfectly normal one across continents. The profile only
+// shapes the weights; Property 15 (silence ≠ death) still holds as long as
+// TimeoutCap stays well below the direct response weights.
+type WeightProfile struct {
+	// FastResponseMS and SlowResponseMS split responses into three tiers.
+	// Latency below FastResponseMS is fast, at or above SlowResponseMS slow.
+	FastResponseMS uint64
+	SlowResponseMS uint64
+
+	// Weights of a fast, medium and slow direct response.
+	FastResponseWeight   float64
+	MediumResponseWeight float64
+	SlowResponseWeight   float64
+
+	// TimeoutWeight is the weight of a timeout waited at most
+	// LongTimeoutRatio times the expected duration.
+	TimeoutWeight float64
+
+	// LongTimeoutRatio and LongTimeoutWeight apply once the wait exceeds
+	// LongTimeoutRatio times the expected duration.
+	LongTimeoutRatio  float64
+	LongTimeoutWeight float64
+
+	// ExtremeTimeoutRatio and ExtremeTimeoutWeight apply once the wait
+	// exceeds ExtremeTimeoutRatio times the expected duration.
+	ExtremeTimeoutRatio  float64
+	ExtremeTimeoutWeight float64
+
+	// TimeoutCap bounds the weight of any single timeout, including after
+	// jitter adjustment. Even a very long silence is weak evidence.
+	TimeoutCap float64
+}
+
+// DefaultWeightProfile returns the weights tuned for a local network.
+// These are the weights NewDirectResponse and NewTimeout use.
+func DefaultWeightProfile() WeightProfile {
+	return WeightProfile{
+		FastResponseMS:       100,
+		SlowResponseMS:       1000,
+		FastResponseWeight:   1.0,
+		MediumResponseWeight: 0.8,
+		SlowResponseWeight:   0.6,
+		TimeoutWeight:        0.1,
+		LongTimeoutRatio:     3.0,
+		LongTimeoutWeight:    0.2,
+		ExtremeTimeoutRatio:  10.0,
+		ExtremeTimeoutWeight: 0.3,
+		TimeoutCap:           0.3,
+	}
+}
+
+// WANWeightProfile returns weights for wide-area deployments, where round
+// trips of several hundred milliseconds are normal and should not weaken
+// the evidence.
+func WANWeightProfile() WeightProfile {
+	p := DefaultWeightProfile()
+	p.FastResponseMS = 750
+	p.SlowResponseMS = 5000
+	return p
+}
+
+// ResponseWeight returns the weight of a direct response with the given
+// latency.
+func (p WeightProfile) ResponseWeight(latencyMS uint64) float64 {
+	switch {
+	case latencyMS < p.FastResponseMS:
+		return p.FastResponseWeight
+	case latencyMS < p.SlowResponseMS:
+		return p.MediumResponseWeight
+	default:
+		return p.SlowResponseWeight
+	}
+}
+
+// TimeoutWeightFor returns the weight of a timeout after waiting waitedMS
+// for a response expected within expectedMS. The result never exceeds
+// TimeoutCap.
+func (p WeightProfile) TimeoutWeightFor(expectedMS, waitedMS uint64) float64 {
+	ratio := float64(waitedMS) / float64(expectedMS)
+	weight := p.TimeoutWeight
+	if ratio > p.ExtremeTimeoutRatio {
+		weight = p.ExtremeTimeoutWeight // Still weak - silence ≠ death
+	} else if ratio > p.LongTimeoutRatio {
+		weight = p.LongTimeoutWeight
+	}
+	return p.CapTimeout(weight)
+}
+
+// CapTimeout clamps a timeout weight to TimeoutCap.
+func (p WeightProfile) CapTimeout(weight float64) float64 {
+	if weight > p.TimeoutCap {
+		return p.TimeoutCap
+	}
+	return weight
+}
+
+// NewDirectResponse creates evidence of a direct response weighted by this
+// profile.
+func (p WeightProfile) NewDirectResponse(ts styxtime.LogicalTimestamp, latencyMS uint64, source, target types.NodeID) Evidence {
+	return Evidence{
+		Kind:      KindDirectResponse,
+		Timestamp: ts,
+		Weight:    p.ResponseWeight(latencyMS),
+		Source:    source,
+		Target:    target,
+		Details:   EvidenceDetails{LatencyMS: latencyMS},
+	}
+}
+
+// NewTimeout creates evidence of a timeout weighted by this profile.
+// Per Property 4 and 15: timeouts are WEAK evidence, never proof of death.
+func (p WeightProfile) NewTimeout(ts styxtime.LogicalTimestamp, expectedMS, waitedMS uint64, source, target types.NodeID) Evidence {
+	return Evidence{
+		Kind:      KindTimeout,
+		Timestamp: ts,
+		Weight:    p.TimeoutWeightFor(expectedMS, waitedMS),
+		Source:    source,
+		Target:    target,
+		Details:   EvidenceDetails{ExpectedMS: expectedMS, WaitedMS: waitedMS},
+	}
+}
@@ -10,10 +10,12 @@ import (
 
 // Errors
 var (
-	ErrAlreadyDead          = errors.New("node already declared dead")
-	ErrInsufficientEvidence = errors.New("insufficient evidence for death declaration")
-	ErrSilenceOnly          = errors.New("cannot declare death from silence alone")
-	ErrResurrection         = errors.New("cannot resurrect a dead node")
+	ErrAlreadyDead               = errors.New("node already declared dead")
+	ErrInsufficientEvidence      = errors.New("insufficient evidence for death declaration")
+	ErrSilenceOnly               = errors.New("cannot declare death from silence alone")
+	ErrResurrection              = errors.New("cannot resurrect a dead node")
+	ErrInsufficientZoneDiversity = errors.New("death witnesses span too few zones")
+	ErrBatchAborted              = errors.New("batch aborted, another declaration failed")
 )
 
 // Thresholds for death declaration
@@ -41,19 +43,34 @@ type DeathRecord struct {
 type Engine struct {
 	mu       sync.RWMutex
 	dead     map[types.NodeID]*DeathRecord
+	lineage  map[uint64]uint64 // base -> highest dead generation
 	registry *witness.Registry
+	minZones int
 }
 
 // NewEngine creates a new finality engine
 func NewEngine(registry *witness.Registry) *Engine {
 	return &Engine{
 		dead:     make(map[types.NodeID]*DeathRecord),
+		lineage:  make(map[uint64]uint64),
 		registry: registry,
 	}
 }
 
+// SetZoneQuorum requires death witnesses from at least minZones distinct
+// zones (registry zone metadata) so one failing rack cant kill a node
+// witnesses without a zone count as their own zone
+// minZones <= 1 disables the check
+func (e *Engine) SetZoneQuorum(minZones int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.minZones = minZones
+}
+
 // IsDead checks if a node has been declared dead
 // P14: Once dead, always dead
+// matches the exact generation, a reborn node (same base, later generation)
+// is a new identity and not dead
 func (e *Engine) IsDead(id types.NodeID) bool {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
@@ -61,6 +78,16 @@ func (e *Engine) IsDead(id types.NodeID) bool {
 	return exists
 }
 
+// IsLineageDead reports whether any generation of base was declared dead
+// and the highest such generation
+// a rebirth must use a generation above it
+func (e *Engine) IsLineageDead(base uint64) (bool, uint64) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	gen, ok := e.lineage[base]
+	return ok, gen
+}
+
 // GetDeathRecord returns death record if exists
 func (e *Engine) GetDeathRecord(id types.NodeID) *DeathRecord {
 	e.mu.RLock()
@@ -87,46 +114,168 @@ func (e *Engine) DeclareDeath(
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	record, err := e.evaluate(nodeID, aggregatedBelief, witnessReports, hasNonTimeoutEvidence)
+	if err != nil {
+		return err
+	}
+	e.store(record)
+	return nil
+}
+
+// DeathDeclaration is one DeclareDeath call inside BatchDeclareDeath
+type DeathDeclaration struct {
+	NodeID                types.NodeID
+	Belief                types.Belief
+	Reports               []witness.WitnessReport
+	HasNonTimeoutEvidence bool
+}
+
+// BatchDeclareDeath declares several nodes dead at once, e.g. a whole
+// failed rack, so readers never see only part of the batch dead
+// all declarations are checked first and either all are committed under one
+// lock or none are
+// returns nil on success, otherwise one error per declaration: its own
+// check failure or ErrBatchAborted if it passed but was not committed
+// a node listed twice fails its second entry with ErrAlreadyDead
+func (e *Engine) BatchDeclareDeath(declarations []DeathDeclaration) []error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	records := make([]*DeathRecord, len(declarations))
+	errs := make([]error, len(declarations))
+	seen := make(map[types.NodeID]bool, len(declarations))
+	failed := false
+	for i, d := range declarations {
+		if seen[d.NodeID] {
+			errs[i] = ErrAlreadyDead
+			failed = true
+			continue
+		}
+		seen[d.NodeID] = true
+
+		records[i], errs[i] = e.evaluate(d.NodeID, d.Belief, d.Reports, d.HasNonTimeoutEvidence)
+		if errs[i] != nil {
+			failed = true
+		}
+	}
+
+	if failed {
+		for i := range errs {
+			if errs[i] == nil {
+				errs[i] = ErrBatchAborted
+			}
+		}
+		return errs
+	}
+
+	for _, record := range records {
+		e.store(record)
+	}
+	return nil
+}
+
+// Evaluate runs the DeclareDeath checks without declaring anything
+// Returns the record DeclareDeath would store, for callers that need
+// outside agreement (cluster consensus) before calling Commit
+func (e *Engine) Evaluate(
+	nodeID types.NodeID,
+	aggregatedBelief types.Belief,
+	witnessReports []witness.WitnessReport,
+	hasNonTimeoutEvidence bool,
+) (*DeathRecord, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.evaluate(nodeID, aggregatedBelief, witnessReports, hasNonTimeoutEvidence)
+}
+
+// Commit stores a record produced by Evaluate
+// P14: fails with ErrAlreadyDead if the node died in the meantime
+func (e *Engine) Commit(record *DeathRecord) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, exists := e.dead[record.NodeID]; exists {
+		return ErrAlreadyDead
+	}
+	copy := *record
+	e.store(&copy)
+	return nil
+}
+
+// store records a death, caller must hold e.mu for writing
+func (e *Engine) store(record *DeathRecord) {
+	id := record.NodeID
+	e.dead[id] = record
+	if gen, ok := e.lineage[id.Base]; !ok || id.Generation > gen {
+		e.lineage[id.Base] = id.Generation
+	}
+}
+
+// evaluate checks P13 P14 P15, caller must hold e.mu
+func (e *Engine) evaluate(
+	nodeID types.NodeID,
+	aggregatedBelief types.Belief,
+	witnessReports []witness.WitnessReport,
+	hasNonTimeoutEvidence bool,
+) (*DeathRecord, error) {
 	// P14: Already dead stays dead
 	if _, exists := e.dead[nodeID]; exists {
-		return ErrAlreadyDead
+		return nil, ErrAlreadyDead
 	}
 
 	// P13: Require overwhelming dead confidence
 	if aggregatedBelief.Dead().Value() < MinDeadConfidence {
-		return ErrInsufficientEvidence
+		return nil, ErrInsufficientEvidence
 	}
 
 	// P13: Require multiple witnesses
 	if len(witnessReports) < MinWitnesses {
-		return ErrInsufficientEvidence
+		return nil, ErrInsufficientEvidence
+	}
+
+	// Witnesses sharing a zone can share a failure
+	if e.minZones > 1 && e.zoneCount(witnessReports) < e.minZones {
+		return nil, ErrInsufficientZoneDiversity
 	}
 
 	// P15: Silence alone cannot trigger death
 	if !hasNonTimeoutEvidence {
-		return ErrSilenceOnly
+		return nil, ErrSilenceOnly
 	}
 
 	// P10: Check disagreement isnt too high
 	disagreement := calculateDisagreement(witnessReports)
 	if disagreement > MaxDisagreement {
-		return ErrInsufficientEvidence
+		return nil, ErrInsufficientEvidence
 	}
 
-	// All checks passed - declare death
+	// All checks passed
 	witnesses := make([]types.NodeID, len(witnessReports))
 	for i, r := range witnessReports {
 		witnesses[i] = r.Witness
 	}
 
-	e.dead[nodeID] = &DeathRecord{
+	return &DeathRecord{
 		NodeID:      nodeID,
 		FinalBelief: aggregatedBelief,
 		Witnesses:   witnesses,
 		Reason:      "overwhelming evidence from multiple witnesses",
-	}
+	}, nil
+}
 
-	return nil
+// zoneCount counts the distinct zones among report witnesses
+// a witness without a zone is its own zone
+func (e *Engine) zoneCount(reports []witness.WitnessReport) int {
+	zones := make(map[string]bool)
+	unzoned := make(map[types.NodeID]bool)
+	for _, r := range reports {
+		if zone := e.registry.Zone(r.Witness); zone != "" {
+			zones[zone] = true
+		} else {
+			unzoned[r.Witness] = true
+		}
+	}
+	return len(zones) + len(unzoned)
 }
 
 // AttemptResurrection tries to bring back a dead node
@@ -0,0 +1,131 @@
+package finality
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+func deadReports(target types.NodeID, n int) []witness.WitnessReport {
+	reports := make([]witness.WitnessReport, n)
+	for i := range reports {
+		reports[i] = witness.WitnessReport{
+			Witness: types.NewNodeID(uint64(10 + i)),
+			Target:  target,
+			Belief:  types.MustBelief(0.02, 0.95, 0.03),
+		}
+	}
+	return reports
+}
+
+func TestZoneQuorumRejectsSingleZone(t *testing.T) {
+	reg := witness.NewRegistry()
+	target := types.NewNodeID(100)
+	reports := deadReports(target, 5)
+	for _, r := range reports {
+		reg.SetZone(r.Witness, "rack-a")
+	}
+	// one zoned witness reporting twice is still one zone
+	reports = append(reports, reports[0])
+
+	e := NewEngine(reg)
+	e.SetZoneQuorum(2)
+	err := e.DeclareDeath(target, types.MustBelief(0.02, 0.95, 0.03), reports, true)
+	if !errors.Is(err, ErrInsufficientZoneDiversity) {
+		t.Fatalf("expected ErrInsufficientZoneDiversity, got %v", err)
+	}
+	if e.IsDead(target) {
+		t.Error("target declared dead from one zone")
+	}
+}
+
+func TestZoneQuorumAcceptsDistinctZones(t *testing.T) {
+	reg := witness.NewRegistry()
+	target := types.NewNodeID(100)
+	reports := deadReports(target, 3)
+	reg.SetZone(reports[0].Witness, "rack-a")
+	reg.SetZone(reports[1].Witness, "rack-b")
+	reg.SetZone(reports[2].Witness, "rack-c")
+
+	e := NewEngine(reg)
+	e.SetZoneQuorum(2)
+	if err := e.DeclareDeath(target, types.MustBelief(0.02, 0.95, 0.03), reports, true); err != nil {
+		t.Fatalf("DeclareDeath: %v", err)
+	}
+	if !e.IsDead(target) {
+		t.Error("target not dead")
+	}
+}
+
+func TestLineageDeathSparesRebirth(t *testing.T) {
+	e := NewEngine(witness.NewRegistry())
+	gen0 := types.NewNodeID(100)
+	gen1 := gen0.Rebirth()
+
+	if dead, _ := e.IsLineageDead(100); dead {
+		t.Fatal("fresh lineage reported dead")
+	}
+	if err := e.DeclareDeath(gen0, types.MustBelief(0.02, 0.95, 0.03), deadReports(gen0, 3), true); err != nil {
+		t.Fatalf("DeclareDeath: %v", err)
+	}
+
+	if !e.IsDead(gen0) {
+		t.Error("generation 0 not dead")
+	}
+	if e.IsDead(gen1) {
+		t.Error("reborn generation 1 treated as dead")
+	}
+	if dead, gen := e.IsLineageDead(100); !dead || gen != 0 {
+		t.Errorf("IsLineageDead = %v, %d; want true, 0", dead, gen)
+	}
+
+	// P13: generation 1 must earn its own death
+	if err := e.DeclareDeath(gen1, types.MustBelief(0.02, 0.95, 0.03), deadReports(gen1, 3), true); err != nil {
+		t.Fatalf("DeclareDeath gen 1: %v", err)
+	}
+	if dead, gen := e.IsLineageDead(100); !dead || gen != 1 {
+		t.Errorf("IsLineageDead = %v, %d; want true, 1", dead, gen)
+	}
+	if !e.IsDead(gen0) {
+		t.Error("P14: generation 0 resurrected")
+	}
+}
+
+func TestBatchDeclareDeathIsAtomic(t *testing.T) {
+	e := NewEngine(witness.NewRegistry())
+	dead := types.MustBelief(0.02, 0.95, 0.03)
+	a, b, c := types.NewNodeID(100), types.NewNodeID(101), types.NewNodeID(102)
+
+	batch := []DeathDeclaration{
+		{NodeID: a, Belief: dead, Reports: deadReports(a, 3), HasNonTimeoutEvidence: true},
+		{NodeID: b, Belief: dead, Reports: deadReports(b, 3), HasNonTimeoutEvidence: false},
+		{NodeID: c, Belief: dead, Reports: deadReports(c, 3), HasNonTimeoutEvidence: true},
+	}
+	errs := e.BatchDeclareDeath(batch)
+	if len(errs) != 3 {
+		t.Fatalf("expected one error per declaration, got %v", errs)
+	}
+	if !errors.Is(errs[1], ErrSilenceOnly) {
+		t.Errorf("declaration 2: expected ErrSilenceOnly, got %v", errs[1])
+	}
+	if !errors.Is(errs[0], ErrBatchAborted) || !errors.Is(errs[2], ErrBatchAborted) {
+		t.Errorf("valid declarations should be aborted, got %v", errs)
+	}
+	for _, id := range []types.NodeID{a, b, c} {
+		if e.IsDead(id) {
+			t.Errorf("%s committed from a failed batch", id)
+		}
+	}
+
+	batch[1].HasNonTimeoutEvidence = true
+	if errs := e.BatchDeclareDeath(batch); errs != nil {
+		t.Fatalf("valid batch failed: %v", errs)
+	}
+	for _, id := range []types.NodeID{a, b, c} {
+		if !e.IsDead(id) {
+			t.Errorf("%s not committed", id)
+		}
+	}
+}
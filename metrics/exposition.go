@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the media type of the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Metric types of the Prometheus text exposition format
+const (
+	TypeCounter = "counter"
+	TypeGauge   = "gauge"
+	TypeSummary = "summary"
+)
+
+// Family is one metric in the text exposition format: exactly one HELP and
+// one TYPE line followed by all of its samples
+// samples carry no timestamp, Prometheus stamps them at scrape time
+type Family struct {
+	Name    string
+	Type    string
+	Help    string
+	Samples []Sample
+}
+
+// Sample is one line of a family, Value is already formatted
+// Suffix extends the family name, e.g. "_sum" and "_count" of a summary
+type Sample struct {
+	Suffix string
+	Labels []Label
+	Value  string
+}
+
+// Label is a name="value" pair on a sample
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Int makes an integer sample
+func Int(v int64, labels ...Label) Sample {
+	return Sample{Labels: labels, Value: strconv.FormatInt(v, 10)}
+}
+
+// Float makes a float sample
+func Float(v float64, labels ...Label) Sample {
+	return Sample{Labels: labels, Value: formatFloat(v)}
+}
+
+// WithLabel returns families with l added in front of every sample's
+// labels, families is not modified
+func WithLabel(families []Family, l Label) []Family {
+	out := make([]Family, len(families))
+	for i, f := range families {
+		samples := make([]Sample, len(f.Samples))
+		for j, s := range f.Samples {
+			s.Labels = append([]Label{l}, s.Labels...)
+			samples[j] = s
+		}
+		f.Samples = samples
+		out[i] = f
+	}
+	return out
+}
+
+// WriteFamilies writes families in the text exposition format
+// families without samples are skipped
+func WriteFamilies(w io.Writer, families []Family) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		if len(f.Samples) == 0 {
+			continue
+		}
+		bw.WriteString("# HELP " + f.Name + " " + escapeHelp(f.Help) + "\n")
+		bw.WriteString("# TYPE " + f.Name + " " + f.Type + "\n")
+		for _, s := range f.Samples {
+			bw.WriteString(f.Name + s.Suffix)
+			writeLabels(bw, s.Labels)
+			bw.WriteString(" " + s.Value + "\n")
+		}
+	}
+	return bw.Flush()
+}
+
+func writeLabels(bw *bufio.Writer, labels []Label) {
+	if len(labels) == 0 {
+		return
+	}
+	bw.WriteByte('{')
+	for i, l := range labels {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		bw.WriteString(l.Name + `="` + escapeLabel(l.Value) + `"`)
+	}
+	bw.WriteByte('}')
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f, 'f', 3, 64)
+}
@@ -2,9 +2,11 @@ package metrics
 
 import (
 	"net/http"
-	"strconv"
+	"slices"
 	"sync"
 	"time"
+
+	"github.com/styx-oracle/styx/types"
 )
 
 // Metrics tracks STYX operational metrics
@@ -17,6 +19,9 @@ type Metrics struct {
 	RefusalsTotal      int64
 	DeathsTotal        int64
 	PartitionsDetected int64
+	QueryTimeoutsTotal int64
+	QueryCacheHits     int64
+	QueryCacheMisses   int64
 
 	// Gauges
 	WitnessCount   int
@@ -26,9 +31,20 @@ type Metrics struct {
 	// Histograms (simplified as averages)
 	QueryLatencySum   time.Duration
 	QueryLatencyCount int64
+
+	// Belief changes, keyed by target label
+	ConfidenceChangesTotal int64
+	dominantFlips          map[string]int64
+	currentBeliefs         map[string]types.Belief
+
+	// Per target queries, keyed by target label
+	targetQueries   map[string]*targetQueries
+	maxQueryTargets int
 }
 
 // Global metrics instance
+// an Oracle keeps its own Metrics, this one only collects what callers
+// send it explicitly
 var Default = &Metrics{}
 
 // RecordQuery records a query
@@ -45,6 +61,24 @@ func (m *Metrics) RecordQuery(latency time.Duration, refused bool) {
 	}
 }
 
+// RecordQueryTimeout records a query abandoned after its deadline
+func (m *Metrics) RecordQueryTimeout() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.QueryTimeoutsTotal++
+}
+
+// RecordQueryCache records whether a query was served from the query cache
+func (m *Metrics) RecordQueryCache(hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if hit {
+		m.QueryCacheHits++
+	} else {
+		m.QueryCacheMisses++
+	}
+}
+
 // RecordReport records a witness report
 func (m *Metrics) RecordReport() {
 	m.mu.Lock()
@@ -66,6 +100,43 @@ func (m *Metrics) RecordPartition() {
 	m.PartitionsDetected++
 }
 
+// RecordBeliefChange tracks how a target's belief moved between answers
+// Counts a flip only when the dominant state changes, small moves within
+// one state only count as a confidence change
+// Flapping nodes show up as a high flip count
+// targets past the WithMaxQueryTargets bound share the OtherTarget flip
+// count and get no belief gauges
+func (m *Metrics) RecordBeliefChange(target types.NodeID, old, new types.Belief) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.dominantFlips == nil {
+		m.dominantFlips = make(map[string]int64)
+		m.currentBeliefs = make(map[string]types.Belief)
+	}
+
+	label := seriesLabel(m.dominantFlips, target, m.maxQueryTargets)
+	if old.Dominant() != new.Dominant() {
+		m.dominantFlips[label]++
+	} else if _, ok := m.dominantFlips[label]; !ok {
+		m.dominantFlips[label] = 0
+	}
+	if !old.Equal(new) {
+		m.ConfidenceChangesTotal++
+	}
+	if label != OtherTarget {
+		m.currentBeliefs[label] = new
+	}
+}
+
+// DominantFlips returns how often target changed dominant state, zero if
+// it has no series of its own
+func (m *Metrics) DominantFlips(target types.NodeID) int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.dominantFlips[target.String()]
+}
+
 // SetWitnessCount sets current witness count
 func (m *Metrics) SetWitnessCount(count int) {
 	m.mu.Lock()
@@ -73,41 +144,70 @@ func (m *Metrics) SetWitnessCount(count int) {
 	m.WitnessCount = count
 }
 
-// Handler returns Prometheus-compatible metrics endpoint
-func (m *Metrics) Handler() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		m.mu.RLock()
-		defer m.mu.RUnlock()
-
-		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+// Families returns every metric as exposition families
+func (m *Metrics) Families() []Family {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
+	families := []Family{
 		// Counters
-		writeMetric(w, "styx_queries_total", "counter", "Total queries processed", m.QueriesTotal)
-		writeMetric(w, "styx_reports_total", "counter", "Total witness reports received", m.ReportsTotal)
-		writeMetric(w, "styx_refusals_total", "counter", "Total query refusals", m.RefusalsTotal)
-		writeMetric(w, "styx_deaths_total", "counter", "Total death declarations", m.DeathsTotal)
-		writeMetric(w, "styx_partitions_detected_total", "counter", "Total partitions detected", m.PartitionsDetected)
+		{"styx_queries_total", TypeCounter, "Total queries processed", []Sample{Int(m.QueriesTotal)}},
+		{"styx_reports_total", TypeCounter, "Total witness reports received", []Sample{Int(m.ReportsTotal)}},
+		{"styx_refusals_total", TypeCounter, "Total query refusals", []Sample{Int(m.RefusalsTotal)}},
+		{"styx_deaths_total", TypeCounter, "Total death declarations", []Sample{Int(m.DeathsTotal)}},
+		{"styx_partitions_detected_total", TypeCounter, "Total partitions detected", []Sample{Int(m.PartitionsDetected)}},
+		{"styx_query_timeouts_total", TypeCounter, "Total queries abandoned after the query timeout", []Sample{Int(m.QueryTimeoutsTotal)}},
+		{"styx_query_cache_hits_total", TypeCounter, "Total queries served from the query cache", []Sample{Int(m.QueryCacheHits)}},
+		{"styx_query_cache_misses_total", TypeCounter, "Total cached queries that had to re-aggregate", []Sample{Int(m.QueryCacheMisses)}},
 
 		// Gauges
-		writeMetric(w, "styx_witnesses", "gauge", "Current witness count", int64(m.WitnessCount))
-		writeMetric(w, "styx_active_nodes", "gauge", "Current active nodes", int64(m.ActiveNodes))
-
-		// Query latency
-		if m.QueryLatencyCount > 0 {
-			avgMs := float64(m.QueryLatencySum.Milliseconds()) / float64(m.QueryLatencyCount)
-			w.Write([]byte("# HELP styx_query_latency_avg_ms Average query latency in milliseconds\n"))
-			w.Write([]byte("# TYPE styx_query_latency_avg_ms gauge\n"))
-			w.Write([]byte("styx_query_latency_avg_ms " + formatFloat(avgMs) + "\n"))
-		}
+		{"styx_witnesses", TypeGauge, "Current witness count", []Sample{Int(int64(m.WitnessCount))}},
+		{"styx_active_nodes", TypeGauge, "Current active nodes", []Sample{Int(int64(m.ActiveNodes))}},
+
+		// Belief changes
+		{"styx_belief_confidence_changes_total", TypeCounter, "Total belief confidence changes", []Sample{Int(m.ConfidenceChangesTotal)}},
 	}
+	families = append(families, m.beliefFamilies()...)
+	families = append(families, m.targetQueryFamilies()...)
+
+	// Query latency
+	if m.QueryLatencyCount > 0 {
+		avgMs := float64(m.QueryLatencySum.Milliseconds()) / float64(m.QueryLatencyCount)
+		families = append(families, Family{"styx_query_latency_avg_ms", TypeGauge, "Average query latency in milliseconds", []Sample{Float(avgMs)}})
+	}
+	return families
 }
 
-func writeMetric(w http.ResponseWriter, name, mtype, help string, value int64) {
-	w.Write([]byte("# HELP " + name + " " + help + "\n"))
-	w.Write([]byte("# TYPE " + name + " " + mtype + "\n"))
-	w.Write([]byte(name + " " + strconv.FormatInt(value, 10) + "\n"))
+// Handler returns Prometheus-compatible metrics endpoint
+func (m *Metrics) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		WriteFamilies(w, m.Families())
+	}
 }
 
-func formatFloat(f float64) string {
-	return strconv.FormatFloat(f, 'f', 3, 64)
+// beliefFamilies returns per target flip counters and the last answered
+// belief, the only belief series: bounded like the query series
+// caller must hold m.mu
+func (m *Metrics) beliefFamilies() []Family {
+	labels := make([]string, 0, len(m.dominantFlips))
+	for l := range m.dominantFlips {
+		labels = append(labels, l)
+	}
+	slices.Sort(labels)
+
+	flips := Family{Name: "styx_belief_dominant_flips_total", Type: TypeCounter, Help: "Dominant state changes per target"}
+	alive := Family{Name: "styx_current_belief_alive", Type: TypeGauge, Help: "Last answered alive confidence per target"}
+	dead := Family{Name: "styx_current_belief_dead", Type: TypeGauge, Help: "Last answered dead confidence per target"}
+	unknown := Family{Name: "styx_current_belief_unknown", Type: TypeGauge, Help: "Last answered unknown mass per target"}
+	for _, l := range labels {
+		target := Label{"target", l}
+		flips.Samples = append(flips.Samples, Int(m.dominantFlips[l], target))
+		if b, ok := m.currentBeliefs[l]; ok {
+			alive.Samples = append(alive.Samples, Float(b.Alive().Value(), target))
+			dead.Samples = append(dead.Samples, Float(b.Dead().Value(), target))
+			unknown.Samples = append(unknown.Samples, Float(b.Unknown().Value(), target))
+		}
+	}
+	return []Family{flips, alive, dead, unknown}
 }
@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+func TestRecordBeliefChangeCountsOnlyDominantFlips(t *testing.T) {
+	m := &Metrics{}
+	target := types.NewNodeID(7)
+
+	alive := types.MustBelief(0.8, 0.1, 0.1)
+	stillAlive := types.MustBelief(0.75, 0.15, 0.1)
+	dead := types.MustBelief(0.1, 0.8, 0.1)
+
+	m.RecordBeliefChange(target, alive, stillAlive)
+	if n := m.DominantFlips(target); n != 0 {
+		t.Errorf("small change within ALIVE counted as flip: %d", n)
+	}
+	if m.ConfidenceChangesTotal != 1 {
+		t.Errorf("confidence changes = %d, want 1", m.ConfidenceChangesTotal)
+	}
+
+	m.RecordBeliefChange(target, stillAlive, dead)
+	m.RecordBeliefChange(target, dead, alive)
+	if n := m.DominantFlips(target); n != 2 {
+		t.Errorf("flips = %d, want 2", n)
+	}
+
+	m.RecordBeliefChange(target, alive, alive)
+	if m.ConfidenceChangesTotal != 3 {
+		t.Errorf("unchanged belief counted as change: %d", m.ConfidenceChangesTotal)
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler()(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`styx_belief_dominant_flips_total{target="` + target.String() + `"} 2`,
+		`styx_current_belief_alive{target="` + target.String() + `"} 0.800`,
+		`styx_current_belief_dead{target="` + target.String() + `"} 0.100`,
+		`styx_current_belief_unknown{target="` + target.String() + `"} 0.100`,
+		"styx_belief_confidence_changes_total 3",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q", want)
+		}
+	}
+}
+
+func TestWriteFamiliesEscapesLabels(t *testing.T) {
+	var b strings.Builder
+	WriteFamilies(&b, []Family{{
+		Name: "styx_test", Type: TypeGauge, Help: "line\nbreak",
+		Samples: []Sample{Float(1, Label{Name: "node", Value: `a"b\c`})},
+	}})
+	want := "# HELP styx_test line\\nbreak\n# TYPE styx_test gauge\nstyx_test{node=\"a\\\"b\\\\c\"} 1.000\n"
+	if b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+}
+
+func TestWithLabel(t *testing.T) {
+	families := []Family{{Name: "styx_test", Type: TypeGauge, Samples: []Sample{Int(1, Label{"target", "a"})}}}
+	labeled := WithLabel(families, Label{"namespace", "ns"})
+
+	var b strings.Builder
+	WriteFamilies(&b, labeled)
+	if want := `styx_test{namespace="ns",target="a"} 1`; !strings.Contains(b.String(), want) {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+	if len(families[0].Samples[0].Labels) != 1 {
+		t.Error("WithLabel modified its input")
+	}
+}
+
+func TestTargetQueriesBoundedCardinality(t *testing.T) {
+	m := (&Metrics{}).WithMaxQueryTargets(2)
+	for i := uint64(1); i <= 4; i++ {
+		m.RecordTargetQuery(types.NewNodeID(i), time.Millisecond, i == 1)
+	}
+
+	if s := m.TargetQueryStats(types.NewNodeID(1)); s.Queries != 1 || s.Refusals != 1 || s.P99 != time.Millisecond {
+		t.Errorf("target 1 stats = %+v", s)
+	}
+	if s := m.TargetQueryStats(types.NewNodeID(3)); s.Queries != 0 {
+		t.Errorf("target past the bound got its own series: %+v", s)
+	}
+
+	var b strings.Builder
+	WriteFamilies(&b, m.Families())
+	body := b.String()
+	for _, want := range []string{
+		`styx_query_refusals{target="` + types.NewNodeID(1).String() + `"} 1`,
+		`styx_target_queries_total{target="other"} 2`,
+		`styx_query_latency_seconds{target="other",quantile="0.5"} 0.001`,
+		`styx_query_latency_seconds_count{target="other"} 2`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q", want)
+		}
+	}
+	if strings.Contains(body, `target="`+types.NewNodeID(4).String()+`"`) {
+		t.Error("unbounded target label in output")
+	}
+}
+
+func TestBeliefSeriesBoundedCardinality(t *testing.T) {
+	m := (&Metrics{}).WithMaxQueryTargets(2)
+	alive, dead := types.MustBelief(0.8, 0.1, 0.1), types.MustBelief(0.1, 0.8, 0.1)
+	for i := uint64(1); i <= 4; i++ {
+		m.RecordBeliefChange(types.NewNodeID(i), alive, dead)
+	}
+
+	if n := m.DominantFlips(types.NewNodeID(2)); n != 1 {
+		t.Errorf("target 2 flips = %d, want 1", n)
+	}
+	var b strings.Builder
+	WriteFamilies(&b, m.Families())
+	body := b.String()
+	if !strings.Contains(body, `styx_belief_dominant_flips_total{target="other"} 2`) {
+		t.Errorf("targets past the bound not counted under other:\n%s", body)
+	}
+	for _, unwanted := range []string{
+		`target="` + types.NewNodeID(3).String() + `"`,
+		`styx_current_belief_alive{target="other"}`,
+	} {
+		if strings.Contains(body, unwanted) {
+			t.Errorf("metrics output has %q", unwanted)
+		}
+	}
+}
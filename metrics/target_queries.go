@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+// DefaultMaxQueryTargets bounds how many targets get their own query and
+// belief series
+const DefaultMaxQueryTargets = 256
+
+// QueryLatencyWindow is how many recent latencies per target feed the
+// latency quantiles
+const QueryLatencyWindow = 128
+
+// OtherTarget labels the queries of targets past the cardinality bound
+const OtherTarget = "other"
+
+// queryQuantiles are the quantiles of the per target latency summary
+var queryQuantiles = []float64{0.5, 0.9, 0.99}
+
+// targetQueries is the query record of one target label
+type targetQueries struct {
+	count      int64
+	refusals   int64
+	latencySum time.Duration
+	recent     []time.Duration // ring of the last QueryLatencyWindow latencies
+	next       int
+}
+
+// TargetQueryStats is the query record of one target
+type TargetQueryStats struct {
+	Queries     int64
+	Refusals    int64
+	RefusalRate float64
+	P50         time.Duration
+	P90         time.Duration
+	P99         time.Duration
+}
+
+// WithMaxQueryTargets bounds the per target query and belief series to n
+// targets, later targets share the OtherTarget series, n <= 0 uses the
+// default
+func (m *Metrics) WithMaxQueryTargets(n int) *Metrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maxQueryTargets = n
+	return m
+}
+
+// RecordTargetQuery records a query about target, in the totals and in
+// the per target series so expensive or often refused nodes stand out
+func (m *Metrics) RecordTargetQuery(target types.NodeID, latency time.Duration, refused bool) {
+	m.RecordQuery(latency, refused)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.targetQueries == nil {
+		m.targetQueries = make(map[string]*targetQueries)
+	}
+	label := seriesLabel(m.targetQueries, target, m.maxQueryTargets)
+	tq, ok := m.targetQueries[label]
+	if !ok {
+		tq = &targetQueries{}
+		m.targetQueries[label] = tq
+	}
+
+	tq.count++
+	if refused {
+		tq.refusals++
+	}
+	tq.latencySum += latency
+	if len(tq.recent) < QueryLatencyWindow {
+		tq.recent = append(tq.recent, latency)
+	} else {
+		tq.recent[tq.next] = latency
+		tq.next = (tq.next + 1) % QueryLatencyWindow
+	}
+}
+
+// seriesLabel returns the label target's samples go under in series:
+// its own until series holds limit labels, OtherTarget after that
+// limit <= 0 means DefaultMaxQueryTargets
+func seriesLabel[V any](series map[string]V, target types.NodeID, limit int) string {
+	label := target.String()
+	if _, ok := series[label]; ok {
+		return label
+	}
+	if limit <= 0 {
+		limit = DefaultMaxQueryTargets
+	}
+	if len(series) >= limit {
+		return OtherTarget
+	}
+	return label
+}
+
+// TargetQueryStats returns the query record of target, zero if it has no
+// series of its own
+func (m *Metrics) TargetQueryStats(target types.NodeID) TargetQueryStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tq, ok := m.targetQueries[target.String()]
+	if !ok {
+		return TargetQueryStats{}
+	}
+	q := tq.quantiles()
+	return TargetQueryStats{
+		Queries:     tq.count,
+		Refusals:    tq.refusals,
+		RefusalRate: float64(tq.refusals) / float64(tq.count),
+		P50:         q[0],
+		P90:         q[1],
+		P99:         q[2],
+	}
+}
+
+// quantiles returns the nearest rank queryQuantiles of the recent latencies
+func (tq *targetQueries) quantiles() []time.Duration {
+	sorted := slices.Clone(tq.recent)
+	slices.Sort(sorted)
+	out := make([]time.Duration, len(queryQuantiles))
+	if len(sorted) == 0 {
+		return out
+	}
+	for i, q := range queryQuantiles {
+		rank := int(q*float64(len(sorted))+0.5) - 1
+		out[i] = sorted[max(0, min(rank, len(sorted)-1))]
+	}
+	return out
+}
+
+// targetQueryFamilies returns the per target query series
+// caller must hold m.mu
+func (m *Metrics) targetQueryFamilies() []Family {
+	labels := make([]string, 0, len(m.targetQueries))
+	for l := range m.targetQueries {
+		labels = append(labels, l)
+	}
+	slices.Sort(labels)
+
+	queries := Family{Name: "styx_target_queries_total", Type: TypeCounter, Help: "Queries per target"}
+	refusals := Family{Name: "styx_query_refusals", Type: TypeCounter, Help: "Refused queries per target"}
+	latency := Family{Name: "styx_query_latency_seconds", Type: TypeSummary, Help: "Query latency per target over the recent window"}
+	for _, l := range labels {
+		tq, target := m.targetQueries[l], Label{"target", l}
+		queries.Samples = append(queries.Samples, Int(tq.count, target))
+		refusals.Samples = append(refusals.Samples, Int(tq.refusals, target))
+		for i, d := range tq.quantiles() {
+			latency.Samples = append(latency.Samples, Sample{
+				Labels: []Label{target, {"quantile", formatQuantile(queryQuantiles[i])}},
+				Value:  formatSeconds(d),
+			})
+		}
+		count := Int(tq.count, target)
+		count.Suffix = "_count"
+		latency.Samples = append(latency.Samples,
+			Sample{Suffix: "_sum", Labels: []Label{target}, Value: formatSeconds(tq.latencySum)},
+			count,
+		)
+	}
+	return []Family{queries, refusals, latency}
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'g', -1, 64)
+}
+
+func formatQuantile(q float64) string {
+	return strconv.FormatFloat(q, 'g', -1, 64)
+}
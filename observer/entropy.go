@@ -46,7 +46,11 @@ func (re *ResponseEntropy) AddSample(latency time.Duration) {
 func (re *ResponseEntropy) Entropy() float64 {
 	re.mu.RLock()
 	defer re.mu.RUnlock()
+	return re.entropy()
+}
 
+// entropy computes normalized entropy. Caller must hold re.mu.
+func (re *ResponseEntropy) entropy() float64 {
 	n := len(re.latencies)
 	if n < 2 {
 		return 0.5 // Insufficient data, neutral
@@ -121,7 +125,7 @@ func (re *ResponseEntropy) Stats() EntropyStats {
 		MeanLatency: sum / time.Duration(n),
 		MinLatency:  min,
 		MaxLatency:  max,
-		Entropy:     re.Entropy(),
+		Entropy:     re.entropy(),
 	}
 }
 
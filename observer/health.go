@@ -0,0 +1,70 @@
+package observer
+
+import (
+	"math"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+// ObserverHealth summarizes how reliable this observer currently is.
+//
+// An overloaded observer (high jitter) or one whose beliefs are mostly
+// undecided produces weaker evidence; operators and peers can use this to
+// discount it.
+type ObserverHealth struct {
+	JitterFactor float64 `json:"jitter_factor"`
+	IsJittery    bool    `json:"is_jittery"`
+
+	TrackedTargets int `json:"tracked_targets"`
+	AliveTargets   int `json:"alive_targets"`
+	DeadTargets    int `json:"dead_targets"`
+	UnknownTargets int `json:"unknown_targets"`
+
+	// AverageBeliefEntropy is the mean normalized Shannon entropy of the
+	// per-target beliefs: 0 when every belief is certain, 1 when every
+	// belief is evenly split between alive, dead and unknown.
+	AverageBeliefEntropy float64 `json:"average_belief_entropy"`
+}
+
+// HealthReport computes the observer health from the current jitter and
+// observer state. It is computed on every call, never cached.
+func (p *Prober) HealthReport() ObserverHealth {
+	factor := p.jitter.GetJitterFactor()
+	health := ObserverHealth{
+		JitterFactor: factor,
+		IsJittery:    factor < jitteryFactor,
+	}
+
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+
+	var entropySum float64
+	for _, target := range p.state.KnownNodes() {
+		belief := p.state.QueryOrUnknown(target).Belief
+		switch belief.Dominant() {
+		case types.StateAlive:
+			health.AliveTargets++
+		case types.StateDead:
+			health.DeadTargets++
+		default:
+			health.UnknownTargets++
+		}
+		entropySum += beliefEntropy(belief)
+		health.TrackedTargets++
+	}
+	if health.TrackedTargets > 0 {
+		health.AverageBeliefEntropy = entropySum / float64(health.TrackedTargets)
+	}
+	return health
+}
+
+// beliefEntropy returns the Shannon entropy of a belief normalized to [0,1].
+func beliefEntropy(b types.Belief) float64 {
+	var h float64
+	for _, p := range []float64{b.Alive().Value(), b.Dead().Value(), b.Unknown().Value()} {
+		if p > 0 {
+			h -= p * math.Log(p)
+		}
+	}
+	return h / math.Log(3)
+}
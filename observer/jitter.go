@@ -63,7 +63,11 @@ func (jt *JitterTracker) RecordSample(expected, actual time.Duration) {
 func (jt *JitterTracker) GetJitterFactor() float64 {
 	jt.mu.RLock()
 	defer jt.mu.RUnlock()
+	return jt.jitterFactor()
+}
 
+// jitterFactor computes the trust factor. Caller must hold jt.mu.
+func (jt *JitterTracker) jitterFactor() float64 {
 	if len(jt.samples) == 0 {
 		return 1.0 // No data, assume no jitter
 	}
@@ -94,9 +98,12 @@ func (jt *JitterTracker) GetJitterFactor() float64 {
 	return 1.0 - (mean * 2.5)
 }
 
+// jitteryFactor is the jitter factor below which the host counts as jittery.
+const jitteryFactor = 0.8
+
 // IsJittery returns true if significant jitter is detected.
 func (jt *JitterTracker) IsJittery() bool {
-	return jt.GetJitterFactor() < 0.8
+	return jt.GetJitterFactor() < jitteryFactor
 }
 
 // JitterStats returns current jitter statistics.
@@ -120,7 +127,7 @@ func (jt *JitterTracker) JitterStats() JitterStats {
 		SampleCount:  len(jt.samples),
 		MeanJitter:   sum / float64(len(jt.samples)),
 		MaxJitter:    max,
-		JitterFactor: jt.GetJitterFactor(),
+		JitterFactor: jt.jitterFactor(),
 	}
 }
 
@@ -1,6 +1,8 @@
 package observer
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -9,8 +11,12 @@ import (
 	"github.com/styx-oracle/styx/state"
 	styxtime "github.com/styx-oracle/styx/time"
 	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
 )
 
+// ErrProbeTimeout is reported when a probe function overruns the probe timeout.
+var ErrProbeTimeout = errors.New("probe timed out")
+
 // ProbeResult represents the outcome of a probe.
 type ProbeResult struct {
 	Target    types.NodeID
@@ -18,6 +24,12 @@ type ProbeResult struct {
 	Latency   time.Duration
 	Error     error
 	Timestamp styxtime.LogicalTimestamp
+
+	// JitterFactor is the local jitter discount in effect for this probe.
+	// Belief is the belief about Target after recording the probe.
+	// Both are filled in by the Prober, not by the ProbeFunc.
+	JitterFactor float64
+	Belief       types.Belief
 }
 
 // ProbeFunc is a function that probes a target node.
@@ -33,14 +45,23 @@ type ProbeFunc func(target types.NodeID) ProbeResult
 // - Recording evidence to the observer state
 type Prober struct {
 	mu           sync.Mutex
+	stateMu      sync.Mutex // serializes state, which is not safe for concurrent use
 	selfID       types.NodeID
 	state        *state.ObserverState
 	jitter       *JitterTracker
 	entropy      map[types.NodeID]*ResponseEntropy
 	probeFunc    ProbeFunc
 	probeTimeout time.Duration
+	clock        Clock
+	batchLimit   int
+	weights      evidence.WeightProfile
+	onProbe      []func(ProbeResult)
 }
 
+// DefaultBatchConcurrency is the default number of concurrent probes in
+// BatchProbe.
+const DefaultBatchConcurrency = 10
+
 // NewProber creates a new Prober.
 func NewProber(selfID types.NodeID, probeTimeout time.Duration) *Prober {
 	return &Prober{
@@ -49,9 +70,49 @@ func NewProber(selfID types.NodeID, probeTimeout time.Duration) *Prober {
 		jitter:       NewJitterTracker(100),
 		entropy:      make(map[types.NodeID]*ResponseEntropy),
 		probeTimeout: probeTimeout,
+		clock:        RealClock{},
+		batchLimit:   DefaultBatchConcurrency,
+		weights:      evidence.DefaultWeightProfile(),
 	}
 }
 
+// SetBatchConcurrency limits how many probes BatchProbe runs at once.
+// Values below 1 reset it to DefaultBatchConcurrency.
+func (p *Prober) SetBatchConcurrency(n int) {
+	if n < 1 {
+		n = DefaultBatchConcurrency
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.batchLimit = n
+}
+
+// SetClock replaces the clock used to measure latency and enforce the
+// probe timeout. Defaults to RealClock.
+func (p *Prober) SetClock(c Clock) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clock = c
+}
+
+// SetWeightProfile replaces the weights used for response and timeout
+// evidence. Defaults to evidence.DefaultWeightProfile.
+func (p *Prober) SetWeightProfile(w evidence.WeightProfile) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.weights = w
+}
+
+// OnProbe registers fn to be called after every probe with its result,
+// for external logging or tracing. Callbacks run synchronously on the
+// probing goroutine once all Prober locks are released, so a slow callback
+// delays only its own caller, never other probes.
+func (p *Prober) OnProbe(fn func(ProbeResult)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onProbe = append(p.onProbe, fn)
+}
+
 // SetProbeFunc sets the function used to probe targets.
 // Required before calling Probe().
 func (p *Prober) SetProbeFunc(fn ProbeFunc) {
@@ -61,6 +122,7 @@ func (p *Prober) SetProbeFunc(fn ProbeFunc) {
 }
 
 // State returns the observer state.
+// The state is not synchronized; do not use it while probes are running.
 func (p *Prober) State() *state.ObserverState {
 	return p.state
 }
@@ -73,8 +135,20 @@ func (p *Prober) JitterTracker() *JitterTracker {
 // Probe sends a probe to the target and records evidence.
 // Returns the updated belief about the target.
 func (p *Prober) Probe(target types.NodeID) (types.Belief, error) {
+	return p.probe(target, nil)
+}
+
+// errProbeAbandoned is returned by probe when keep declined recording.
+var errProbeAbandoned = errors.New("probe abandoned")
+
+// probe is Probe, recording the result only if keep, called under stateMu
+// once the probe returned, allows it. A nil keep always records.
+func (p *Prober) probe(target types.NodeID, keep func() bool) (types.Belief, error) {
 	p.mu.Lock()
 	probeFunc := p.probeFunc
+	clock := p.clock
+	weights := p.weights
+	callbacks := p.onProbe
 	p.mu.Unlock()
 
 	if probeFunc == nil {
@@ -85,9 +159,9 @@ func (p *Prober) Probe(target types.NodeID) (types.Belief, error) {
 	expectedDuration := p.probeTimeout / 2 // Expect response in half the timeout
 
 	// Perform the probe
-	start := time.Now()
-	result := probeFunc(target)
-	actualDuration := time.Since(start)
+	start := clock.Now()
+	result := p.runProbe(clock, probeFunc, target)
+	actualDuration := clock.Since(start)
 
 	// Record jitter sample (local scheduling delay)
 	p.jitter.RecordSample(expectedDuration, actualDuration)
@@ -95,6 +169,12 @@ func (p *Prober) Probe(target types.NodeID) (types.Belief, error) {
 	// Get jitter factor to discount timeout evidence
 	jitterFactor := p.jitter.GetJitterFactor()
 
+	p.stateMu.Lock()
+	if keep != nil && !keep() {
+		p.stateMu.Unlock()
+		return types.UnknownBelief(), errProbeAbandoned
+	}
+
 	// Advance logical clock
 	ts := p.state.Tick()
 
@@ -102,9 +182,9 @@ func (p *Prober) Probe(target types.NodeID) (types.Belief, error) {
 	var ev evidence.Evidence
 	if result.Success {
 		// Direct response - strong evidence of liveness
-		ev = evidence.NewDirectResponse(
+		ev = weights.NewDirectResponse(
 			ts,
-			uint64(result.Latency.Milliseconds()),
+			responseMS(result.Latency),
 			p.selfID,
 			target,
 		)
@@ -118,7 +198,8 @@ func (p *Prober) Probe(target types.NodeID) (types.Belief, error) {
 	} else {
 		// Timeout - weak evidence, further discounted by jitter
 		// Per Property 15: Silence ≠ death
-		ev = NewJitterAwareTimeout(
+		ev = jitterAwareTimeout(
+			weights,
 			ts,
 			uint64(p.probeTimeout.Milliseconds()),
 			uint64(actualDuration.Milliseconds()),
@@ -128,16 +209,279 @@ func (p *Prober) Probe(target types.NodeID) (types.Belief, error) {
 		)
 	}
 
+	// Invalid evidence would corrupt the belief, keep it out
+	if err := ev.Validate(); err != nil {
+		p.stateMu.Unlock()
+		return types.UnknownBelief(), err
+	}
+
 	// Record to observer state
 	belief := p.state.RecordEvidence(target, ev)
+	p.stateMu.Unlock()
+
+	result.Target = target
+	result.Timestamp = ts
+	result.JitterFactor = jitterFactor
+	result.Belief = belief
+	if !result.Success && result.Latency == 0 {
+		result.Latency = actualDuration
+	}
+	for _, fn := range callbacks {
+		fn(result)
+	}
 	return belief, nil
 }
 
+// BatchProbe probes many targets concurrently and returns the resulting
+// beliefs.
+//
+// At most SetBatchConcurrency probes run at once. BatchProbe returns when
+// every probe finished or ctx is done, whichever comes first. Probes still
+// in flight when ctx expires get timeout evidence for the time waited so far
+// (Property 15: the weak timeout weighting still applies), and record
+// nothing when they finish later, so each probe counts once. Targets that
+// never started are not probed and get no evidence, they map to the current
+// belief.
+//
+// Without a probe function nothing is probed and every target maps to the
+// unknown belief.
+func (p *Prober) BatchProbe(ctx context.Context, targets []types.NodeID) map[types.NodeID]types.Belief {
+	p.mu.Lock()
+	limit, clock, probeFunc := p.batchLimit, p.clock, p.probeFunc
+	p.mu.Unlock()
+
+	results := make(map[types.NodeID]types.Belief, len(targets))
+	if probeFunc == nil {
+		for _, target := range targets {
+			results[target] = types.UnknownBelief()
+		}
+		return results
+	}
+
+	var (
+		mu       sync.Mutex
+		closed   bool
+		inFlight = make(map[types.NodeID]bool, len(targets))
+		wg       sync.WaitGroup
+	)
+	start := clock.Now()
+	sem := make(chan struct{}, limit)
+
+	for _, target := range targets {
+		wg.Add(1)
+		go func(target types.NodeID) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			mu.Lock()
+			if closed || ctx.Err() != nil {
+				mu.Unlock()
+				return
+			}
+			inFlight[target] = true
+			mu.Unlock()
+
+			// the probe records only while the batch still waits for it
+			belief, err := p.probe(target, func() bool {
+				mu.Lock()
+				defer mu.Unlock()
+				delete(inFlight, target)
+				return !closed
+			})
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if !closed {
+				results[target] = belief
+			}
+		}(target)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	mu.Lock()
+	closed = true
+	waited := clock.Since(start)
+	abandoned := make(map[types.NodeID]bool, len(inFlight))
+	for target := range inFlight {
+		abandoned[target] = true
+	}
+	mu.Unlock()
+
+	for _, target := range targets {
+		if _, ok := results[target]; ok {
+			continue
+		}
+		if abandoned[target] {
+			results[target] = p.recordTimeout(target, waited)
+		} else {
+			results[target] = p.Query(target).Belief
+		}
+	}
+	return results
+}
+
+// recordTimeout records jitter-aware timeout evidence for a probe that was
+// abandoned after waited.
+func (p *Prober) recordTimeout(target types.NodeID, waited time.Duration) types.Belief {
+	p.mu.Lock()
+	weights := p.weights
+	p.mu.Unlock()
+
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+
+	ev := jitterAwareTimeout(
+		weights,
+		p.state.Tick(),
+		uint64(p.probeTimeout.Milliseconds()),
+		uint64(waited.Milliseconds()),
+		p.jitter.GetJitterFactor(),
+		p.selfID,
+		target,
+	)
+	if ev.Validate() != nil {
+		return p.state.QueryOrUnknown(target).Belief
+	}
+	return p.state.RecordEvidence(target, ev)
+}
+
+// RecordCausalEvent records that the application received a message caused
+// by target, identified by eventID, and advances the clock.
+//
+// Real traffic is stronger liveness evidence than a probe reply: the node
+// did real work, and the evidence is not discounted by response latency or
+// entropy. Events about the observer itself are ignored.
+//
+// Events arrive with application traffic, so the belief is not recomputed
+// per event but on the next read (ObserverState.AddEvidence).
+func (p *Prober) RecordCausalEvent(target types.NodeID, eventID evidence.EventID) {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+
+	ev := evidence.NewCausalEvent(p.state.Tick(), eventID, p.selfID, target)
+	if ev.Validate() != nil {
+		return
+	}
+	p.state.AddEvidence(target, ev)
+}
+
+// responseMS converts a response latency to whole milliseconds
+// a sub-millisecond response still took time, it rounds up to 1ms
+func responseMS(d time.Duration) uint64 {
+	if ms := uint64(d.Milliseconds()); ms > 0 {
+		return ms
+	}
+	return 1
+}
+
+// runProbe calls probeFunc, giving up after probeTimeout.
+//
+// An overrun is reported as a failed probe with ErrProbeTimeout. The probe
+// goroutine writes to a buffered channel, so it exits as soon as probeFunc
+// returns even if nobody is waiting for the result anymore.
+func (p *Prober) runProbe(clock Clock, probeFunc ProbeFunc, target types.NodeID) ProbeResult {
+	if p.probeTimeout <= 0 {
+		return probeFunc(target)
+	}
+
+	done := make(chan ProbeResult, 1)
+	go func() {
+		done <- probeFunc(target)
+	}()
+
+	timer := clock.NewTimer(p.probeTimeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-done:
+		return result
+	case <-timer.C():
+		return ProbeResult{
+			Target:  target,
+			Success: false,
+			Error:   ErrProbeTimeout,
+		}
+	}
+}
+
 // Query returns the current belief about a target.
 func (p *Prober) Query(target types.NodeID) state.BeliefQuery {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
 	return p.state.QueryOrUnknown(target)
 }
 
+// WitnessReport exports the current belief about target as a witness report
+// from this observer.
+//
+// Quality combines the target's response entropy confidence with the local
+// jitter factor, so aggregation can down-weight observations made over
+// erratic links or on an overloaded host. It never drops below
+// witness.MinQuality. The report carries the observer's logical time so the
+// receiving Oracle can order it causally.
+func (p *Prober) WitnessReport(target types.NodeID) witness.WitnessReport {
+	q := p.Query(target)
+
+	quality := p.jitter.GetJitterFactor()
+	p.mu.Lock()
+	if re := p.entropy[target]; re != nil {
+		quality *= re.ConfidenceFactor()
+	}
+	p.mu.Unlock()
+	if quality < witness.MinQuality {
+		quality = witness.MinQuality
+	}
+
+	return witness.WitnessReport{
+		Witness:   p.selfID,
+		Target:    target,
+		Belief:    q.Belief,
+		Quality:   quality,
+		Timestamp: q.QueryTime,
+	}
+}
+
+// ProberStats bundles local jitter and per-target response entropy.
+// It explains why timeout or response evidence is being discounted.
+type ProberStats struct {
+	Target  types.NodeID
+	Jitter  JitterStats
+	Entropy EntropyStats
+}
+
+// Stats returns current jitter statistics and the response entropy
+// statistics for a target. Entropy is zero-valued for unprobed targets.
+func (p *Prober) Stats(target types.NodeID) ProberStats {
+	stats := ProberStats{
+		Target: target,
+		Jitter: p.jitter.JitterStats(),
+	}
+
+	p.mu.Lock()
+	re := p.entropy[target]
+	p.mu.Unlock()
+
+	if re != nil {
+		stats.Entropy = re.Stats()
+	}
+	return stats
+}
+
 // getEntropy returns the entropy tracker for a target, creating if needed.
 func (p *Prober) getEntropy(target types.NodeID) *ResponseEntropy {
 	p.mu.Lock()
@@ -162,9 +506,20 @@ func NewJitterAwareTimeout(
 	expectedMS, waitedMS uint64,
 	jitterFactor float64,
 	source, target types.NodeID,
+) evidence.Evidence {
+	return jitterAwareTimeout(evidence.DefaultWeightProfile(), ts, expectedMS, waitedMS, jitterFactor, source, target)
+}
+
+// jitterAwareTimeout is NewJitterAwareTimeout with an explicit weight profile.
+func jitterAwareTimeout(
+	weights evidence.WeightProfile,
+	ts styxtime.LogicalTimestamp,
+	expectedMS, waitedMS uint64,
+	jitterFactor float64,
+	source, target types.NodeID,
 ) evidence.Evidence {
 	// Create base timeout evidence
-	ev := evidence.NewTimeout(ts, expectedMS, waitedMS, source, target)
+	ev := weights.NewTimeout(ts, expectedMS, waitedMS, source, target)
 
 	// Discount by jitter factor
 	// This implements Property 6: local load should not cause false death signals
@@ -172,9 +527,7 @@ func NewJitterAwareTimeout(
 
 	// Cap maximum weight for timeouts (Property 15: silence ≠ death)
 	// Even with no jitter, a single timeout is weak evidence
-	if ev.Weight > 0.3 {
-		ev.Weight = 0.3
-	}
+	ev.Weight = weights.CapTimeout(ev.Weight)
 
 	return ev
 }
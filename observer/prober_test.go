@@ -0,0 +1,460 @@
+package observer
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/styx-oracle/styx/evidence"
+	"github.com/styx-oracle/styx/types"
+)
+
+func TestProbeEnforcesTimeout(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	release := make(chan struct{})
+	p := NewProber(types.NewNodeID(1), 50*time.Millisecond)
+	p.SetProbeFunc(func(target types.NodeID) ProbeResult {
+		<-release // blocks well past the probe timeout
+		return ProbeResult{Target: target, Success: true, Latency: time.Millisecond}
+	})
+
+	target := types.NewNodeID(2)
+	start := time.Now()
+	if _, err := p.Probe(target); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("probe was not bounded by timeout: %v", elapsed)
+	}
+
+	reasoning := p.Query(target).Reasoning
+	if reasoning.DeadEvidenceCount != 1 || reasoning.AliveEvidenceCount != 0 {
+		t.Errorf("expected a single timeout evidence, got %s", reasoning)
+	}
+
+	// Once the stuck probe returns its goroutine must exit
+	close(release)
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			t.Fatalf("probe goroutine leaked: %d > %d", runtime.NumGoroutine(), baseline)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRunProbeReportsTimeoutError(t *testing.T) {
+	p := NewProber(types.NewNodeID(1), 10*time.Millisecond)
+	result := p.runProbe(RealClock{}, func(target types.NodeID) ProbeResult {
+		time.Sleep(100 * time.Millisecond)
+		return ProbeResult{Target: target, Success: true}
+	}, types.NewNodeID(2))
+
+	if result.Success || !errors.Is(result.Error, ErrProbeTimeout) {
+		t.Errorf("expected timeout result, got %+v", result)
+	}
+}
+
+func TestProberStatsReflectSamples(t *testing.T) {
+	p := NewProber(types.NewNodeID(1), time.Second)
+	latencies := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond}
+	i := 0
+	p.SetProbeFunc(func(target types.NodeID) ProbeResult {
+		lat := latencies[i%len(latencies)]
+		i++
+		return ProbeResult{Target: target, Success: true, Latency: lat}
+	})
+
+	target := types.NewNodeID(2)
+	for range latencies {
+		if _, err := p.Probe(target); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats := p.Stats(target)
+	if stats.Jitter.SampleCount != len(latencies) {
+		t.Errorf("expected %d jitter samples, got %d", len(latencies), stats.Jitter.SampleCount)
+	}
+	if stats.Entropy.SampleCount != len(latencies) {
+		t.Errorf("expected %d entropy samples, got %d", len(latencies), stats.Entropy.SampleCount)
+	}
+	if stats.Entropy.MinLatency != 10*time.Millisecond || stats.Entropy.MaxLatency != 30*time.Millisecond {
+		t.Errorf("unexpected latency range: %v-%v", stats.Entropy.MinLatency, stats.Entropy.MaxLatency)
+	}
+	if stats.Entropy.MeanLatency != 20*time.Millisecond {
+		t.Errorf("unexpected mean latency: %v", stats.Entropy.MeanLatency)
+	}
+
+	if unprobed := p.Stats(types.NewNodeID(3)); unprobed.Entropy.SampleCount != 0 {
+		t.Errorf("unprobed target should have no entropy samples")
+	}
+}
+
+// fakeLatencyProber returns a prober whose probes take exactly the given
+// latencies on a fake clock, without sleeping.
+func fakeLatencyProber(timeout time.Duration, latencies []time.Duration) (*Prober, *FakeClock) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	p := NewProber(types.NewNodeID(1), timeout)
+	p.SetClock(clock)
+
+	next := 0
+	p.SetProbeFunc(func(target types.NodeID) ProbeResult {
+		latency := latencies[next]
+		next++
+		clock.Advance(latency)
+		return ProbeResult{Target: target, Success: true, Latency: latency}
+	})
+	return p, clock
+}
+
+func TestProberFakeClockDrivesJitter(t *testing.T) {
+	// Expected duration is timeout/2 = 50ms, so 80ms is 60% jitter
+	p, _ := fakeLatencyProber(100*time.Millisecond, []time.Duration{
+		50 * time.Millisecond,
+		80 * time.Millisecond,
+	})
+
+	target := types.NewNodeID(2)
+	for i := 0; i < 2; i++ {
+		if _, err := p.Probe(target); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats := p.Stats(target)
+	if stats.Jitter.SampleCount != 2 {
+		t.Fatalf("expected 2 jitter samples, got %d", stats.Jitter.SampleCount)
+	}
+	if stats.Jitter.MaxJitter != 0.6 {
+		t.Errorf("max jitter = %f, want 0.6", stats.Jitter.MaxJitter)
+	}
+	if stats.Jitter.MeanJitter != 0.3 {
+		t.Errorf("mean jitter = %f, want 0.3", stats.Jitter.MeanJitter)
+	}
+}
+
+func TestProberFakeClockDrivesEntropy(t *testing.T) {
+	latencies := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond}
+	p, _ := fakeLatencyProber(time.Second, latencies)
+
+	target := types.NewNodeID(2)
+	for range latencies {
+		if _, err := p.Probe(target); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entropy := p.Stats(target).Entropy
+	if entropy.MinLatency != 10*time.Millisecond || entropy.MaxLatency != 30*time.Millisecond {
+		t.Errorf("latency range = [%v, %v], want [10ms, 30ms]", entropy.MinLatency, entropy.MaxLatency)
+	}
+	if entropy.MeanLatency != 20*time.Millisecond {
+		t.Errorf("mean latency = %v, want 20ms", entropy.MeanLatency)
+	}
+}
+
+func TestFakeClockTimerFiresOnAdvance(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	timer := clock.NewTimer(time.Second)
+
+	clock.Advance(999 * time.Millisecond)
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired early")
+	default:
+	}
+
+	clock.Advance(time.Millisecond)
+	select {
+	case <-timer.C():
+	default:
+		t.Fatal("timer did not fire at deadline")
+	}
+	if timer.Stop() {
+		t.Error("Stop reported an already fired timer as pending")
+	}
+}
+
+// httpProbe probes an HTTP endpoint, treating any response as success.
+func httpProbe(url string) ProbeFunc {
+	return func(target types.NodeID) ProbeResult {
+		start := time.Now()
+		resp, err := http.Get(url)
+		if err != nil {
+			return ProbeResult{Target: target, Error: err}
+		}
+		resp.Body.Close()
+		return ProbeResult{Target: target, Success: true, Latency: time.Since(start)}
+	}
+}
+
+func TestBatchProbeRunsConcurrently(t *testing.T) {
+	const maxLatency = 50 * time.Millisecond
+	var mu sync.Mutex
+	rng := rand.New(rand.NewSource(1))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		delay := time.Duration(rng.Int63n(int64(maxLatency)))
+		mu.Unlock()
+		time.Sleep(delay)
+	}))
+	defer srv.Close()
+
+	p := NewProber(types.NewNodeID(1), time.Second)
+	p.SetProbeFunc(httpProbe(srv.URL))
+
+	targets := make([]types.NodeID, 20)
+	for i := range targets {
+		targets[i] = types.NewNodeID(uint64(i + 2))
+	}
+
+	start := time.Now()
+	beliefs := p.BatchProbe(context.Background(), targets)
+	elapsed := time.Since(start)
+
+	if len(beliefs) != len(targets) {
+		t.Fatalf("got %d beliefs, want %d", len(beliefs), len(targets))
+	}
+	for _, target := range targets {
+		b, ok := beliefs[target]
+		if !ok || !b.IsValid() {
+			t.Errorf("missing or invalid belief for %s: %v", target, b)
+		}
+	}
+	if elapsed >= 20*maxLatency {
+		t.Errorf("batch took %v, not faster than sequential bound %v", elapsed, 20*maxLatency)
+	}
+}
+
+func TestBatchProbeTimesOutPendingTargets(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	p := NewProber(types.NewNodeID(1), time.Minute)
+	p.SetProbeFunc(func(target types.NodeID) ProbeResult {
+		<-release
+		return ProbeResult{Target: target, Success: true}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	targets := []types.NodeID{types.NewNodeID(2), types.NewNodeID(3)}
+	beliefs := p.BatchProbe(ctx, targets)
+
+	for _, target := range targets {
+		if _, ok := beliefs[target]; !ok {
+			t.Errorf("no belief for pending target %s", target)
+		}
+		if n := p.Query(target).Reasoning.DeadEvidenceCount; n != 1 {
+			t.Errorf("%s: expected one timeout evidence, got %d", target, n)
+		}
+	}
+}
+
+func TestBatchProbeCountsAbandonedProbesOnce(t *testing.T) {
+	release := make(chan struct{})
+	var returned sync.WaitGroup
+	p := NewProber(types.NewNodeID(1), time.Minute)
+	p.SetBatchConcurrency(1)
+	p.SetProbeFunc(func(target types.NodeID) ProbeResult {
+		defer returned.Done()
+		<-release
+		return ProbeResult{Target: target, Success: true}
+	})
+	returned.Add(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	targets := []types.NodeID{types.NewNodeID(2), types.NewNodeID(3), types.NewNodeID(4)}
+	beliefs := p.BatchProbe(ctx, targets)
+	if len(beliefs) != len(targets) {
+		t.Fatalf("got %d beliefs, want %d", len(beliefs), len(targets))
+	}
+
+	// the one probe in flight finishes after the batch gave up on it
+	close(release)
+	returned.Wait()
+	time.Sleep(20 * time.Millisecond) // let it reach recording
+
+	var timedOut, untouched int
+	for _, target := range targets {
+		switch r := p.Query(target).Reasoning; {
+		case r.EvidenceCount == 0:
+			untouched++
+		case r.EvidenceCount == 1 && r.DeadEvidenceCount == 1:
+			timedOut++
+		default:
+			t.Errorf("%s: %d evidence items, %d alive", target, r.EvidenceCount, r.AliveEvidenceCount)
+		}
+	}
+	if timedOut != 1 || untouched != 2 {
+		t.Errorf("%d targets timed out and %d untouched, want 1 and 2", timedOut, untouched)
+	}
+}
+
+func TestOnProbeReportsSuccessAndTimeout(t *testing.T) {
+	p := NewProber(types.NewNodeID(1), 20*time.Millisecond)
+
+	var results []ProbeResult
+	p.OnProbe(func(r ProbeResult) { results = append(results, r) })
+
+	target := types.NewNodeID(2)
+	p.SetProbeFunc(func(target types.NodeID) ProbeResult {
+		return ProbeResult{Success: true, Latency: 5 * time.Millisecond}
+	})
+	alive, err := p.Probe(target)
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	p.SetProbeFunc(func(target types.NodeID) ProbeResult {
+		<-release
+		return ProbeResult{Target: target, Success: true}
+	})
+	after, err := p.Probe(target)
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("got %d callbacks, want 2", len(results))
+	}
+	ok, timedOut := results[0], results[1]
+	if !ok.Success || ok.Target != target || ok.Latency != 5*time.Millisecond || !ok.Belief.Equal(alive) {
+		t.Errorf("success callback mismatch: %+v (belief %v)", ok, alive)
+	}
+	if timedOut.Success || !errors.Is(timedOut.Error, ErrProbeTimeout) || !timedOut.Belief.Equal(after) {
+		t.Errorf("timeout callback mismatch: %+v (belief %v)", timedOut, after)
+	}
+	if timedOut.Latency < 20*time.Millisecond {
+		t.Errorf("timeout latency %v shorter than probe timeout", timedOut.Latency)
+	}
+	if timedOut.Timestamp <= ok.Timestamp {
+		t.Errorf("timestamps not increasing: %d then %d", ok.Timestamp, timedOut.Timestamp)
+	}
+	for _, r := range results {
+		if r.JitterFactor < 0 || r.JitterFactor > 1 {
+			t.Errorf("jitter factor out of range: %f", r.JitterFactor)
+		}
+	}
+}
+
+func TestWitnessReportQualityTracksLatencyEntropy(t *testing.T) {
+	target := types.NewNodeID(2)
+	probeWith := func(latency func(i int) time.Duration) float64 {
+		p := NewProber(types.NewNodeID(1), time.Second)
+		i := 0
+		p.SetProbeFunc(func(target types.NodeID) ProbeResult {
+			i++
+			return ProbeResult{Target: target, Success: true, Latency: latency(i)}
+		})
+		for n := 0; n < 40; n++ {
+			if _, err := p.Probe(target); err != nil {
+				t.Fatalf("Probe: %v", err)
+			}
+		}
+		r := p.WitnessReport(target)
+		if r.Witness != types.NewNodeID(1) || r.Target != target || !r.Belief.IsValid() {
+			t.Fatalf("bad exported report %+v", r)
+		}
+		return r.Quality
+	}
+
+	rng := rand.New(rand.NewSource(3))
+	clean := probeWith(func(int) time.Duration { return 10 * time.Millisecond })
+	erratic := probeWith(func(int) time.Duration { return time.Duration(1+rng.Intn(500)) * time.Millisecond })
+
+	if erratic >= clean {
+		t.Errorf("erratic quality %.3f not below clean quality %.3f", erratic, clean)
+	}
+}
+
+func TestHealthReportCountsTargets(t *testing.T) {
+	self := types.NewNodeID(1)
+	p := NewProber(self, time.Second)
+	st := p.State()
+
+	// Two alive, two dead and one undecided target
+	for _, base := range []uint64{10, 11} {
+		target := types.NewNodeID(base)
+		for i := 0; i < 5; i++ {
+			st.RecordEvidence(target, evidence.NewDirectResponse(st.Tick(), 5, self, target))
+		}
+	}
+	for _, base := range []uint64{20, 21} {
+		target := types.NewNodeID(base)
+		for i := 0; i < 20; i++ {
+			st.RecordEvidence(target, evidence.NewTimeout(st.Tick(), 100, 2000, self, target))
+		}
+	}
+	undecided := types.NewNodeID(30)
+	st.RecordEvidence(undecided, evidence.NewTimeout(st.Tick(), 100, 150, self, undecided))
+
+	for i := 0; i < 10; i++ {
+		p.JitterTracker().RecordSample(100*time.Millisecond, 140*time.Millisecond)
+	}
+
+	h := p.HealthReport()
+	if h.TrackedTargets != 5 || h.AliveTargets != 2 || h.DeadTargets != 2 || h.UnknownTargets != 1 {
+		t.Errorf("counts: %+v", h)
+	}
+	if want := p.JitterTracker().GetJitterFactor(); h.JitterFactor != want {
+		t.Errorf("jitter factor %v, want %v", h.JitterFactor, want)
+	}
+	if !h.IsJittery {
+		t.Errorf("40%% jitter should count as jittery: %+v", h)
+	}
+	if h.AverageBeliefEntropy <= 0 || h.AverageBeliefEntropy >= 1 {
+		t.Errorf("average entropy %v outside (0,1)", h.AverageBeliefEntropy)
+	}
+}
+
+func TestRecordCausalEventOutweighsProbe(t *testing.T) {
+	p := NewProber(types.NewNodeID(1), time.Second)
+	probed, messaged := types.NewNodeID(2), types.NewNodeID(3)
+
+	p.SetProbeFunc(func(target types.NodeID) ProbeResult {
+		return ProbeResult{Target: target, Success: true, Latency: 200 * time.Millisecond}
+	})
+	before := p.State().LogicalTime()
+	probeBelief, err := p.Probe(probed)
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	p.RecordCausalEvent(messaged, 7)
+	if p.State().LogicalTime() != before+2 {
+		t.Errorf("clock %d, want %d", p.State().LogicalTime(), before+2)
+	}
+
+	causal := p.State().QueryOrUnknown(messaged).Belief
+	if !causal.MoreAliveThan(probeBelief) {
+		t.Errorf("causal event %v not more alive than probe reply %v", causal, probeBelief)
+	}
+
+	// the same silence afterwards leaves the messaged node ahead
+	for range 3 {
+		p.recordTimeout(probed, 3*time.Second)
+		p.recordTimeout(messaged, 3*time.Second)
+	}
+	probeBelief = p.State().QueryOrUnknown(probed).Belief
+	causal = p.State().QueryOrUnknown(messaged).Belief
+	if !causal.MoreAliveThan(probeBelief) || causal.Alive().Value() <= causal.Dead().Value() {
+		t.Errorf("after timeouts causal %v, probed %v", causal, probeBelief)
+	}
+
+	// events about the observer itself are not evidence
+	p.RecordCausalEvent(types.NewNodeID(1), 8)
+	if got := p.State().QueryOrUnknown(types.NewNodeID(1)).Belief; !got.Equal(types.UnknownBelief()) {
+		t.Errorf("self event recorded: %v", got)
+	}
+}
@@ -0,0 +1,42 @@
+package oracle
+
+import (
+	"sync"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+// answerLog remembers the last belief answered per target
+// own lock so queries can update it under the Oracle read lock
+type answerLog struct {
+	mu      sync.Mutex
+	beliefs map[types.NodeID]types.Belief
+}
+
+func newAnswerLog() *answerLog {
+	return &answerLog{beliefs: make(map[types.NodeID]types.Belief)}
+}
+
+// swap stores b and returns the previous answer
+// the first answer for a target is its own previous, so it is no change
+func (l *answerLog) swap(target types.NodeID, b types.Belief) types.Belief {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	old, ok := l.beliefs[target]
+	if !ok {
+		old = b
+	}
+	l.beliefs[target] = b
+	return old
+}
+
+// recordAnswer reports the belief change since the last answer to metrics
+// caller must hold o.mu (read is enough)
+func (o *Oracle) recordAnswer(target types.NodeID, b types.Belief) {
+	m := o.metrics.Load()
+	if m == nil {
+		return
+	}
+	m.RecordBeliefChange(target, o.answers.swap(target, b), b)
+}
@@ -0,0 +1,112 @@
+package oracle
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/styx-oracle/styx/evidence"
+	"github.com/styx-oracle/styx/types"
+)
+
+// contention shape: 8 writers and 8 readers over a few hot targets
+const (
+	contentionWriters = 8
+	contentionReaders = 8
+	contentionTargets = 16
+)
+
+// mutexWaitCycles sums the contention delay recorded by the mutex profile
+func mutexWaitCycles() int64 {
+	n, _ := runtime.MutexProfile(nil)
+	records := make([]runtime.BlockProfileRecord, n+16)
+	n, _ = runtime.MutexProfile(records)
+	var cycles int64
+	for _, r := range records[:n] {
+		cycles += r.Cycles
+	}
+	return cycles
+}
+
+// BenchmarkHighContention has readers (Query) and writers (ReceiveReport)
+// fight over o.mu, b.N operations split evenly between the goroutines
+// reports reads/s and writes/s separately and mutex-wait-cycles/op from
+// the mutex profile: if wait cycles climb with writers while reads/s
+// collapse, the single RWMutex is the bottleneck and per target locking
+// would pay off
+func BenchmarkHighContention(b *testing.B) {
+	o := New(types.NewNodeID(1)).WithMaxReportsPerTarget(64)
+	targets := make([]types.NodeID, contentionTargets)
+	for i := range targets {
+		targets[i] = types.NewNodeID(uint64(1000 + i))
+		reportAll(o, targets[i], 10, 20, types.MustBelief(0.8, 0.1, 0.1))
+	}
+	beliefs := []types.Belief{
+		types.MustBelief(0.8, 0.1, 0.1),
+		types.MustBelief(0.7, 0.2, 0.1),
+		types.MustBelief(0.85, 0.05, 0.1),
+	}
+
+	prev := runtime.SetMutexProfileFraction(1)
+	defer runtime.SetMutexProfileFraction(prev)
+
+	perWorker := max(1, b.N/(contentionWriters+contentionReaders))
+	var reads, writes atomic.Int64
+	var wg sync.WaitGroup
+	waitBefore := mutexWaitCycles()
+	b.ResetTimer()
+	start := time.Now()
+
+	for w := range contentionWriters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWorker {
+				target := targets[(w+i)%contentionTargets]
+				witness := types.NewNodeID(uint64(10 + (w*perWorker+i)%50))
+				o.ReceiveReport(witness, target, beliefs[i%len(beliefs)])
+				writes.Add(1)
+			}
+		}()
+	}
+	for r := range contentionReaders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWorker {
+				o.Query(targets[(r+i)%contentionTargets])
+				reads.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	elapsed := time.Since(start).Seconds()
+	b.StopTimer()
+	ops := reads.Load() + writes.Load()
+	b.ReportMetric(float64(ops)/elapsed, "ops/s")
+	b.ReportMetric(float64(reads.Load())/elapsed, "reads/s")
+	b.ReportMetric(float64(writes.Load())/elapsed, "writes/s")
+	b.ReportMetric(float64(mutexWaitCycles()-waitBefore)/float64(ops), "mutex-wait-cycles/op")
+}
+
+// BenchmarkQueryDerivedReports queries a target whose aggregation input has
+// to be built (evidence-derived reports), run with -benchmem to see the
+// pooled input keep allocations down
+func BenchmarkQueryDerivedReports(b *testing.B) {
+	o := New(types.NewNodeID(1))
+	target := types.NewNodeID(100)
+	reportAll(o, target, 10, 20, types.MustBelief(0.8, 0.1, 0.1))
+	for i := range 5 {
+		w := types.NewNodeID(uint64(50 + i))
+		o.ReceiveEvidence(w, target, evidence.NewDirectResponse(1, 20, w, target))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		o.Query(target)
+	}
+}
@@ -0,0 +1,130 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+// DefaultNamespace is used when no namespace is given
+const DefaultNamespace = "default"
+
+// DefaultMaxNamespaces bounds how many namespaces Open creates
+const DefaultMaxNamespaces = 64
+
+// ErrNamespaceLimit is returned by Open for a new namespace past the limit
+var ErrNamespaceLimit = errors.New("namespace limit reached")
+
+// Broker hosts isolated Oracles keyed by namespace
+// Each namespace has its own registry, reports and finality engine,
+// nothing leaks between them
+type Broker struct {
+	mu            sync.RWMutex
+	selfID        types.NodeID
+	oracles       map[string]*Oracle
+	maxNamespaces int
+}
+
+// NewBroker creates a broker whose Oracles share selfID
+func NewBroker(selfID types.NodeID) *Broker {
+	return &Broker{
+		selfID:        selfID,
+		oracles:       make(map[string]*Oracle),
+		maxNamespaces: DefaultMaxNamespaces,
+	}
+}
+
+// WithMaxNamespaces sets how many namespaces Open creates, the default
+// namespace included, 0 removes the limit
+func (b *Broker) WithMaxNamespaces(n int) *Broker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.maxNamespaces = max(n, 0)
+	return b
+}
+
+// Open is GetOrCreate for namespaces named by clients: a namespace past
+// the WithMaxNamespaces limit is not created, ErrNamespaceLimit
+// existing namespaces are never evicted, their state stays until restart
+func (b *Broker) Open(namespace string) (*Oracle, error) {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+
+	b.mu.RLock()
+	o, ok := b.oracles[namespace]
+	full := b.maxNamespaces > 0 && len(b.oracles) >= b.maxNamespaces
+	b.mu.RUnlock()
+	if ok {
+		return o, nil
+	}
+	if full {
+		return nil, fmt.Errorf("%w: %q", ErrNamespaceLimit, namespace)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if o, ok := b.oracles[namespace]; ok {
+		return o, nil
+	}
+	if b.maxNamespaces > 0 && len(b.oracles) >= b.maxNamespaces {
+		return nil, fmt.Errorf("%w: %q", ErrNamespaceLimit, namespace)
+	}
+	o = NewWithNamespace(b.selfID, namespace)
+	b.oracles[namespace] = o
+	return o, nil
+}
+
+// GetOrCreate returns the Oracle for a namespace, creating it on first use
+// empty namespace means DefaultNamespace
+// not limited, for namespaces the caller chose, see Open
+func (b *Broker) GetOrCreate(namespace string) *Oracle {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+
+	b.mu.RLock()
+	o, ok := b.oracles[namespace]
+	b.mu.RUnlock()
+	if ok {
+		return o
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if o, ok := b.oracles[namespace]; ok {
+		return o
+	}
+	o = NewWithNamespace(b.selfID, namespace)
+	b.oracles[namespace] = o
+	return o
+}
+
+// Get returns the Oracle for an existing namespace without creating one
+// empty namespace means DefaultNamespace
+func (b *Broker) Get(namespace string) (*Oracle, bool) {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	o, ok := b.oracles[namespace]
+	return o, ok
+}
+
+// List returns all namespaces in sorted order
+func (b *Broker) List() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	names := make([]string, 0, len(b.oracles))
+	for ns := range b.oracles {
+		names = append(names, ns)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,59 @@
+package oracle
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+func TestBrokerNamespacesAreIsolated(t *testing.T) {
+	b := NewBroker(types.NewNodeID(1))
+	target := types.NewNodeID(100)
+
+	a := b.GetOrCreate("A")
+	reportAll(a, target, 10, 12, types.MustBelief(0.8, 0.1, 0.1))
+
+	if got := b.GetOrCreate("A"); got != a {
+		t.Fatal("GetOrCreate returned a new Oracle for an existing namespace")
+	}
+	if n := b.GetOrCreate("A").Query(target).WitnessCount; n != 3 {
+		t.Errorf("namespace A witness count %d, want 3", n)
+	}
+
+	other := b.GetOrCreate("B").Query(target)
+	if other.WitnessCount != 0 || !other.Belief.Equal(types.UnknownBelief()) {
+		t.Errorf("namespace B saw reports from A: %+v", other)
+	}
+}
+
+func TestBrokerList(t *testing.T) {
+	b := NewBroker(types.NewNodeID(1))
+	b.GetOrCreate("staging")
+	b.GetOrCreate("production")
+	b.GetOrCreate("")
+
+	want := []string{DefaultNamespace, "production", "staging"}
+	if got := b.List(); !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %v, want %v", got, want)
+	}
+	if ns := b.GetOrCreate("staging").Namespace(); ns != "staging" {
+		t.Errorf("Namespace() = %q", ns)
+	}
+}
+
+func TestBrokerOpenIsLimited(t *testing.T) {
+	b := NewBroker(types.NewNodeID(1)).WithMaxNamespaces(2)
+	b.GetOrCreate("")
+
+	if _, err := b.Open("a"); err != nil {
+		t.Fatalf("Open under the limit: %v", err)
+	}
+	if _, err := b.Open("b"); !errors.Is(err, ErrNamespaceLimit) {
+		t.Errorf("Open past the limit: got %v, want ErrNamespaceLimit", err)
+	}
+	if o, err := b.Open("a"); err != nil || o != b.GetOrCreate("a") {
+		t.Errorf("Open of an existing namespace: %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package oracle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/styx-oracle/styx/finality"
+	"github.com/styx-oracle/styx/types"
+)
+
+// ErrNoConsensus is returned when too few peers accept a death proposal
+var ErrNoConsensus = errors.New("death proposal rejected by cluster majority")
+
+// DeathProposal is the POST /consensus/death body
+// generations are omitted when 0, so proposals about first generation
+// nodes read the same as before generations were sent
+type DeathProposal struct {
+	Target             uint64   `json:"target"`
+	Generation         uint64   `json:"generation,omitempty"`
+	Alive              float64  `json:"alive"`
+	Dead               float64  `json:"dead"`
+	Unknown            float64  `json:"unknown"`
+	Witnesses          []uint64 `json:"witnesses"`
+	WitnessGenerations []uint64 `json:"witness_generations,omitempty"` // parallel to Witnesses
+	Reason             string   `json:"reason"`
+}
+
+// TargetID returns the proposed node, in its generation
+func (p DeathProposal) TargetID() types.NodeID {
+	return types.WithGeneration(p.Target, p.Generation)
+}
+
+// WitnessIDs returns the witnesses behind the proposal, in their generations
+func (p DeathProposal) WitnessIDs() []types.NodeID {
+	ids := make([]types.NodeID, len(p.Witnesses))
+	for i, base := range p.Witnesses {
+		var gen uint64
+		if i < len(p.WitnessGenerations) {
+			gen = p.WitnessGenerations[i]
+		}
+		ids[i] = types.WithGeneration(base, gen)
+	}
+	return ids
+}
+
+// DeathVote is the POST /consensus/death response
+type DeathVote struct {
+	Accept bool `json:"accept"`
+}
+
+// NewDeathProposal encodes a record for the wire
+func NewDeathProposal(rec *finality.DeathRecord) DeathProposal {
+	witnesses := make([]uint64, len(rec.Witnesses))
+	generations := make([]uint64, len(rec.Witnesses))
+	reborn := false
+	for i, w := range rec.Witnesses {
+		witnesses[i], generations[i] = w.Base, w.Generation
+		reborn = reborn || w.Generation != 0
+	}
+	if !reborn {
+		generations = nil
+	}
+	return DeathProposal{
+		Target:             rec.NodeID.Base,
+		Generation:         rec.NodeID.Generation,
+		Alive:              rec.FinalBelief.Alive().Value(),
+		Dead:               rec.FinalBelief.Dead().Value(),
+		Unknown:            rec.FinalBelief.Unknown().Value(),
+		Witnesses:          witnesses,
+		WitnessGenerations: generations,
+		Reason:             rec.Reason,
+	}
+}
+
+// Cluster makes death declarations a majority decision across oracles
+// P13: one oracle with a skewed view cannot declare death alone
+type Cluster struct {
+	self   *Oracle
+	peers  []string // base URLs
+	client *http.Client
+}
+
+// NewCluster attaches a cluster to self
+// Every DeclareDeath on self then needs a majority of peers to accept:
+// at least len(peers)/2+1 Accept votes. self does not vote unless it is
+// also listed as a peer
+func NewCluster(self *Oracle, peers []string) *Cluster {
+	c := &Cluster{
+		self:   self,
+		peers:  append([]string(nil), peers...),
+		client: &http.Client{Timeout: DefaultGossipTimeout},
+	}
+	self.mu.Lock()
+	self.cluster = c
+	self.mu.Unlock()
+	return c
+}
+
+// Quorum returns the number of Accept votes required
+func (c *Cluster) Quorum() int {
+	return len(c.peers)/2 + 1
+}
+
+// Propose asks every peer to vote on a death record
+// Unreachable peers count as rejections
+func (c *Cluster) Propose(rec *finality.DeathRecord) (accepts int, err error) {
+	body, err := json.Marshal(NewDeathProposal(rec))
+	if err != nil {
+		return 0, err
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, peer := range c.peers {
+		wg.Add(1)
+		go func(peer string) {
+			defer wg.Done()
+			if c.vote(peer, body) {
+				mu.Lock()
+				accepts++
+				mu.Unlock()
+			}
+		}(peer)
+	}
+	wg.Wait()
+
+	if accepts < c.Quorum() {
+		return accepts, fmt.Errorf("%w: %d of %d accepted, need %d",
+			ErrNoConsensus, accepts, len(c.peers), c.Quorum())
+	}
+	return accepts, nil
+}
+
+func (c *Cluster) vote(peer string, body []byte) bool {
+	resp, err := c.client.Post(peer+"/consensus/death", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	var v DeathVote
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&v) != nil {
+		return false
+	}
+	return v.Accept
+}
+
+// DeclareDeath runs finality on the current reports for target
+// With a Cluster attached the declaration only happens after majority
+// agreement, otherwise the local finality checks alone decide
+// P15: hasNonTimeoutEvidence must be true, silence alone cannot kill
+func (o *Oracle) DeclareDeath(target types.NodeID, hasNonTimeoutEvidence bool) error {
+	o.mu.RLock()
+	reports := o.reportsFor(target)
+	agg := o.aggregator.Aggregate(reports)
+	cluster := o.cluster
+	o.mu.RUnlock()
+
+	rec, err := o.finality.Evaluate(target, agg.Belief, agg.Reports, hasNonTimeoutEvidence)
+	if err != nil {
+		return err
+	}
+	if cluster != nil {
+		// network round trip, no locks held
+		if _, err := cluster.Propose(rec); err != nil {
+			return err
+		}
+	}
+	return o.finality.Commit(rec)
+}
+
+// VoteDeath answers a peer death proposal from the local view
+// Accepts when the target is already dead here or local witnesses
+// independently reach finality dead confidence, both about the proposed
+// generation: an earlier generation's death is no vote on its successor
+func (o *Oracle) VoteDeath(p DeathProposal) bool {
+	target := p.TargetID()
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	if o.finality.IsDead(target) {
+		return true
+	}
+	result, settled := o.assess(target)
+	if settled {
+		return false
+	}
+	return result.Belief.Dead().Value() >= finality.MinDeadConfidence
+}
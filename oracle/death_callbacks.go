@@ -0,0 +1,54 @@
+package oracle
+
+import (
+	"sync"
+
+	"github.com/styx-oracle/styx/finality"
+	"github.com/styx-oracle/styx/types"
+)
+
+// DeathCallback is told about a target the first time a query finds it dead
+type DeathCallback func(nodeID types.NodeID, record finality.DeathRecord)
+
+// deathWatch remembers which deaths callbacks were already told about
+// own lock so queries can notify under the Oracle read lock
+type deathWatch struct {
+	mu        sync.Mutex
+	callbacks []DeathCallback
+	notified  map[types.NodeID]bool
+}
+
+func newDeathWatch() *deathWatch {
+	return &deathWatch{notified: make(map[types.NodeID]bool)}
+}
+
+// RegisterDeathCallback adds fn to the callbacks run when a query first
+// finds a target dead
+// callbacks run in their own goroutine so they never block the query
+// P14: death is final so each callback fires at most once per target
+func (o *Oracle) RegisterDeathCallback(fn DeathCallback) {
+	o.deaths.mu.Lock()
+	defer o.deaths.mu.Unlock()
+	o.deaths.callbacks = append(o.deaths.callbacks, fn)
+}
+
+// notifyDeath runs the death callbacks if target was not seen dead before
+// caller must hold o.mu (read is enough)
+func (o *Oracle) notifyDeath(target types.NodeID) {
+	o.deaths.mu.Lock()
+	if o.deaths.notified[target] {
+		o.deaths.mu.Unlock()
+		return
+	}
+	o.deaths.notified[target] = true
+	callbacks := append([]DeathCallback(nil), o.deaths.callbacks...)
+	o.deaths.mu.Unlock()
+
+	rec := o.finality.GetDeathRecord(target)
+	if rec == nil {
+		return
+	}
+	for _, fn := range callbacks {
+		go fn(target, *rec)
+	}
+}
@@ -0,0 +1,53 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/styx-oracle/styx/finality"
+	"github.com/styx-oracle/styx/types"
+)
+
+func TestDeathCallbacksFireOnceOnQuery(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	target := types.NewNodeID(100)
+	o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.0, 0.95, 0.05))
+	o.ReceiveReport(types.NewNodeID(11), target, types.MustBelief(0.04, 0.9, 0.06))
+	o.ReceiveReport(types.NewNodeID(12), target, types.MustBelief(0.0, 0.9, 0.1))
+
+	type death struct {
+		id  types.NodeID
+		rec finality.DeathRecord
+	}
+	first, second := make(chan death, 2), make(chan death, 2)
+	o.RegisterDeathCallback(func(id types.NodeID, rec finality.DeathRecord) { first <- death{id, rec} })
+	o.RegisterDeathCallback(func(id types.NodeID, rec finality.DeathRecord) { second <- death{id, rec} })
+
+	if err := o.DeclareDeath(target, true); err != nil {
+		t.Fatalf("DeclareDeath: %v", err)
+	}
+	o.Query(target)
+	o.Query(target)
+
+	want := o.finality.GetDeathRecord(target)
+	for i, ch := range []chan death{first, second} {
+		select {
+		case d := <-ch:
+			if d.id != target || d.rec.NodeID != target || len(d.rec.Witnesses) != len(want.Witnesses) {
+				t.Errorf("callback %d got %v %+v", i, d.id, d.rec)
+			}
+			if !d.rec.FinalBelief.Equal(want.FinalBelief) {
+				t.Errorf("callback %d belief %v, want %v", i, d.rec.FinalBelief, want.FinalBelief)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("callback %d did not fire", i)
+		}
+	}
+
+	// P14: death is final, later queries are not a new death
+	select {
+	case <-first:
+		t.Error("callback fired twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
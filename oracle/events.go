@@ -0,0 +1,135 @@
+package oracle
+
+import (
+	"sync"
+
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+)
+
+// DefaultEventBuffer is the per subscriber channel buffer
+const DefaultEventBuffer = 16
+
+// BeliefChangeEvent is published when the belief about a target changes
+type BeliefChangeEvent struct {
+	Target    types.NodeID
+	Old       types.Belief
+	New       types.Belief
+	Timestamp styxtime.LogicalTimestamp
+}
+
+// eventBus fans belief changes out to per target subscribers
+// slow subscribers miss events rather than blocking ingestion
+// last holds the belief published for targets with subscribers only, it is
+// seeded on subscribe and dropped with the final subscriber
+type eventBus struct {
+	mu   sync.Mutex
+	next int
+	subs map[types.NodeID]map[int]chan BeliefChangeEvent
+	last map[types.NodeID]types.Belief
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{
+		subs: make(map[types.NodeID]map[int]chan BeliefChangeEvent),
+		last: make(map[types.NodeID]types.Belief),
+	}
+}
+
+// subscribe registers a subscriber given the current belief about target,
+// a known belief is its first event
+func (b *eventBus) subscribe(target types.NodeID, buffer int, current types.Belief, ts styxtime.LogicalTimestamp) (<-chan BeliefChangeEvent, func()) {
+	if buffer < 1 {
+		buffer = DefaultEventBuffer
+	}
+	ch := make(chan BeliefChangeEvent, buffer)
+
+	b.mu.Lock()
+	id := b.next
+	b.next++
+	if b.subs[target] == nil {
+		b.subs[target] = make(map[int]chan BeliefChangeEvent)
+	}
+	b.subs[target][id] = ch
+	b.last[target] = current
+	if !current.Equal(types.UnknownBelief()) {
+		ch <- BeliefChangeEvent{Target: target, Old: types.UnknownBelief(), New: current, Timestamp: ts}
+	}
+	b.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs[target], id)
+			if len(b.subs[target]) == 0 {
+				delete(b.subs, target)
+				delete(b.last, target)
+			}
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
+
+func (b *eventBus) hasSubscribers(target types.NodeID) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs[target]) > 0
+}
+
+func (b *eventBus) subscriberCount(target types.NodeID) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs[target])
+}
+
+// publish emits an event if the belief differs from the last one published
+func (b *eventBus) publish(target types.NodeID, belief types.Belief, ts styxtime.LogicalTimestamp) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.subs[target]) == 0 {
+		return
+	}
+	old, seen := b.last[target]
+	if !seen {
+		old = types.UnknownBelief()
+	}
+	if seen && old.Equal(belief) {
+		return
+	}
+	b.last[target] = belief
+
+	ev := BeliefChangeEvent{
+		Target:    target,
+		Old:       old,
+		New:       belief,
+		Timestamp: ts,
+	}
+	for _, ch := range b.subs[target] {
+		select {
+		case ch <- ev:
+		default: // subscriber too slow, drop
+		}
+	}
+}
+
+// Subscribe streams belief changes for a target
+// a known current belief is sent first, with Old unknown, later events
+// compare against what the subscriber has already seen
+// The returned cancel func must be called to release the subscription,
+// it closes the channel
+func (o *Oracle) Subscribe(target types.NodeID, buffer int) (<-chan BeliefChangeEvent, func()) {
+	// holding o.mu keeps publishes out until the subscriber has the current belief
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	result, _ := o.assess(target)
+	return o.events.subscribe(target, buffer, result.Belief, o.clock)
+}
+
+// SubscriberCount returns active subscriptions for a target
+func (o *Oracle) SubscriberCount(target types.NodeID) int {
+	return o.events.subscriberCount(target)
+}
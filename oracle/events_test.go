@@ -0,0 +1,62 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+func nextEvent(t *testing.T, events <-chan BeliefChangeEvent) BeliefChangeEvent {
+	t.Helper()
+	select {
+	case ev := <-events:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return BeliefChangeEvent{}
+	}
+}
+
+func TestSubscribeStartsFromCurrentBelief(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	w, target := types.NewNodeID(10), types.NewNodeID(100)
+	alive, dead := types.MustBelief(0.9, 0.0, 0.1), types.MustBelief(0.0, 0.9, 0.1)
+
+	events, cancel := o.Subscribe(target, 0)
+	select {
+	case ev := <-events:
+		t.Fatalf("unknown target sent %v on subscribe", ev)
+	default:
+	}
+	o.ReceiveReport(w, target, alive)
+	if ev := nextEvent(t, events); ev.New.Dominant() != types.StateAlive {
+		t.Fatalf("first change %v, want alive", ev.New)
+	}
+	cancel()
+
+	o.events.mu.Lock()
+	kept := len(o.events.last)
+	o.events.mu.Unlock()
+	if kept != 0 {
+		t.Errorf("%d beliefs kept after the last subscriber left", kept)
+	}
+
+	// the belief turns dead while nobody listens
+	o.WithMaxReportsPerTarget(1)
+	o.ReceiveReport(w, target, dead)
+
+	events, cancel = o.Subscribe(target, 0)
+	defer cancel()
+	ev := nextEvent(t, events)
+	if ev.New.Dominant() != types.StateDead {
+		t.Fatalf("subscribe sent %v, want the current dead belief", ev.New)
+	}
+
+	// turning back alive is a change for this subscriber
+	o.ReceiveReport(w, target, alive)
+	ev = nextEvent(t, events)
+	if ev.Old.Dominant() != types.StateDead || ev.New.Dominant() != types.StateAlive {
+		t.Errorf("change back reported as %v -> %v, want dead -> alive", ev.Old, ev.New)
+	}
+}
@@ -0,0 +1,276 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/styx-oracle/styx/evidence"
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+// ReceiveEvidence records raw evidence from a remote witness
+// Each (witness, target) pair keeps its own EvidenceSet and the witness
+// belief is derived with ComputeBelief at query time, so remote witnesses
+// get the same decay and conflict handling as local observers
+// Evidence is restamped with the Oracle clock so decay runs on one timeline
+// Each set keeps the newest evidence, see WithMaxEvidencePerWitness
+// Decay uses the witness half-life from SetWitnessHalfLife if one is set
+// Network instability evidence also feeds partition detection
+// Invalid evidence is rejected before it touches any state, so is
+// evidence from an evicted witness (ErrWitnessEvicted)
+func (o *Oracle) ReceiveEvidence(witnessID, target types.NodeID, e evidence.Evidence) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	_, err := o.receiveEvidence(witnessID, target, e)
+	return err
+}
+
+// receiveEvidence is ReceiveEvidence returning the assigned timestamp
+// caller must hold o.mu
+func (o *Oracle) receiveEvidence(witnessID, target types.NodeID, e evidence.Evidence) (styxtime.LogicalTimestamp, error) {
+	if o.registry.IsEvicted(witnessID) {
+		return 0, fmt.Errorf("%w: %s", ErrWitnessEvicted, witnessID)
+	}
+	byWitness := o.evidence[target]
+	set := byWitness[witnessID]
+	if set == nil {
+		set = evidence.NewEvidenceSet()
+	}
+	e.Timestamp = o.clock + 1
+	if e.Origin == 0 {
+		e.Origin = e.Timestamp
+	}
+	if err := set.AddValidated(e); err != nil {
+		return 0, err
+	}
+	set.TrimToSize(o.maxEvidence)
+	o.clock.Increment()
+	o.registry.Admit(witnessID)
+
+	if byWitness == nil {
+		byWitness = make(map[types.NodeID]*evidence.EvidenceSet)
+		o.evidence[target] = byWitness
+	}
+	byWitness[witnessID] = set
+	o.partition.RecordNetworkInstability(witnessID, target, e)
+	o.trackPartition(target)
+	o.invalidate(target)
+
+	if o.events.hasSubscribers(target) {
+		result, _ := o.assess(target)
+		o.events.publish(target, result.Belief, e.Timestamp)
+	}
+	return e.Timestamp, nil
+}
+
+// ErrUnsupportedBasis is returned for a report basis that is not an observation
+var ErrUnsupportedBasis = errors.New("unsupported report basis")
+
+// ReceiveReportWithBasis records a belief report together with what the
+// witness based it on, so P15 can tell silence from observation
+// plain witness reports are observations the witness vouches for, the
+// belief is kept as reported
+// direct responses and causal events are proof of life: they are ingested
+// as that evidence weighted by the alive confidence, so they decay and
+// count as non-timeout evidence like locally gathered observations, a
+// direct response of unknown latency weighs like a slow one
+// a timeout basis means the belief is only silence: it is ingested as
+// timeout evidence weighted by the dead confidence (capped like any
+// timeout), so it decays like local timeouts and never makes a death
+// finality eligible on its own
+// reports from an evicted witness are rejected with ErrWitnessEvicted,
+// reports a keyed witness did not sign validly with the signature errors
+func (o *Oracle) ReceiveReportWithBasis(report witness.WitnessReport, basis evidence.EvidenceKind) (styxtime.LogicalTimestamp, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.registry.IsEvicted(report.Witness) {
+		return 0, fmt.Errorf("%w: %s", ErrWitnessEvicted, report.Witness)
+	}
+	if err := o.checkSignature(report); err != nil {
+		return 0, err
+	}
+	report.RelayPath = nil
+	return o.receiveBasis(report, basis)
+}
+
+// receiveBasis stores a report by its basis, as a report or as the
+// evidence it stands for, and gossips it on with the basis
+// caller must hold o.mu for writing
+func (o *Oracle) receiveBasis(report witness.WitnessReport, basis evidence.EvidenceKind) (styxtime.LogicalTimestamp, error) {
+	report = withOrigin(report)
+	if basis == evidence.KindWitnessReport {
+		return o.record(report), nil
+	}
+	e, err := basisEvidence(report, basis)
+	if err != nil {
+		return 0, err
+	}
+	ts, err := o.receiveEvidence(report.Witness, report.Target, e)
+	if err == nil && o.gossip != nil {
+		report = stampOrigin(report, ts)
+		o.gossip.forward(o.selfID, o.namespace, report, basis)
+	}
+	return ts, err
+}
+
+// basisEvidence returns the evidence a report with an observation basis
+// stands for, stamped by receiveEvidence
+func basisEvidence(report witness.WitnessReport, basis evidence.EvidenceKind) (evidence.Evidence, error) {
+	profile := evidence.DefaultWeightProfile()
+	e := evidence.Evidence{Kind: basis, Source: report.Witness, Target: report.Target, Origin: report.Origin}
+	switch basis {
+	case evidence.KindDirectResponse:
+		e.Details.LatencyMS = profile.SlowResponseMS
+		e.Weight = profile.ResponseWeight(profile.SlowResponseMS) * report.Belief.Alive().Value()
+	case evidence.KindCausalEvent:
+		e.Weight = report.Belief.Alive().Value()
+	case evidence.KindTimeout:
+		e.Weight = profile.CapTimeout(report.Belief.Dead().Value())
+	default:
+		return evidence.Evidence{}, fmt.Errorf("%w: %s", ErrUnsupportedBasis, basis)
+	}
+	return e, nil
+}
+
+// knownRelayedEvidence reports whether witness already has the observation
+// behind e about its target, a copy reached over another relay path
+// observations are told apart by Origin, evidence without one (older peers)
+// falls back to comparing kind and weight
+// caller must hold o.mu
+func (o *Oracle) knownRelayedEvidence(witnessID types.NodeID, e evidence.Evidence) bool {
+	set := o.evidence[e.Target][witnessID]
+	if set == nil {
+		return false
+	}
+	for _, known := range set.All() {
+		if known.Kind != e.Kind {
+			continue
+		}
+		if e.Origin == 0 {
+			if known.Weight == e.Weight {
+				return true
+			}
+		} else if known.Origin == e.Origin {
+			return true
+		}
+	}
+	return false
+}
+
+// SetWitnessHalfLife sets how fast evidence from a witness decays
+// applies to evidence already received, 0 restores the default
+func (o *Oracle) SetWitnessHalfLife(witnessID types.NodeID, halfLife uint64) {
+	o.registry.SetHalfLife(witnessID, halfLife)
+}
+
+// SilenceDeadCap is the highest dead confidence a query reports when the
+// evidence is mostly timeouts, see WithMinNonTimeoutEvidence
+const SilenceDeadCap = 0.5
+
+// silenceDerived reports whether the live evidence about target is mostly
+// timeouts, with less than the configured non-timeout fraction
+// belief reports carry no evidence kind, the witness vouches for them, so
+// each counts as one non-timeout item
+// caller must hold o.mu
+func (o *Oracle) silenceDerived(target types.NodeID) bool {
+	if o.minNonTimeout <= 0 {
+		return false
+	}
+
+	evictions := o.registry.HasEvictions()
+	var timeouts, total int
+	for id, set := range o.evidence[target] {
+		if o.expired(set.LatestTimestamp()) || (evictions && o.registry.IsEvicted(id)) {
+			continue
+		}
+		for _, e := range set.All() {
+			if e.Kind == evidence.KindTimeout {
+				timeouts++
+			}
+			total++
+		}
+	}
+	if timeouts == 0 {
+		return false
+	}
+	for _, r := range o.reports.load(target) {
+		if !o.expired(r.Timestamp) && !(evictions && o.registry.IsEvicted(r.Witness)) {
+			total++
+		}
+	}
+	return float64(total-timeouts)/float64(total) < o.minNonTimeout
+}
+
+// capDead lowers dead confidence to limit, the excess becomes unknown
+func capDead(b types.Belief, limit float64) types.Belief {
+	dead := b.Dead().Value()
+	if dead <= limit {
+		return b
+	}
+	capped, err := types.NewBelief(b.Alive().Value(), limit, b.Unknown().Value()+dead-limit)
+	if err != nil {
+		return b
+	}
+	return capped
+}
+
+// reportsFor returns live belief reports plus reports derived from evidence
+// derived beliefs are computed at the current clock so they decay
+// reports past the TTL and reports from evicted witnesses are skipped
+// caller must hold o.mu
+func (o *Oracle) reportsFor(target types.NodeID) []witness.WitnessReport {
+	reports, _ := o.reportsInto(target, nil)
+	return reports
+}
+
+// reportsInto is reportsFor building into buf when filtering or derived
+// reports are needed, built reports whether buf was used, otherwise the
+// stored snapshot is returned as is
+// caller must hold o.mu
+func (o *Oracle) reportsInto(target types.NodeID, buf []witness.WitnessReport) (reports []witness.WitnessReport, built bool) {
+	reports = o.reports.load(target)
+	byWitness := o.evidence[target]
+	evictions := o.registry.HasEvictions()
+	if len(byWitness) == 0 && !o.anyExpired(reports) && !evictions {
+		return reports, false
+	}
+
+	all := buf[:0]
+	if all == nil {
+		all = make([]witness.WitnessReport, 0, len(reports)+len(byWitness))
+	}
+	for _, r := range reports {
+		if o.expired(r.Timestamp) || (evictions && o.registry.IsEvicted(r.Witness)) {
+			continue
+		}
+		all = append(all, r)
+	}
+	for id, set := range byWitness {
+		latest := set.LatestTimestamp()
+		if o.expired(latest) || (evictions && o.registry.IsEvicted(id)) {
+			continue
+		}
+		all = append(all, witness.WitnessReport{
+			Witness:   id,
+			Target:    target,
+			Belief:    set.ComputeBeliefWithHalfLife(o.clock, o.registry.HalfLife(id)),
+			Timestamp: latest,
+		})
+	}
+	return all, true
+}
+
+// expired reports whether something stamped at ts is past the report TTL
+func (o *Oracle) expired(ts styxtime.LogicalTimestamp) bool {
+	return o.reportTTL > 0 && ts.AgeSince(o.clock) > o.reportTTL
+}
+
+// anyExpired reports whether any report is past the TTL
+// reports are appended in clock order so the oldest is first
+func (o *Oracle) anyExpired(reports []witness.WitnessReport) bool {
+	return len(reports) > 0 && o.expired(reports[0].Timestamp)
+}
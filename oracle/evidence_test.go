@@ -0,0 +1,331 @@
+package oracle
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/styx-oracle/styx/evidence"
+	"github.com/styx-oracle/styx/finality"
+	"github.com/styx-oracle/styx/metrics"
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+func TestReceiveEvidenceDecaysOverLogicalTime(t *testing.T) {
+	h := NewHarness(types.NewNodeID(1), 1)
+	o := h.Oracle()
+	w, target := types.NewNodeID(10), types.NewNodeID(100)
+
+	for i := 0; i < 5; i++ {
+		o.ReceiveEvidence(w, target, evidence.NewTimeout(0, 100, 300, w, target))
+	}
+
+	fresh := h.Query(target)
+	if fresh.WitnessCount != 1 {
+		t.Fatalf("evidence witness count %d, want 1", fresh.WitnessCount)
+	}
+	if fresh.Belief.Dead().Value() <= fresh.Belief.Alive().Value() {
+		t.Fatalf("timeouts should lean dead, got %v", fresh.Belief)
+	}
+
+	h.AdvanceClock(10 * evidence.DefaultHalfLife)
+	stale := h.Query(target)
+
+	if stale.Belief.Dead().Value() >= fresh.Belief.Dead().Value() {
+		t.Errorf("dead confidence did not decay: fresh %v, stale %v", fresh.Belief, stale.Belief)
+	}
+	if stale.Belief.Unknown().Value() <= fresh.Belief.Unknown().Value() {
+		t.Errorf("unknown did not grow as evidence aged: fresh %v, stale %v", fresh.Belief, stale.Belief)
+	}
+}
+
+func TestWitnessHalfLifeShapesDecay(t *testing.T) {
+	h := NewHarness(types.NewNodeID(1), 1)
+	o := h.Oracle()
+	chatty, beacon := types.NewNodeID(10), types.NewNodeID(11)
+	t1, t2 := types.NewNodeID(100), types.NewNodeID(101)
+
+	o.SetWitnessHalfLife(chatty, 10)
+	o.SetWitnessHalfLife(beacon, 1000)
+	for i := 0; i < 3; i++ {
+		o.ReceiveEvidence(chatty, t1, evidence.NewDirectResponse(0, 5, chatty, t1))
+		o.ReceiveEvidence(beacon, t2, evidence.NewDirectResponse(0, 5, beacon, t2))
+	}
+
+	h.AdvanceClock(50)
+	fast, slow := h.Query(t1).Belief, h.Query(t2).Belief
+
+	if fast.Alive().Value() >= slow.Alive().Value() {
+		t.Errorf("chatty witness evidence should decay faster: chatty %v, beacon %v", fast, slow)
+	}
+
+	o.SetWitnessHalfLife(chatty, 1000)
+	if got := h.Query(t1).Belief; got.Alive().Value() <= fast.Alive().Value() {
+		t.Errorf("longer half-life should apply to stored evidence: was %v, now %v", fast, got)
+	}
+}
+
+func TestReceiveEvidenceCombinesWithReports(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	target := types.NewNodeID(100)
+
+	o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.8, 0.1, 0.1))
+	o.ReceiveEvidence(types.NewNodeID(11), target,
+		evidence.NewDirectResponse(0, 5, types.NewNodeID(11), target))
+
+	if n := o.Query(target).WitnessCount; n != 2 {
+		t.Errorf("witness count %d, want report and evidence witness", n)
+	}
+}
+
+func TestApplicationCheckpointPreventsFinality(t *testing.T) {
+	target := types.NewNodeID(100)
+	build := func(checkpoint bool) *Oracle {
+		o := New(types.NewNodeID(1))
+		o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.0, 0.95, 0.05))
+		o.ReceiveReport(types.NewNodeID(11), target, types.MustBelief(0.04, 0.9, 0.06))
+		o.ReceiveReport(types.NewNodeID(12), target, types.MustBelief(0.0, 0.9, 0.1))
+		if checkpoint {
+			w := types.NewNodeID(13)
+			o.ReceiveEvidence(w, target, evidence.NewApplicationCheckpoint(0, 7, w, target))
+		}
+		return o
+	}
+
+	// the same reports without the checkpoint are enough for death
+	without := build(false)
+	aliveWithout := without.Query(target).Belief.Alive().Value()
+	if err := without.DeclareDeath(target, true); err != nil {
+		t.Fatalf("no checkpoint: %v, the test proves nothing", err)
+	}
+
+	o := build(true)
+	if a := o.Query(target).Belief.Alive().Value(); a <= aliveWithout {
+		t.Errorf("checkpoint added no alive mass: %v, without %v", a, aliveWithout)
+	}
+
+	// P15: one checkpoint holds death off, even when told non-timeout
+	// evidence exists
+	if err := o.DeclareDeath(target, true); !errors.Is(err, finality.ErrInsufficientEvidence) {
+		t.Errorf("expected ErrInsufficientEvidence, got %v", err)
+	}
+	if o.Query(target).Dead {
+		t.Error("P15 VIOLATED: death declared over an application checkpoint")
+	}
+}
+
+func TestReceiveEvidenceRejectsInvalid(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	w, target := types.NewNodeID(10), types.NewNodeID(100)
+
+	bad := evidence.NewTimeout(0, 100, 300, w, target)
+	bad.Weight = 1.5
+	if err := o.ReceiveEvidence(w, target, bad); !errors.Is(err, evidence.ErrInvalidEvidence) {
+		t.Fatalf("expected ErrInvalidEvidence, got %v", err)
+	}
+	if r := o.Query(target); r.WitnessCount != 0 {
+		t.Errorf("invalid evidence reached the query: %d witnesses", r.WitnessCount)
+	}
+}
+
+func TestSilenceDerivedDeathIsCapped(t *testing.T) {
+	storm := func(o *Oracle, target types.NodeID) {
+		for i := 0; i < 3; i++ {
+			w := types.NewNodeID(uint64(10 + i))
+			for j := 0; j < 15+5*i; j++ {
+				o.ReceiveEvidence(w, target, evidence.NewTimeout(0, 100, 2000, w, target))
+			}
+		}
+	}
+	target := types.NewNodeID(100)
+
+	o := New(types.NewNodeID(1))
+	storm(o, target)
+	result := o.Query(target)
+	if d := result.Belief.Dead().Value(); d > SilenceDeadCap {
+		t.Errorf("timeout storm reported dead %v above cap %v", d, SilenceDeadCap)
+	}
+	if !containsNote(result.Evidence, "death confidence is silence-derived") {
+		t.Errorf("missing silence note: %v", result.Evidence)
+	}
+
+	uncapped := New(types.NewNodeID(1)).WithMinNonTimeoutEvidence(0)
+	storm(uncapped, target)
+	if d := uncapped.Query(target).Belief.Dead().Value(); d <= SilenceDeadCap {
+		t.Fatalf("storm should exceed the cap when disabled, got dead %v", d)
+	}
+
+	genuine := New(types.NewNodeID(1))
+	genuine.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0, 0.95, 0.05))
+	genuine.ReceiveReport(types.NewNodeID(11), target, types.MustBelief(0.04, 0.9, 0.06))
+	genuine.ReceiveReport(types.NewNodeID(12), target, types.MustBelief(0, 0.9, 0.1))
+	result = genuine.Query(target)
+	if d := result.Belief.Dead().Value(); d <= SilenceDeadCap {
+		t.Errorf("genuine dead reports were capped: dead %v", d)
+	}
+	if containsNote(result.Evidence, "death confidence is silence-derived") {
+		t.Errorf("genuine evidence marked silence-derived: %v", result.Evidence)
+	}
+}
+
+func containsNote(notes []string, want string) bool {
+	for _, n := range notes {
+		if n == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestExplainAttributesEvidenceBySource(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	a, b, c := types.NewNodeID(10), types.NewNodeID(11), types.NewNodeID(12)
+	target := types.NewNodeID(100)
+
+	o.ReceiveEvidence(a, target, evidence.NewDirectResponse(0, 5, a, target))
+	o.ReceiveEvidence(a, target, evidence.NewDirectResponse(0, 7, a, target))
+	o.ReceiveEvidence(b, target, evidence.NewTimeout(0, 100, 2000, b, target))
+	// a forwards what c saw, it is still c's evidence
+	o.ReceiveEvidence(a, target, evidence.NewCausalEvent(0, 3, c, target))
+	o.ReceiveEvidence(c, target, evidence.NewDirectResponse(0, 6, c, target))
+	o.ReceiveReport(types.NewNodeID(13), target, types.MustBelief(0.8, 0.1, 0.1))
+
+	exp := o.Explain(target)
+	if exp.Result.WitnessCount != 4 {
+		t.Errorf("explained answer has %d witnesses, want 4", exp.Result.WitnessCount)
+	}
+	if len(exp.Sources) != 3 {
+		t.Fatalf("got %d sources, want 3: %+v", len(exp.Sources), exp.Sources)
+	}
+
+	counts := map[types.NodeID]int{a: 2, b: 1, c: 2}
+	for i, s := range exp.Sources {
+		if len(s.Evidence) != counts[s.Source] {
+			t.Errorf("source %v has %d records, want %d", s.Source, len(s.Evidence), counts[s.Source])
+		}
+		for _, e := range s.Evidence {
+			if e.Source != s.Source {
+				t.Errorf("source %v lists evidence from %v", s.Source, e.Source)
+			}
+		}
+		if s.Weight <= 0 {
+			t.Errorf("source %v has weight %v", s.Source, s.Weight)
+		}
+		if i > 0 && s.Weight > exp.Sources[i-1].Weight {
+			t.Errorf("sources not heaviest first: %v", exp.Sources)
+		}
+	}
+
+	if exp := o.Explain(types.NewNodeID(200)); len(exp.Sources) != 0 || !exp.Result.Belief.Equal(types.UnknownBelief()) {
+		t.Errorf("target without evidence explained as %+v", exp)
+	}
+}
+
+func TestRemoveWitnessPurgesReportsAndEvidence(t *testing.T) {
+	o := New(types.NewNodeID(1)).WithQueryCache(time.Minute)
+	gone, stays := types.NewNodeID(10), types.NewNodeID(11)
+	target, other := types.NewNodeID(100), types.NewNodeID(200)
+
+	o.ReceiveReport(gone, target, types.MustBelief(0.05, 0.9, 0.05))
+	o.ReceiveReport(stays, target, types.MustBelief(0.8, 0.1, 0.1))
+	o.ReceiveEvidence(gone, other, evidence.NewTimeout(0, 100, 2000, gone, other))
+	if n := o.Query(target).WitnessCount; n != 2 {
+		t.Fatalf("before removal: %d witnesses, want 2", n)
+	}
+
+	if err := o.RemoveWitness(gone); err != nil {
+		t.Fatalf("RemoveWitness: %v", err)
+	}
+	if got := o.Query(target); got.WitnessCount != 1 || got.Belief.Dominant() != types.StateAlive {
+		t.Errorf("after removal answered %+v, want only the alive witness", got)
+	}
+	if n := o.Query(other).WitnessCount; n != 0 {
+		t.Errorf("removed witness evidence still counted: %d witnesses", n)
+	}
+	for _, r := range o.reports.load(target) {
+		if r.Witness == gone {
+			t.Errorf("report from removed witness kept: %+v", r)
+		}
+	}
+
+	if err := o.RemoveWitness(gone); !errors.Is(err, ErrWitnessNotFound) {
+		t.Errorf("second removal: got %v, want ErrWitnessNotFound", err)
+	}
+}
+
+func TestRemoveWitnessPurgesEvictedWitness(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	gone := types.NewNodeID(10)
+	target, other := types.NewNodeID(100), types.NewNodeID(200)
+
+	o.ReceiveReport(gone, target, types.MustBelief(0.05, 0.9, 0.05))
+	o.ReceiveEvidence(gone, other, evidence.NewTimeout(0, 100, 2000, gone, other))
+	o.EvictWitness(gone)
+
+	if err := o.RemoveWitness(gone); err != nil {
+		t.Fatalf("removing an evicted witness: %v", err)
+	}
+
+	// nothing it sent before may come back with it
+	o.RegisterWitness(gone)
+	if n := o.Query(target).WitnessCount; n != 0 {
+		t.Errorf("purged reports revived on register: %d witnesses", n)
+	}
+	if n := o.Query(other).WitnessCount; n != 0 {
+		t.Errorf("purged evidence revived on register: %d witnesses", n)
+	}
+}
+
+func TestExplainRecordsNothingAndWeighsLikeTheAnswer(t *testing.T) {
+	m := &metrics.Metrics{}
+	h := NewHarnessFor(New(types.NewNodeID(1)).WithMetrics(m).WithQueryCache(time.Minute), 1)
+	o := h.Oracle()
+	a, target := types.NewNodeID(10), types.NewNodeID(100)
+	o.SetWitnessHalfLife(a, 10)
+	o.ReceiveEvidence(a, target, evidence.NewDirectResponse(0, 5, a, target))
+	h.AdvanceClock(20)
+
+	exp := o.Explain(target)
+	if m.QueryCacheHits+m.QueryCacheMisses != 0 {
+		t.Errorf("Explain went through the query cache: %d hits, %d misses", m.QueryCacheHits, m.QueryCacheMisses)
+	}
+	if len(o.Transitions(target)) != 0 {
+		t.Error("Explain recorded a transition")
+	}
+
+	o.mu.RLock()
+	want := o.evidence[target][a].SumWeightBySource(a, o.clock, 10)
+	o.mu.RUnlock()
+	if len(exp.Sources) != 1 || math.Abs(exp.Sources[0].Weight-want) > 1e-9 {
+		t.Errorf("explained weight %+v, want %v at the witness half-life", exp.Sources, want)
+	}
+}
+
+func TestReportsWithBasisKeepEvidenceBounded(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	w, target := types.NewNodeID(10), types.NewNodeID(100)
+	alive := witness.WitnessReport{Witness: w, Target: target, Belief: types.MustBelief(0.9, 0.0, 0.1)}
+
+	for i := 0; i < 10*DefaultMaxEvidencePerWitness; i++ {
+		basis := evidence.KindDirectResponse
+		if i%2 == 0 {
+			basis = evidence.KindTimeout
+		}
+		if _, err := o.ReceiveReportWithBasis(alive, basis); err != nil {
+			t.Fatalf("report %d: %v", i, err)
+		}
+		if n := o.EvidenceCount(target); n > DefaultMaxEvidencePerWitness {
+			t.Fatalf("after %d reports %d evidence items are stored, cap is %d", i+1, n, DefaultMaxEvidencePerWitness)
+		}
+	}
+
+	o.WithMaxEvidencePerWitness(4)
+	if n := o.EvidenceCount(target); n != 4 {
+		t.Errorf("lowering the cap kept %d items, want 4", n)
+	}
+	if got := o.Query(target); got.WitnessCount != 1 {
+		t.Errorf("trimmed evidence counts as %d witnesses, want 1", got.WitnessCount)
+	}
+}
@@ -0,0 +1,79 @@
+package oracle
+
+import (
+	"sort"
+
+	"github.com/styx-oracle/styx/evidence"
+	"github.com/styx-oracle/styx/types"
+)
+
+// SourceContribution is the raw evidence one source contributed about a target
+type SourceContribution struct {
+	Source   types.NodeID
+	Evidence []evidence.Evidence
+	Weight   float64 // total effective weight at the Oracle clock
+}
+
+// Explanation is an answer together with the evidence behind it
+type Explanation struct {
+	Result  QueryResult
+	Sources []SourceContribution // heaviest first
+}
+
+// Explain answers like Query and attributes the raw evidence about target
+// to the sources that gathered it, for debugging a node suspected of
+// sending bad evidence
+// weights decay with the half-life the answer was computed with, the one
+// of the witness holding the evidence or else the set's own
+// evidence past the report TTL or held by evicted witnesses does not count
+// toward the answer and is left out, belief reports carry no raw evidence
+// Explain records nothing, neither the answer nor a cache entry
+func (o *Oracle) Explain(target types.NodeID) Explanation {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	result, settled := o.assess(target)
+	if !settled {
+		result = applyRequirement(result, DefaultRequirement, o.degraded)
+	}
+	return Explanation{Result: result, Sources: o.sourceContributions(target)}
+}
+
+// sourceContributions groups the live evidence about target by source,
+// scanning each evidence set once
+// caller must hold o.mu
+func (o *Oracle) sourceContributions(target types.NodeID) []SourceContribution {
+	evictions := o.registry.HasEvictions()
+	bySource := make(map[types.NodeID]*SourceContribution)
+	for id, set := range o.evidence[target] {
+		if o.expired(set.LatestTimestamp()) || (evictions && o.registry.IsEvicted(id)) {
+			continue
+		}
+		halfLife := o.registry.HalfLife(id)
+		if halfLife == 0 {
+			halfLife = set.HalfLife()
+		}
+		for _, e := range set.All() {
+			c := bySource[e.Source]
+			if c == nil {
+				c = &SourceContribution{Source: e.Source}
+				bySource[e.Source] = c
+			}
+			c.Evidence = append(c.Evidence, e)
+			c.Weight += e.EffectiveWeight(o.clock, halfLife)
+		}
+	}
+
+	sources := make([]SourceContribution, 0, len(bySource))
+	for _, c := range bySource {
+		sort.SliceStable(c.Evidence, func(i, j int) bool { return c.Evidence[i].Timestamp < c.Evidence[j].Timestamp })
+		sources = append(sources, *c)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].Weight != sources[j].Weight {
+			return sources[i].Weight > sources[j].Weight
+		}
+		return sources[i].Source.Less(sources[j].Source)
+	})
+	return sources
+}
@@ -0,0 +1,287 @@
+package oracle
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/styx-oracle/styx/evidence"
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+// Gossip defaults
+const (
+	DefaultGossipMaxHops    = 3
+	DefaultGossipTimeout    = 2 * time.Second
+	DefaultGossipMinBackoff = 100 * time.Millisecond
+	DefaultGossipMaxBackoff = 30 * time.Second
+	DefaultGossipQueueSize  = 256
+)
+
+// gossipReport is the POST /report body sent to peers
+// must stay wire compatible with api.ReportRequest
+// sent as schema v6 so the alive interval, node generations, the origin
+// timestamp and the witness signature survive the hop
+type gossipReport struct {
+	Version           int        `json:"version"`
+	Witness           uint64     `json:"witness"`
+	WitnessGeneration uint64     `json:"witness_generation,omitempty"`
+	Target            uint64     `json:"target"`
+	TargetGeneration  uint64     `json:"target_generation,omitempty"`
+	Alive             float64    `json:"alive"`
+	Dead              float64    `json:"dead"`
+	Unknown           float64    `json:"unknown"`
+	RelayPath         []uint64   `json:"relay_path"`
+	RelayGenerations  []uint64   `json:"relay_generations,omitempty"`
+	Quality           float64    `json:"quality,omitempty"`
+	AliveInterval     [2]float64 `json:"alive_interval"`
+	Basis             string     `json:"basis,omitempty"`
+	Timestamp         uint64     `json:"timestamp,omitempty"` // the report Origin
+	Signature         []byte     `json:"signature,omitempty"`
+}
+
+// gossipBases names the report bases as the api spells them, plain witness
+// reports are sent without one
+var gossipBases = map[evidence.EvidenceKind]string{
+	evidence.KindDirectResponse: "direct",
+	evidence.KindCausalEvent:    "causal",
+	evidence.KindTimeout:        "timeout",
+}
+
+// gossipSchemaVersion is the api report schema gossipReport speaks
+const gossipSchemaVersion = 6
+
+// gossipSend is one report queued for a peer
+type gossipSend struct {
+	client *http.Client
+	url    string
+	body   []byte
+}
+
+// peerBackoff tracks an unreachable peer
+type peerBackoff struct {
+	until time.Time
+	delay time.Duration
+}
+
+// GossipRelay forwards reports to peer oracles so every node in a cluster
+// sees every witness
+// Forwarding is best effort: each peer has one sender goroutine draining a
+// bounded queue, reports arriving at a full queue are dropped (Dropped),
+// unreachable peers are skipped with exponential backoff, reports past
+// maxHops stop spreading
+// Loops and duplicate paths are dropped by the receiver (ReceiveRelayedReport)
+type GossipRelay struct {
+	mu        sync.Mutex
+	peers     []string // base URLs, e.g. http://10.0.0.2:8080
+	maxHops   int
+	client    *http.Client
+	backoff   map[string]*peerBackoff
+	queueSize int
+	queues    map[string]chan gossipSend // started on first use
+	closed    bool
+	dropped   uint64
+	wg        sync.WaitGroup
+}
+
+// NewGossipRelay creates a relay forwarding to the given peer base URLs
+func NewGossipRelay(peers ...string) *GossipRelay {
+	return &GossipRelay{
+		peers:     append([]string(nil), peers...),
+		maxHops:   DefaultGossipMaxHops,
+		client:    &http.Client{Timeout: DefaultGossipTimeout},
+		backoff:   make(map[string]*peerBackoff),
+		queueSize: DefaultGossipQueueSize,
+		queues:    make(map[string]chan gossipSend),
+	}
+}
+
+// WithQueueSize sets how many reports may wait for each peer
+// applies to peers not sent to yet
+func (g *GossipRelay) WithQueueSize(size int) *GossipRelay {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.queueSize = max(size, 1)
+	return g
+}
+
+// WithMaxHops limits how many relays a report may pass through
+func (g *GossipRelay) WithMaxHops(maxHops int) *GossipRelay {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.maxHops = maxHops
+	return g
+}
+
+// WithHTTPClient replaces the client used to reach peers
+func (g *GossipRelay) WithHTTPClient(c *http.Client) *GossipRelay {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.client = c
+	return g
+}
+
+// Wait blocks until queued and in flight forwards finish
+func (g *GossipRelay) Wait() {
+	g.wg.Wait()
+}
+
+// Dropped returns how many forwards were dropped at a full peer queue
+func (g *GossipRelay) Dropped() uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.dropped
+}
+
+// Close stops the peer senders once their queues drain, later reports are
+// not forwarded
+func (g *GossipRelay) Close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return
+	}
+	g.closed = true
+	for _, q := range g.queues {
+		close(q)
+	}
+}
+
+// WithGossipRelay forwards every accepted report to the relay peers
+func (o *Oracle) WithGossipRelay(relay *GossipRelay) *Oracle {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.gossip = relay
+	return o
+}
+
+// forward sends a report on behalf of self with its basis, asynchronously
+// reports already at maxHops are not forwarded
+func (g *GossipRelay) forward(self types.NodeID, namespace string, report witness.WitnessReport, basis evidence.EvidenceKind) {
+	g.mu.Lock()
+	maxHops, client := g.maxHops, g.client
+	g.mu.Unlock()
+
+	if report.Hops() >= maxHops {
+		return
+	}
+
+	hops := make([]types.NodeID, 0, len(report.RelayPath)+1)
+	hops = append(append(hops, report.RelayPath...), self)
+	path := make([]uint64, len(hops))
+	generations := make([]uint64, len(hops))
+	reborn := false
+	for i, hop := range hops {
+		path[i], generations[i] = hop.Base, hop.Generation
+		reborn = reborn || hop.Generation != 0
+	}
+	if !reborn {
+		generations = nil
+	}
+
+	body, err := json.Marshal(gossipReport{
+		Version:           gossipSchemaVersion,
+		Witness:           report.Witness.Base,
+		WitnessGeneration: report.Witness.Generation,
+		Target:            report.Target.Base,
+		TargetGeneration:  report.Target.Generation,
+		Alive:             report.Belief.Alive().Value(),
+		Dead:              report.Belief.Dead().Value(),
+		Unknown:           report.Belief.Unknown().Value(),
+		RelayPath:         path,
+		RelayGenerations:  generations,
+		Quality:           report.Quality,
+		AliveInterval:     report.AliveInterval,
+		Basis:             gossipBases[basis],
+		Timestamp:         report.Origin.Value(),
+		Signature:         report.Signature,
+	})
+	if err != nil {
+		return
+	}
+
+	query := ""
+	if namespace != "" && namespace != DefaultNamespace {
+		query = "?ns=" + url.QueryEscape(namespace)
+	}
+
+	for _, peer := range g.peers {
+		if g.ready(peer) {
+			g.enqueue(peer, gossipSend{client: client, url: peer + "/report" + query, body: body})
+		}
+	}
+}
+
+// enqueue hands send to the peer's sender, dropping it when the queue is full
+func (g *GossipRelay) enqueue(peer string, send gossipSend) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return
+	}
+
+	q := g.queues[peer]
+	if q == nil {
+		q = make(chan gossipSend, g.queueSize)
+		g.queues[peer] = q
+		go g.send(peer, q)
+	}
+	g.wg.Add(1)
+	select {
+	case q <- send:
+	default:
+		g.wg.Done()
+		g.dropped++
+	}
+}
+
+// send posts queued reports to peer one at a time until the queue closes
+// reports queued before the peer was backed off are skipped
+func (g *GossipRelay) send(peer string, q <-chan gossipSend) {
+	for s := range q {
+		if g.ready(peer) {
+			g.settle(peer, g.post(s.client, s.url, s.body))
+		}
+		g.wg.Done()
+	}
+}
+
+func (g *GossipRelay) post(client *http.Client, target string, body []byte) bool {
+	resp, err := client.Post(target, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode < http.StatusInternalServerError
+}
+
+// ready reports whether a peer is outside its backoff window
+func (g *GossipRelay) ready(peer string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	b, ok := g.backoff[peer]
+	return !ok || time.Now().After(b.until)
+}
+
+// settle resets backoff on success, doubles it on failure
+func (g *GossipRelay) settle(peer string, ok bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if ok {
+		delete(g.backoff, peer)
+		return
+	}
+	b := g.backoff[peer]
+	if b == nil {
+		b = &peerBackoff{delay: DefaultGossipMinBackoff}
+		g.backoff[peer] = b
+	} else {
+		b.delay = min(2*b.delay, DefaultGossipMaxBackoff)
+	}
+	b.until = time.Now().Add(b.delay)
+}
@@ -0,0 +1,66 @@
+package oracle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+func TestGossipRelayBacksOffUnreachablePeer(t *testing.T) {
+	srv := httptest.NewServer(nil)
+	peer := srv.URL
+	srv.Close() // nothing listens there anymore
+
+	relay := NewGossipRelay(peer)
+	o := New(types.NewNodeID(1)).WithGossipRelay(relay)
+
+	o.ReceiveReport(types.NewNodeID(10), types.NewNodeID(100), types.MustBelief(0.8, 0.1, 0.1))
+	relay.Wait()
+
+	if relay.ready(peer) {
+		t.Fatal("unreachable peer not backed off")
+	}
+	first := relay.backoff[peer].delay
+
+	relay.settle(peer, false)
+	if relay.backoff[peer].delay != 2*first {
+		t.Errorf("backoff did not double: %v -> %v", first, relay.backoff[peer].delay)
+	}
+
+	relay.settle(peer, true)
+	if !relay.ready(peer) {
+		t.Error("backoff not cleared after success")
+	}
+}
+
+func TestGossipRelayQueueIsBounded(t *testing.T) {
+	release := make(chan struct{})
+	var received atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		received.Add(1)
+	}))
+	defer srv.Close()
+
+	relay := NewGossipRelay(srv.URL).WithQueueSize(2)
+	defer relay.Close()
+	o := New(types.NewNodeID(1)).WithGossipRelay(relay)
+
+	// the peer hangs, at most one report in flight and two queued
+	const sent = 10
+	for i := range sent {
+		o.ReceiveReport(types.NewNodeID(uint64(10+i)), types.NewNodeID(100), types.MustBelief(0.8, 0.1, 0.1))
+	}
+	if d := relay.Dropped(); d < sent-3 {
+		t.Errorf("%d reports dropped, want at least %d", d, sent-3)
+	}
+
+	close(release)
+	relay.Wait()
+	if got := received.Load(); got != sent-int64(relay.Dropped()) {
+		t.Errorf("peer received %d reports, want %d", got, sent-int64(relay.Dropped()))
+	}
+}
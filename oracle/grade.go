@@ -0,0 +1,65 @@
+package oracle
+
+import "github.com/styx-oracle/styx/types"
+
+// ConfidenceGrade is a coarse label for how far an answer can be trusted
+type ConfidenceGrade int
+
+const (
+	// GradeUnreliable - no usable evidence or witnesses are split
+	GradeUnreliable ConfidenceGrade = iota
+	// GradeLow - an answer exists but would have been refused in strict mode
+	GradeLow
+	// GradeMedium - answer is directionally useful but uncertain
+	GradeMedium
+	// GradeHigh - answer is backed by strong, agreeing evidence
+	GradeHigh
+)
+
+func (g ConfidenceGrade) String() string {
+	switch g {
+	case GradeHigh:
+		return "HIGH"
+	case GradeMedium:
+		return "MEDIUM"
+	case GradeLow:
+		return "LOW"
+	default:
+		return "UNRELIABLE"
+	}
+}
+
+// Grade thresholds for answered queries
+const (
+	// HighGradeMaxUnknown is the most unknown a HIGH answer may carry
+	HighGradeMaxUnknown = 0.3
+	// HighGradeMaxDisagreement is the most disagreement a HIGH answer may carry
+	HighGradeMaxDisagreement = 0.1
+	// MediumGradeMaxUnknown is the most unknown a MEDIUM answer may carry
+	MediumGradeMaxUnknown = 0.6
+)
+
+// gradeBelief grades an answer that met its requirements
+func gradeBelief(belief types.Belief, disagreement float64) ConfidenceGrade {
+	unknown := belief.Unknown().Value()
+	if unknown <= HighGradeMaxUnknown && disagreement <= HighGradeMaxDisagreement {
+		return GradeHigh
+	}
+	if unknown <= MediumGradeMaxUnknown {
+		return GradeMedium
+	}
+	return GradeLow
+}
+
+// refuse marks a result as refused, or in degraded mode as answered
+// with a low grade whose rationale is the refusal reason
+func refuse(result QueryResult, reason, note string, grade ConfidenceGrade, degraded bool) QueryResult {
+	result.Evidence = append(result.Evidence, note)
+	result.Confidence = grade
+	result.GradeRationale = reason
+	if !degraded {
+		result.Refused = true
+		result.RefusalReason = reason
+	}
+	return result
+}
@@ -0,0 +1,138 @@
+package oracle
+
+import (
+	"github.com/styx-oracle/styx/types"
+)
+
+// GroupPolicy decides when a group of targets counts as alive
+type GroupPolicy int
+
+const (
+	// AnyAlive - group is alive if at least one member is alive
+	AnyAlive GroupPolicy = iota
+	// AllAlive - group is alive only if every member is alive
+	AllAlive
+	// MajorityAlive - group is alive if more than half the members are alive
+	MajorityAlive
+)
+
+func (p GroupPolicy) String() string {
+	switch p {
+	case AnyAlive:
+		return "ANY_ALIVE"
+	case AllAlive:
+		return "ALL_ALIVE"
+	case MajorityAlive:
+		return "MAJORITY_ALIVE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// required returns how many members must be alive for the group to be alive
+func (p GroupPolicy) required(n int) int {
+	switch p {
+	case AllAlive:
+		return n
+	case MajorityAlive:
+		return n/2 + 1
+	default:
+		return 1
+	}
+}
+
+// QueryGroup combines member beliefs into a single belief about a group
+// Members are treated as independent
+//   - group alive: at least policy.required members alive
+//   - group dead: enough members dead that the policy cannot hold
+//   - anything else stays unknown
+//
+// The result has no Target, refusals of individual members are listed in Evidence
+func (o *Oracle) QueryGroup(targets []types.NodeID, policy GroupPolicy) QueryResult {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	result := QueryResult{}
+	if len(targets) == 0 {
+		result.Belief = types.UnknownBelief()
+		result.Evidence = append(result.Evidence, "empty group")
+		result.Confidence = GradeUnreliable
+		result.GradeRationale = "empty group"
+		return result
+	}
+
+	members := make([]types.Belief, len(targets))
+	for i, target := range targets {
+		member, _ := o.assess(target)
+		members[i] = member.Belief
+
+		result.WitnessCount += member.WitnessCount
+		if member.Disagreement > result.Disagreement {
+			result.Disagreement = member.Disagreement
+		}
+		if member.PartitionState > result.PartitionState {
+			result.PartitionState = member.PartitionState
+		}
+		if member.Refused {
+			result.Evidence = append(result.Evidence,
+				"member "+target.String()+" refused: "+member.RefusalReason)
+		}
+		if member.Dead {
+			result.Evidence = append(result.Evidence, "member "+target.String()+" declared dead")
+		}
+	}
+
+	result.Belief = combineGroup(members, policy.required(len(members)))
+	result.Evidence = append(result.Evidence,
+		"group of "+itoa(len(targets))+" targets under "+policy.String())
+	result.Confidence = gradeBelief(result.Belief, result.Disagreement)
+	return result
+}
+
+// combineGroup computes the belief that at least need members are alive
+// dist[a][d] is the probability that a members are alive and d are dead
+func combineGroup(members []types.Belief, need int) types.Belief {
+	n := len(members)
+	dist := make([][]float64, n+1)
+	for i := range dist {
+		dist[i] = make([]float64, n+1)
+	}
+	dist[0][0] = 1
+
+	for i, m := range members {
+		pa, pd, pu := m.Alive().Value(), m.Dead().Value(), m.Unknown().Value()
+		for a := i; a >= 0; a-- {
+			for d := i - a; d >= 0; d-- {
+				p := dist[a][d]
+				if p == 0 {
+					continue
+				}
+				dist[a][d] = p * pu
+				dist[a+1][d] += p * pa
+				dist[a][d+1] += p * pd
+			}
+		}
+	}
+
+	// The group fails once more than n-need members are dead
+	failAt := n - need + 1
+	var alive, dead float64
+	for a := 0; a <= n; a++ {
+		for d := 0; a+d <= n; d++ {
+			switch {
+			case a >= need:
+				alive += dist[a][d]
+			case d >= failAt:
+				dead += dist[a][d]
+			}
+		}
+	}
+
+	// Property 8: unknown is never forced to zero
+	alive, dead, _ = types.ApplyUnknownFloor(alive, dead, 1.0-alive-dead)
+	belief, err := types.NewBelief(alive, dead, 1.0-alive-dead)
+	if err != nil {
+		return types.UnknownBelief()
+	}
+	return belief
+}
@@ -0,0 +1,175 @@
+package oracle
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+
+	"github.com/styx-oracle/styx/observer"
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+)
+
+// ErrNoProbeFunc is returned when the harness probes without a probe function
+var ErrNoProbeFunc = errors.New("harness has no probe function")
+
+// Beliefs the harness reports for probe outcomes
+var (
+	// HarnessProbeSuccess is reported for a successful probe
+	HarnessProbeSuccess = types.MustBelief(0.8, 0.05, 0.15)
+	// HarnessProbeTimeout is reported for a failed probe, weak per P15
+	HarnessProbeTimeout = types.MustBelief(0.1, 0.3, 0.6)
+)
+
+// ReportCall records a single ReceiveReport made through a Harness
+type ReportCall struct {
+	Witness   types.NodeID
+	Target    types.NodeID
+	Belief    types.Belief
+	Timestamp styxtime.LogicalTimestamp
+}
+
+// Scenario drives a Harness, it must only use the harness for randomness
+type Scenario func(h *Harness)
+
+// Harness wraps an Oracle for deterministic chaos and simulation tests
+// - logical clock advanced manually, never by wall time
+// - random source seeded explicitly
+// - probe function injected by the test
+// - every ReceiveReport recorded in order
+// Running the same scenario with the same seed is reproducible
+type Harness struct {
+	mu       sync.Mutex
+	selfID   types.NodeID
+	seed     int64
+	oracle   *Oracle
+	rng      *rand.Rand
+	probe    observer.ProbeFunc
+	calls    []ReportCall
+	scenario Scenario
+}
+
+// NewHarness creates a harness around a fresh Oracle
+func NewHarness(selfID types.NodeID, seed int64) *Harness {
+	return NewHarnessFor(New(selfID), seed)
+}
+
+// NewHarnessFor creates a harness around an existing Oracle
+// used to drive an Oracle owned by something else, like an api.Server
+func NewHarnessFor(o *Oracle, seed int64) *Harness {
+	return &Harness{
+		selfID: o.selfID,
+		seed:   seed,
+		oracle: o,
+		rng:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Oracle returns the wrapped Oracle
+func (h *Harness) Oracle() *Oracle {
+	return h.oracle
+}
+
+// Seed returns the seed of the random source
+func (h *Harness) Seed() int64 {
+	return h.seed
+}
+
+// Rand returns the harness random source
+// Scenarios must use this instead of the global source to stay reproducible
+func (h *Harness) Rand() *rand.Rand {
+	return h.rng
+}
+
+// Now returns the Oracle logical clock
+func (h *Harness) Now() styxtime.LogicalTimestamp {
+	h.oracle.mu.RLock()
+	defer h.oracle.mu.RUnlock()
+	return h.oracle.clock
+}
+
+// AdvanceClock moves the Oracle logical clock forward by n
+func (h *Harness) AdvanceClock(n uint64) styxtime.LogicalTimestamp {
+	h.oracle.mu.Lock()
+	defer h.oracle.mu.Unlock()
+	h.oracle.clock += styxtime.LogicalTimestamp(n)
+	return h.oracle.clock
+}
+
+// SetProbeFunc injects the function used by Probe
+func (h *Harness) SetProbeFunc(fn observer.ProbeFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.probe = fn
+}
+
+// Probe runs the probe function on behalf of a witness and reports the outcome
+func (h *Harness) Probe(witness, target types.NodeID) (observer.ProbeResult, error) {
+	h.mu.Lock()
+	fn := h.probe
+	h.mu.Unlock()
+
+	if fn == nil {
+		return observer.ProbeResult{}, ErrNoProbeFunc
+	}
+
+	result := fn(target)
+
+	belief := HarnessProbeTimeout
+	if result.Success {
+		belief = HarnessProbeSuccess
+	}
+	result.Timestamp = h.ReceiveReport(witness, target, belief)
+	return result, nil
+}
+
+// ReceiveReport forwards a report to the Oracle and records it
+// Returns the logical time the report was stamped with
+func (h *Harness) ReceiveReport(witness, target types.NodeID, belief types.Belief) styxtime.LogicalTimestamp {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ts := h.oracle.ReceiveReport(witness, target, belief)
+	h.calls = append(h.calls, ReportCall{
+		Witness:   witness,
+		Target:    target,
+		Belief:    belief,
+		Timestamp: ts,
+	})
+	return ts
+}
+
+// Calls returns every report received through the harness, in order
+func (h *Harness) Calls() []ReportCall {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]ReportCall(nil), h.calls...)
+}
+
+// Query asks the wrapped Oracle about a node
+func (h *Harness) Query(target types.NodeID) QueryResult {
+	return h.oracle.Query(target)
+}
+
+// Run executes a scenario against this harness and remembers it for Replay
+func (h *Harness) Run(s Scenario) *Harness {
+	h.mu.Lock()
+	h.scenario = s
+	h.mu.Unlock()
+	s(h)
+	return h
+}
+
+// Replay runs the last scenario again on a fresh harness with another seed
+// Used for property based checks across many seeds
+func (h *Harness) Replay(seed int64) *Harness {
+	h.mu.Lock()
+	s := h.scenario
+	h.mu.Unlock()
+
+	replay := NewHarness(h.selfID, seed)
+	if s != nil {
+		replay.Run(s)
+	}
+	return replay
+}
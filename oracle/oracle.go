@@ -1,11 +1,20 @@
 package oracle
 
 import (
+	"context"
+	"crypto/ed25519"
 	"errors"
+	"fmt"
+	"math"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/styx-oracle/styx/evidence"
 	"github.com/styx-oracle/styx/finality"
+	"github.com/styx-oracle/styx/metrics"
 	"github.com/styx-oracle/styx/partition"
+	styxtime "github.com/styx-oracle/styx/time"
 	"github.com/styx-oracle/styx/types"
 	"github.com/styx-oracle/styx/witness"
 )
@@ -14,6 +23,17 @@ import (
 var (
 	ErrRefused = errors.New("oracle refuses to answer due to uncertainty")
 	ErrDead    = errors.New("node is dead")
+
+	ErrRelayLoop      = errors.New("relayed report already passed through this node")
+	ErrDuplicateRelay = errors.New("relayed report duplicates a known observation")
+
+	ErrInvalidSignature = witness.ErrInvalidSignature
+	ErrMissingSignature = witness.ErrMissingSignature
+	ErrUnknownSigner    = witness.ErrUnknownSigner
+	ErrInvalidPublicKey = errors.New("public key is not a valid ed25519 key")
+
+	ErrWitnessNotFound = errors.New("witness is not registered")
+	ErrWitnessEvicted  = errors.New("witness is evicted")
 )
 
 // QueryResult is the full response from the Oracle
@@ -27,6 +47,19 @@ type QueryResult struct {
 	Disagreement   float64
 	PartitionState partition.PartitionState
 	Evidence       []string
+	// Confidence grades the answer, GradeRationale explains LOW/UNRELIABLE grades
+	Confidence     ConfidenceGrade
+	GradeRationale string
+	// 90% credible intervals [low, high] for alive and dead
+	AliveInterval [2]float64
+	DeadInterval  [2]float64
+	// PartitionedBeliefs holds one aggregated belief per side of a confirmed
+	// partition, in SplitReality.Groups order, so callers can pick the side
+	// they trust, the query is still refused
+	PartitionedBeliefs []types.Belief
+	// Timestamp is the Oracle logical time the answer was computed at,
+	// every report stamped at or before it was considered
+	Timestamp styxtime.LogicalTimestamp
 }
 
 // RequiredConfidence specifies minimum confidence for a query
@@ -52,26 +85,217 @@ var StrictRequirement = RequiredConfidence{
 
 // Oracle is the main STYX interface
 type Oracle struct {
-	mu         sync.RWMutex
-	selfID     types.NodeID
-	registry   *witness.Registry
-	aggregator *witness.Aggregator
-	finality   *finality.Engine
-	partition  *partition.Detector
-	reports    map[types.NodeID][]witness.WitnessReport
+	mu          sync.RWMutex
+	selfID      types.NodeID
+	namespace   string
+	registry    *witness.Registry
+	aggregator  *witness.Aggregator
+	finality    *finality.Engine
+	partition   *partition.Detector
+	collusion   *witness.CollusionDetector
+	reports     reportStore                                             // see report_store.go
+	evidence    map[types.NodeID]map[types.NodeID]*evidence.EvidenceSet // target -> witness
+	clock       styxtime.LogicalTimestamp
+	events      *eventBus
+	degraded    bool
+	gossip      *GossipRelay
+	cluster     *Cluster
+	reportTTL   uint64
+	maxReports  int // per target, 0 = unbounded
+	maxEvidence int // per witness and target, 0 = unbounded
+	trends      *trendLog
+	metrics     atomic.Pointer[metrics.Metrics] // read without o.mu, see Metrics
+	answers     *answerLog
+	transitions *transitionLog
+	cache       *queryCache // nil = disabled, see WithQueryCache
+	cacheTTL    time.Duration
+	beliefCache bool
+	// partitionPending counts changes per target since its partition state
+	// was last recorded, see trackPartition
+	partitionPending map[types.NodeID]int
+	deaths           *deathWatch
+	// minNonTimeout is the non-timeout evidence fraction below which dead
+	// confidence is capped at SilenceDeadCap, 0 disables the cap
+	minNonTimeout float64
+	// allowForce enables ForceFinality, see WithOperatorOverride
+	allowForce bool
 }
 
-// New creates a new Oracle
+// New creates a new Oracle in the default namespace
 func New(selfID types.NodeID) *Oracle {
+	return NewWithNamespace(selfID, DefaultNamespace)
+}
+
+// NewWithNamespace creates a new Oracle for one isolated cluster
+func NewWithNamespace(selfID types.NodeID, namespace string) *Oracle {
 	reg := witness.NewRegistry()
-	return &Oracle{
-		selfID:     selfID,
-		registry:   reg,
-		aggregator: witness.NewAggregator(reg),
-		finality:   finality.NewEngine(reg),
-		partition:  partition.NewDetector(),
-		reports:    make(map[types.NodeID][]witness.WitnessReport),
+	collusion := witness.NewCollusionDetector()
+	o := &Oracle{
+		selfID:      selfID,
+		namespace:   namespace,
+		registry:    reg,
+		aggregator:  witness.NewAggregator(reg).WithCollusionDetector(collusion).WithPreverifiedReports(),
+		finality:    finality.NewEngine(reg),
+		partition:   partition.NewDetector(),
+		collusion:   collusion,
+		evidence:    make(map[types.NodeID]map[types.NodeID]*evidence.EvidenceSet),
+		events:      newEventBus(),
+		trends:      newTrendLog(),
+		answers:     newAnswerLog(),
+		transitions: newTransitionLog(),
+		deaths:      newDeathWatch(),
+
+		partitionPending: make(map[types.NodeID]int),
+
+		minNonTimeout: finality.MinNonTimeoutEvidence,
+		maxEvidence:   DefaultMaxEvidencePerWitness,
+	}
+	o.metrics.Store(&metrics.Metrics{})
+	return o
+}
+
+// Clock returns the current Oracle logical time
+// it advances on every stored report and evidence, queries read it but do
+// not advance it: logical time drives evidence decay, report TTL and
+// partition cooldown, so a clock ticked by polling would let merely
+// reading a belief change it
+func (o *Oracle) Clock() styxtime.LogicalTimestamp {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.clock
+}
+
+// Namespace returns the cluster this Oracle answers for
+func (o *Oracle) Namespace() string {
+	return o.namespace
+}
+
+// WithDegradedMode makes the Oracle answer instead of refusing
+// Refusals become LOW or UNRELIABLE graded answers carrying the best belief
+// and the refusal reason as rationale
+func (o *Oracle) WithDegradedMode(enabled bool) *Oracle {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.degraded = enabled
+	o.invalidateAll()
+	return o
+}
+
+// WithReportTTL stops reports older than ttl logical ticks from voting
+// They stay stored for audit, they just no longer influence aggregation,
+// so a witness that went quiet cannot pin a stale opinion forever
+// 0 disables expiry
+func (o *Oracle) WithReportTTL(ttl uint64) *Oracle {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.reportTTL = ttl
+	o.invalidateAll()
+	return o
+}
+
+// WithMinNonTimeoutEvidence sets the fraction of non-timeout evidence a
+// query needs before it may report dead confidence above SilenceDeadCap
+// P15 at query time: silence alone cannot make a node look dead
+// defaults to finality.MinNonTimeoutEvidence, 0 disables the cap
+func (o *Oracle) WithMinNonTimeoutEvidence(fraction float64) *Oracle {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.minNonTimeout = fraction
+	o.invalidateAll()
+	return o
+}
+
+// WithMaxReportsPerTarget keeps at most n reports per target, so a chatty
+// cluster cannot grow memory without bound
+// a full target drops the oldest report of the witness holding the most,
+// one witness flooding a target only replaces its own reports
+// 0 or less keeps every report
+func (o *Oracle) WithMaxReportsPerTarget(n int) *Oracle {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if n < 0 {
+		n = 0
 	}
+	o.maxReports = n
+	o.reports.trimAll(n)
+	o.invalidateAll()
+	return o
+}
+
+// DefaultMaxEvidencePerWitness bounds the evidence kept per witness and
+// target, evidence reports arrive over HTTP from anyone
+const DefaultMaxEvidencePerWitness = 128
+
+// WithMaxEvidencePerWitness keeps at most n evidence items per witness and
+// target, trimmed with evidence.EvidenceSet.TrimToSize so the latest alive
+// and dead evidence survive
+// 0 or less keeps every item
+func (o *Oracle) WithMaxEvidencePerWitness(n int) *Oracle {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if n < 0 {
+		n = 0
+	}
+	o.maxEvidence = n
+	for _, byWitness := range o.evidence {
+		for _, set := range byWitness {
+			set.TrimToSize(n)
+		}
+	}
+	o.invalidateAll()
+	return o
+}
+
+// EvidenceCount returns how many evidence items are stored for target
+func (o *Oracle) EvidenceCount(target types.NodeID) int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	n := 0
+	for _, set := range o.evidence[target] {
+		n += set.Len()
+	}
+	return n
+}
+
+// ReportCount returns how many reports are stored for target
+func (o *Oracle) ReportCount(target types.NodeID) int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return len(o.reports.load(target))
+}
+
+// WithMaxWitnessShare caps the share of aggregation weight any one witness
+// can hold, see witness.Aggregator.WithMaxWitnessShare
+func (o *Oracle) WithMaxWitnessShare(share float64) *Oracle {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.aggregator.WithMaxWitnessShare(share)
+	o.invalidateAll()
+	return o
+}
+
+// WithAggregationMode picks how witness beliefs are combined,
+// see witness.AggregationMode
+func (o *Oracle) WithAggregationMode(m witness.AggregationMode) *Oracle {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.aggregator.WithMode(m)
+	o.invalidateAll()
+	return o
+}
+
+// WithMetrics sends belief change and query metrics to m instead of the
+// Oracle's own, nil turns them off
+func (o *Oracle) WithMetrics(m *metrics.Metrics) *Oracle {
+	o.metrics.Store(m)
+	return o
+}
+
+// Metrics returns where the Oracle records its metrics, nil if off
+// every Oracle has its own, so namespaces never mix their series
+// never waits for o.mu, a stalled Oracle can still count its timeouts
+func (o *Oracle) Metrics() *metrics.Metrics {
+	return o.metrics.Load()
 }
 
 // RegisterWitness adds a trusted witness
@@ -79,22 +303,292 @@ func (o *Oracle) RegisterWitness(id types.NodeID) {
 	o.registry.Register(id)
 }
 
-// ReceiveReport records a witness report
-func (o *Oracle) ReceiveReport(witnessID, target types.NodeID, belief types.Belief) {
+// RegisterWitnesses registers several witnesses at once, e.g. to bootstrap
+// a cluster, witnesses already registered keep their trust
+func (o *Oracle) RegisterWitnesses(ids []types.NodeID) {
+	for _, id := range ids {
+		o.registry.Register(id)
+	}
+}
+
+// EvictWitness removes a witness that left the cluster
+// its reports are ignored from now on and new ones are dropped until it is
+// registered again, returns false if it was not registered
+func (o *Oracle) EvictWitness(id types.NodeID) bool {
+	return o.registry.Evict(id)
+}
+
+// RemoveWitness evicts a witness and purges everything it reported, its
+// belief reports and its evidence, so no answer is built from them again
+// a witness already evicted by EvictWitness is purged all the same
+// returns ErrWitnessNotFound if it was neither registered nor left anything
+func (o *Oracle) RemoveWitness(id types.NodeID) error {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 
-	o.registry.Register(witnessID)
-	report := witness.WitnessReport{
+	found := o.registry.Evict(id)
+	for _, target := range o.reports.removeWitness(id) {
+		found = true
+		o.recordPartition(target)
+		o.invalidate(target)
+	}
+	for target, byWitness := range o.evidence {
+		if _, ok := byWitness[id]; ok {
+			found = true
+			delete(byWitness, id)
+			o.recordPartition(target)
+			o.invalidate(target)
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: %s", ErrWitnessNotFound, id)
+	}
+	return nil
+}
+
+// Witnesses returns a snapshot of the living witnesses
+func (o *Oracle) Witnesses() []witness.WitnessRecord {
+	return o.registry.Snapshot()
+}
+
+// WitnessSummary returns the fleet wide witness trust summary
+func (o *Oracle) WitnessSummary() witness.TrustSummary {
+	return o.registry.ScoreSummary()
+}
+
+// ReceiveReport records a witness report
+// Returns the logical timestamp the Oracle assigned to it, later reports
+// always get later timestamps
+// Reports from an evicted witness, or from a witness with a public key
+// (the report is unsigned), are dropped, the current clock is returned
+func (o *Oracle) ReceiveReport(witnessID, target types.NodeID, belief types.Belief) styxtime.LogicalTimestamp {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.record(witness.WitnessReport{
 		Witness: witnessID,
 		Target:  target,
 		Belief:  belief,
+	})
+}
+
+// ReceiveWitnessReport records a report built by the witness itself, such as
+// observer.Prober.WitnessReport, keeping its quality
+// the Oracle stamps its own timestamp and returns it, a timestamp the report
+// carries moves the Oracle clock forward first, relay paths are ignored
+func (o *Oracle) ReceiveWitnessReport(report witness.WitnessReport) styxtime.LogicalTimestamp {
+	report.RelayPath = nil
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.record(report)
+}
+
+// SetPublicKey registers the key a witness signs its reports with
+// kept in the witness registry, from then on every report from that
+// witness must be signed, see ReceiveSignedReport
+func (o *Oracle) SetPublicKey(witnessID types.NodeID, pubKey ed25519.PublicKey) error {
+	if len(pubKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: got %d bytes", ErrInvalidPublicKey, len(pubKey))
 	}
 
-	if o.reports[target] == nil {
-		o.reports[target] = make([]witness.WitnessReport, 0)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.registry.SetPublicKey(witnessID, pubKey)
+	return nil
+}
+
+// VerifyAndReceiveReport records a types.SignedReport, see ReceiveSignedReport
+// both sign the same payload and verify through the witness registry
+// unlike ReceiveSignedReport every report must be signed by a witness with
+// a public key: missing signatures, unknown signers and tampered reports
+// get ErrInvalidSignature, reports from an evicted witness ErrWitnessEvicted
+func (o *Oracle) VerifyAndReceiveReport(sr types.SignedReport) error {
+	if len(sr.Signature) == 0 {
+		return fmt.Errorf("%w: %w: %s", ErrInvalidSignature, ErrMissingSignature, sr.Witness)
+	}
+	_, err := o.ReceiveSignedReport(witness.WitnessReport{
+		Witness:   sr.Witness,
+		Target:    sr.Target,
+		Belief:    sr.Belief,
+		Timestamp: styxtime.LogicalTimestamp(sr.Timestamp),
+		Signature: sr.Signature,
+	})
+	if errors.Is(err, ErrUnknownSigner) || errors.Is(err, ErrMissingSignature) {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	return err
+}
+
+// ReceiveSignedReport records a report signed with witness.WitnessReport.Sign
+// the signature covers the timestamp the witness signed, it is checked
+// before the Oracle restamps the report
+// returns ErrInvalidSignature or ErrMissingSignature for reports failing
+// verification, ErrUnknownSigner for a signed report from a witness
+// without a public key (SetPublicKey) and ErrWitnessEvicted for an evicted
+// witness, relay paths are ignored
+func (o *Oracle) ReceiveSignedReport(report witness.WitnessReport) (styxtime.LogicalTimestamp, error) {
+	report.RelayPath = nil
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.registry.IsEvicted(report.Witness) {
+		return 0, fmt.Errorf("%w: %s", ErrWitnessEvicted, report.Witness)
+	}
+	if err := o.registry.VerifyReport(report); err != nil {
+		return 0, fmt.Errorf("%w: %s", err, report.Witness)
+	}
+	return o.store(report), nil
+}
+
+// checkSignature rejects reports a keyed witness did not sign validly
+// signatures of witnesses without a key are not checked, there is nothing
+// to check them against
+// caller must hold o.mu
+func (o *Oracle) checkSignature(report witness.WitnessReport) error {
+	if err := o.registry.VerifyReport(report); err != nil && !errors.Is(err, witness.ErrUnknownSigner) {
+		return fmt.Errorf("%w: %s", err, report.Witness)
+	}
+	return nil
+}
+
+// ReceiveRelayedReport records a report forwarded by other nodes
+// Reports that already passed through this node are loops and rejected
+// Copies of one observation arriving over several relay paths count once,
+// so gossip cannot inflate the witness count
+func (o *Oracle) ReceiveRelayedReport(report witness.WitnessReport) error {
+	return o.ReceiveRelayedReportWithBasis(report, evidence.KindWitnessReport)
+}
+
+// ReceiveRelayedReportWithBasis is ReceiveRelayedReport for a report
+// relayed with its basis, stored like ReceiveReportWithBasis stores it
+func (o *Oracle) ReceiveRelayedReportWithBasis(report witness.WitnessReport, basis evidence.EvidenceKind) error {
+	if report.HasVisited(o.selfID) {
+		return ErrRelayLoop
+	}
+	report = withOrigin(report)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.registry.IsEvicted(report.Witness) {
+		return fmt.Errorf("%w: %s", ErrWitnessEvicted, report.Witness)
+	}
+	if err := o.checkSignature(report); err != nil {
+		return err
+	}
+
+	if basis == evidence.KindWitnessReport {
+		for _, known := range o.reports.load(report.Target) {
+			if known.SameObservation(report) {
+				return ErrDuplicateRelay
+			}
+		}
+	} else if e, err := basisEvidence(report, basis); err != nil {
+		return err
+	} else if o.knownRelayedEvidence(report.Witness, e) {
+		return ErrDuplicateRelay
+	}
+
+	report.RelayPath = append([]types.NodeID(nil), report.RelayPath...)
+	_, err := o.receiveBasis(report, basis)
+	return err
+}
+
+// withOrigin gives a report that has no Origin the timestamp it carries
+// a report carrying none gets its Origin when it is stamped, see stampOrigin
+func withOrigin(report witness.WitnessReport) witness.WitnessReport {
+	if report.Origin == 0 {
+		report.Origin = report.Timestamp
+	}
+	return report
+}
+
+// stampOrigin gives a report still without an Origin the timestamp ts it
+// was stamped with
+// signed reports keep the timestamp they were signed with, even none:
+// relays carry the Origin as the timestamp peers verify the signature over
+func stampOrigin(report witness.WitnessReport, ts styxtime.LogicalTimestamp) witness.WitnessReport {
+	if report.Origin == 0 && len(report.Signature) == 0 {
+		report.Origin = ts
+	}
+	return report
+}
+
+// record stamps and stores a report, returning the assigned timestamp
+// reports failing checkSignature are dropped, the current clock is returned
+// caller must hold o.mu for writing
+func (o *Oracle) record(report witness.WitnessReport) styxtime.LogicalTimestamp {
+	if o.checkSignature(report) != nil {
+		return o.clock
+	}
+	return o.store(report)
+}
+
+// store stamps and stores a report whose signature was checked
+// stamping and appending under one write lock keeps every list in stamp
+// order and serializes the store's writers
+// caller must hold o.mu for writing
+func (o *Oracle) store(report witness.WitnessReport) styxtime.LogicalTimestamp {
+	report = withOrigin(report)
+	if !o.registry.IsRegistered(report.Witness) {
+		if !o.registry.Admit(report.Witness) {
+			// evicted, dropped until registered again
+			return o.clock
+		}
+		// a witness never seen before cannot move the clock
+		report.Timestamp = 0
+	}
+	report = o.stamp(report)
+	report = stampOrigin(report, report.Timestamp)
+	o.reports.append(report, o.maxReports)
+	o.trackPartition(report.Target)
+	o.invalidate(report.Target)
+	o.announce(report)
+	return report.Timestamp
+}
+
+// MaxClockJump bounds how far one carried report timestamp can move the
+// Oracle clock, a tenth of the default evidence half-life: logical time
+// drives decay, report TTL and partition cooldown, so a corrupt or hostile
+// timestamp must not decay evidence or expire reports in one go
+// a witness far ahead is caught up with over several reports
+const MaxClockJump = evidence.DefaultHalfLife / 10
+
+// maxClockJump is MaxClockJump, lowered to half the report TTL when that
+// is shorter so one report never expires the others
+// caller must hold o.mu
+func (o *Oracle) maxClockJump() uint64 {
+	if o.reportTTL > 0 {
+		return min(MaxClockJump, o.reportTTL/2)
+	}
+	return MaxClockJump
+}
+
+// stamp assigns an admitted report its timestamp
+// a timestamp the report already carries advances the clock Lamport style,
+// max(clock, carried) + 1, so the Oracle stays causally after its witnesses,
+// by at most maxClockJump, witnesses first seen with this report carry none
+// caller must hold o.mu for writing
+func (o *Oracle) stamp(report witness.WitnessReport) witness.WitnessReport {
+	o.registry.RecordReport(report.Witness, report.Belief)
+	report.Timestamp = o.clock.Update(min(report.Timestamp, o.clock.Add(o.maxClockJump())))
+	o.collusion.Observe(report)
+	return report
+}
+
+// announce publishes a stored report to subscribers and gossip peers
+// caller must hold o.mu (read is enough)
+func (o *Oracle) announce(report witness.WitnessReport) {
+	// Only pay for aggregation when someone is listening
+	if o.events.hasSubscribers(report.Target) {
+		result, _ := o.assess(report.Target)
+		o.events.publish(report.Target, result.Belief, report.Timestamp)
+	}
+
+	if o.gossip != nil {
+		o.gossip.forward(o.selfID, o.namespace, report, evidence.KindWitnessReport)
 	}
-	o.reports[target] = append(o.reports[target], report)
 }
 
 // Query asks the Oracle about a node
@@ -108,74 +602,249 @@ func (o *Oracle) Query(target types.NodeID) QueryResult {
 func (o *Oracle) QueryWithRequirement(target types.NodeID, req RequiredConfidence) QueryResult {
 	o.mu.RLock()
 	defer o.mu.RUnlock()
+	return o.query(target, req)
+}
+
+// queryLockPoll is how often QueryCtx retries a busy Oracle lock
+const queryLockPoll = time.Millisecond
+
+// QueryCtx is Query bounded by ctx
+// Returns ctx.Err() if ctx ends before the answer is ready
+// a query still waiting for the Oracle lock when ctx ends is dropped
+// without assessing or recording anything, and leaves nothing waiting
+// behind: the lock is polled rather than waited on
+func (o *Oracle) QueryCtx(ctx context.Context, target types.NodeID) (QueryResult, error) {
+	if err := ctx.Err(); err != nil {
+		return QueryResult{}, err
+	}
+
+	if !o.mu.TryRLock() {
+		tick := time.NewTicker(queryLockPoll)
+		defer tick.Stop()
+		for !o.mu.TryRLock() {
+			select {
+			case <-ctx.Done():
+				return QueryResult{}, ctx.Err()
+			case <-tick.C:
+			}
+		}
+	}
+	defer o.mu.RUnlock()
 
-	result := QueryResult{
-		Target: target,
+	if err := ctx.Err(); err != nil {
+		return QueryResult{}, err
+	}
+	return o.query(target, DefaultRequirement), nil
+}
+
+// query answers for target under req and records the answer
+// caller must hold o.mu (read is enough)
+func (o *Oracle) query(target types.NodeID, req RequiredConfidence) QueryResult {
+	start := time.Now()
+	result, settled := o.assessCached(target)
+	if !settled {
+		result = applyRequirement(result, req, o.degraded)
+	}
+	o.recordAnswer(target, result.Belief)
+	o.transitions.record(target, result)
+	if m := o.metrics.Load(); m != nil {
+		m.RecordTargetQuery(target, time.Since(start), result.Refused)
+	}
+	return result
+}
+
+// QueryTwoPhase answers with a rough estimate and a high confidence confirmation
+// Both phases share one aggregation, only the requirements differ
+// If estimate is answered but confirmed is refused the answer is
+// directionally clear but not yet certain
+func (o *Oracle) QueryTwoPhase(target types.NodeID, estimateReq, confirmReq RequiredConfidence) (estimate, confirmed QueryResult) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	result, settled := o.assessCached(target)
+	if settled {
+		confirmed = result
+		confirmed.Evidence = append([]string(nil), result.Evidence...)
+		return result, confirmed
+	}
+	return applyRequirement(result, estimateReq, o.degraded), applyRequirement(result, confirmReq, o.degraded)
+}
+
+// assess builds the requirement independent part of a query result
+// settled is true when the result is final regardless of requirements
+// (dead, no reports, or partition refusal)
+// caller must hold o.mu
+func (o *Oracle) assess(target types.NodeID) (result QueryResult, settled bool) {
+	result = QueryResult{
+		Target:        target,
+		Timestamp:     o.clock,
+		AliveInterval: [2]float64{0, 1},
+		DeadInterval:  [2]float64{0, 1},
 	}
 
 	// Check if already dead (finality)
 	if o.finality.IsDead(target) {
+		o.notifyDeath(target)
 		result.Dead = true
 		result.Belief = types.MustBelief(0, 1, 0)
+		result.AliveInterval = [2]float64{0, 0}
+		result.DeadInterval = [2]float64{1, 1}
 		result.Evidence = append(result.Evidence, "finality: node declared dead")
-		return result
+		result.Confidence = GradeHigh
+		return result, true
 	}
 
-	// Get reports for this target
-	reports := o.reports[target]
+	// Get reports for this target, pooled: reports must not outlive assess
+	pooled := o.pooledReportsFor(target)
+	defer pooled.release()
+	reports := pooled.reports
 	result.WitnessCount = len(reports)
 
 	if len(reports) == 0 {
 		// No evidence - unknown belief
 		result.Belief = types.UnknownBelief()
 		result.Evidence = append(result.Evidence, "no witness reports available")
-		return result
+		result.Confidence = GradeUnreliable
+		result.GradeRationale = "no witness reports available"
+		return result, true
 	}
 
-	// Check partition state
-	pState, split := o.partition.Analyze(reports, target)
+	// Check partition state, read-only: ingest records it, see trackPartition
+	pState, split := o.partition.AssessAt(reports, target, o.clock)
 	result.PartitionState = pState
 
 	if pState == partition.ConfirmedPartition {
-		result.Refused = true
-		result.RefusalReason = "network partition detected - witnesses disagree"
 		result.Belief = types.UnknownBelief()
+		if o.degraded {
+			// Best guess despite the split
+			agg := o.aggregator.Aggregate(reports)
+			result.Belief = agg.Belief
+			result.AliveInterval = agg.AliveInterval
+			result.DeadInterval = agg.DeadInterval
+		}
 		if split != nil {
 			result.Disagreement = split.Disagreement
+			result.PartitionedBeliefs = o.groupBeliefs(reports, split)
 		}
-		result.Evidence = append(result.Evidence, "partition: witnesses split into groups")
-		return result
+		return refuse(result,
+			"network partition detected - witnesses disagree",
+			"partition: witnesses split into groups",
+			GradeUnreliable, o.degraded,
+		), true
 	}
 
 	// Aggregate witness reports
 	aggResult := o.aggregator.Aggregate(reports)
 	result.Belief = aggResult.Belief
 	result.Disagreement = aggResult.Disagreement
+	o.trends.record(target, o.clock, aggResult.Disagreement)
+	result.AliveInterval = aggResult.AliveInterval
+	result.DeadInterval = aggResult.DeadInterval
+	if aggResult.Diagnostic != "" {
+		result.Evidence = append(result.Evidence, "aggregation: "+aggResult.Diagnostic)
+	}
+
+	if result.Belief.Dead().Value() > SilenceDeadCap && o.silenceDerived(target) {
+		result.Belief = capDead(result.Belief, SilenceDeadCap)
+		result.DeadInterval[0] = math.Min(result.DeadInterval[0], SilenceDeadCap)
+		result.DeadInterval[1] = math.Min(result.DeadInterval[1], SilenceDeadCap)
+		result.Evidence = append(result.Evidence, "death confidence is silence-derived")
+	}
+
+	return result, false
+}
+
+// partitionTrackBatch sets how often ingest records partition state:
+// a target holding n reports is recorded again after n/partitionTrackBatch
+// new ones, so recording stays O(1) amortized per report
+const partitionTrackBatch = 8
+
+// trackPartition notes a change to the reports or evidence of target and
+// records its partition state once enough changes piled up, driving
+// partition history, the overall state and the cooldown from ingest so
+// queries stay read-only
+// caller must hold o.mu for writing
+func (o *Oracle) trackPartition(target types.NodeID) {
+	o.partitionPending[target]++
+	held := len(o.reports.load(target)) + len(o.evidence[target])
+	if o.partitionPending[target]*partitionTrackBatch < held {
+		return
+	}
+	o.recordPartition(target)
+}
+
+// recordPartition records the partition state of target now
+// caller must hold o.mu for writing
+func (o *Oracle) recordPartition(target types.NodeID) {
+	delete(o.partitionPending, target)
+	pooled := o.pooledReportsFor(target)
+	defer pooled.release()
+	o.partition.AnalyzeAt(pooled.reports, target, o.clock)
+	// the recorded state may start or end a cooldown hold
+	o.invalidate(target)
+}
+
+// flushPartitions records every target with changes not recorded yet
+// caller must hold o.mu for writing
+func (o *Oracle) flushPartitions() {
+	for target := range o.partitionPending {
+		o.recordPartition(target)
+	}
+}
+
+// groupBeliefs aggregates the reports of each partition group separately
+// caller must hold o.mu
+func (o *Oracle) groupBeliefs(reports []witness.WitnessReport, split *partition.SplitReality) []types.Belief {
+	beliefs := make([]types.Belief, len(split.Groups))
+	for i, g := range split.Groups {
+		members := make(map[types.NodeID]bool, len(g.Witnesses))
+		for _, w := range g.Witnesses {
+			members[w] = true
+		}
+		side := make([]witness.WitnessReport, 0, len(g.Witnesses))
+		for _, r := range reports {
+			if members[r.Witness] {
+				side = append(side, r)
+			}
+		}
+		beliefs[i] = o.aggregator.Aggregate(side).Belief
+	}
+	return beliefs
+}
+
+// applyRequirement checks an assessed result against confidence requirements
+// Returns a copy, the input result is not modified
+func applyRequirement(result QueryResult, req RequiredConfidence, degraded bool) QueryResult {
+	result.Evidence = append([]string(nil), result.Evidence...)
+	belief := result.Belief
 
 	// Check if confidence meets requirements
-	if aggResult.Belief.Alive().Value() > 0 && aggResult.Belief.Alive().Value() < req.MinAlive {
-		if aggResult.Belief.Dead().Value() > 0 && aggResult.Belief.Dead().Value() < req.MinDead {
-			result.Refused = true
-			result.RefusalReason = "insufficient confidence to meet requirements"
-			result.Evidence = append(result.Evidence, "confidence below threshold")
-			return result
+	if belief.Alive().Value() > 0 && belief.Alive().Value() < req.MinAlive {
+		if belief.Dead().Value() > 0 && belief.Dead().Value() < req.MinDead {
+			return refuse(result,
+				"insufficient confidence to meet requirements",
+				"confidence below threshold",
+				GradeLow, degraded,
+			)
 		}
 	}
 
-	if aggResult.Belief.Unknown().Value() > req.MaxUnknown {
-		result.Refused = true
-		result.RefusalReason = "uncertainty too high"
-		result.Evidence = append(result.Evidence, "unknown exceeds threshold")
-		return result
+	if belief.Unknown().Value() > req.MaxUnknown {
+		return refuse(result,
+			"uncertainty too high",
+			"unknown exceeds threshold",
+			GradeLow, degraded,
+		)
 	}
 
 	// Build evidence list
 	result.Evidence = append(result.Evidence,
-		"aggregated "+itoa(len(reports))+" witness reports",
+		"aggregated "+itoa(result.WitnessCount)+" witness reports",
 	)
 	if result.Disagreement > 0.1 {
 		result.Evidence = append(result.Evidence, "some witness disagreement detected")
 	}
+	result.Confidence = gradeBelief(belief, result.Disagreement)
 
 	return result
 }
@@ -0,0 +1,930 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/styx-oracle/styx/evidence"
+	"github.com/styx-oracle/styx/finality"
+	"github.com/styx-oracle/styx/metrics"
+	"github.com/styx-oracle/styx/observer"
+	"github.com/styx-oracle/styx/partition"
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+// loosened confirmation so a clear consensus can pass both phases
+var relaxedConfirm = RequiredConfidence{
+	MinAlive:   0.5,
+	MinDead:    0.5,
+	MaxUnknown: 0.5,
+}
+
+func reportAll(o *Oracle, target types.NodeID, from, to uint64, b types.Belief) {
+	for i := from; i <= to; i++ {
+		o.ReceiveReport(types.NewNodeID(i), target, b)
+	}
+}
+
+func TestReceiveReportTimestampsIncrease(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	target := types.NewNodeID(100)
+
+	first := o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.8, 0.1, 0.1))
+	second := o.ReceiveWitnessReport(witness.WitnessReport{
+		Witness: types.NewNodeID(11),
+		Target:  types.NewNodeID(101),
+		Belief:  types.MustBelief(0.8, 0.1, 0.1),
+	})
+	third := o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.7, 0.2, 0.1))
+
+	if !(first < second && second < third) {
+		t.Errorf("timestamps not increasing: %d, %d, %d", first, second, third)
+	}
+}
+
+func TestUUIDTargetSharesWireIdentity(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	target, err := types.ParseNodeIDFromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reportAll(o, target, 10, 14, types.MustBelief(0.05, 0.9, 0.05))
+
+	// HTTP and gossip only carry base and generation
+	wire := types.WithGeneration(target.Base, target.Generation)
+	byUUID, byWire := o.Query(target), o.Query(wire)
+	if byWire.WitnessCount != 5 || !byWire.Belief.Equal(byUUID.Belief) {
+		t.Errorf("uuid target %v with %d reports, wire target %v with %d",
+			byUUID.Belief, byUUID.WitnessCount, byWire.Belief, byWire.WitnessCount)
+	}
+}
+
+func TestQueryTwoPhase(t *testing.T) {
+	target := types.NewNodeID(99)
+
+	t.Run("both phases answer", func(t *testing.T) {
+		orc := New(types.NewNodeID(1))
+		reportAll(orc, target, 1, 5, types.MustBelief(0.9, 0.05, 0.05))
+
+		estimate, confirmed := orc.QueryTwoPhase(target, DefaultRequirement, relaxedConfirm)
+		if estimate.Refused || confirmed.Refused {
+			t.Fatalf("expected both phases to answer: estimate=%q confirmed=%q",
+				estimate.RefusalReason, confirmed.RefusalReason)
+		}
+		if !estimate.Belief.Equal(confirmed.Belief) {
+			t.Errorf("phases disagree on belief: %s vs %s", estimate.Belief, confirmed.Belief)
+		}
+	})
+
+	t.Run("estimate answers confirm refuses", func(t *testing.T) {
+		orc := New(types.NewNodeID(1))
+		reportAll(orc, target, 1, 5, types.MustBelief(0.75, 0.1, 0.15))
+
+		estimate, confirmed := orc.QueryTwoPhase(target, DefaultRequirement, StrictRequirement)
+		if estimate.Refused {
+			t.Fatalf("estimate refused: %s", estimate.RefusalReason)
+		}
+		if !confirmed.Refused {
+			t.Fatalf("expected confirmation to refuse, got %s", confirmed.Belief)
+		}
+		if estimate.Belief.Dominant() != types.StateAlive {
+			t.Errorf("estimate should lean alive: %s", estimate.Belief)
+		}
+	})
+
+	t.Run("both refuse", func(t *testing.T) {
+		orc := New(types.NewNodeID(1))
+		reportAll(orc, target, 1, 5, types.MustBelief(0.9, 0.05, 0.05))
+		reportAll(orc, target, 6, 10, types.MustBelief(0.05, 0.9, 0.05))
+
+		estimate, confirmed := orc.QueryTwoPhase(target, DefaultRequirement, StrictRequirement)
+		if !estimate.Refused || !confirmed.Refused {
+			t.Errorf("expected both phases to refuse during partition: estimate=%v confirmed=%v",
+				estimate.Refused, confirmed.Refused)
+		}
+	})
+}
+
+func BenchmarkTwoPhaseQuery(b *testing.B) {
+	orc := New(types.NewNodeID(1))
+	target := types.NewNodeID(99)
+	reportAll(orc, target, 1, 100, types.MustBelief(0.8, 0.1, 0.1))
+
+	b.Run("two-phase", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			orc.QueryTwoPhase(target, DefaultRequirement, StrictRequirement)
+		}
+	})
+
+	b.Run("separate", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			orc.QueryWithRequirement(target, DefaultRequirement)
+			orc.QueryWithRequirement(target, StrictRequirement)
+		}
+	})
+}
+
+func TestDegradedModeGradesInsteadOfRefusing(t *testing.T) {
+	target := types.NewNodeID(99)
+
+	t.Run("partition", func(t *testing.T) {
+		split := func(o *Oracle) {
+			reportAll(o, target, 1, 5, types.MustBelief(0.9, 0.05, 0.05))
+			reportAll(o, target, 6, 11, types.MustBelief(0.05, 0.9, 0.05))
+		}
+
+		strict := New(types.NewNodeID(1))
+		split(strict)
+		if r := strict.Query(target); !r.Refused {
+			t.Fatalf("strict mode should refuse during partition")
+		}
+
+		degraded := New(types.NewNodeID(1)).WithDegradedMode(true)
+		split(degraded)
+		r := degraded.Query(target)
+		if r.Refused {
+			t.Fatalf("degraded mode refused: %s", r.RefusalReason)
+		}
+		if r.Confidence != GradeUnreliable {
+			t.Errorf("expected UNRELIABLE grade, got %s", r.Confidence)
+		}
+		if r.GradeRationale != "network partition detected - witnesses disagree" {
+			t.Errorf("unexpected rationale %q", r.GradeRationale)
+		}
+		if r.Belief.Unknown().IsOne() {
+			t.Errorf("expected a best-guess belief, got pure unknown")
+		}
+	})
+
+	t.Run("low confidence", func(t *testing.T) {
+		weak := types.MustBelief(0.4, 0.1, 0.5)
+
+		strict := New(types.NewNodeID(1))
+		reportAll(strict, target, 1, 3, weak)
+		if r := strict.QueryWithRequirement(target, StrictRequirement); !r.Refused {
+			t.Fatalf("strict mode should refuse low confidence")
+		}
+
+		degraded := New(types.NewNodeID(1)).WithDegradedMode(true)
+		reportAll(degraded, target, 1, 3, weak)
+		r := degraded.QueryWithRequirement(target, StrictRequirement)
+		if r.Refused {
+			t.Fatalf("degraded mode refused: %s", r.RefusalReason)
+		}
+		if r.Confidence != GradeLow || r.GradeRationale == "" {
+			t.Errorf("expected LOW grade with rationale, got %s (%q)", r.Confidence, r.GradeRationale)
+		}
+	})
+
+	t.Run("confident answer", func(t *testing.T) {
+		orc := New(types.NewNodeID(1)).WithDegradedMode(true)
+		reportAll(orc, target, 1, 5, types.MustBelief(0.9, 0.05, 0.05))
+		if r := orc.Query(target); r.Confidence < GradeMedium {
+			t.Errorf("expected at least MEDIUM grade, got %s", r.Confidence)
+		}
+	})
+}
+
+func TestHarnessDeterministic(t *testing.T) {
+	target := types.NewNodeID(99)
+	scenario := func(h *Harness) {
+		for i := 0; i < 10; i++ {
+			alive := 0.5 + h.Rand().Float64()*0.4
+			h.ReceiveReport(types.NewNodeID(uint64(i+1)), target, types.MustBelief(alive, 0.05, 0.95-alive))
+		}
+	}
+
+	a := NewHarness(types.NewNodeID(1), 7).Run(scenario)
+	b := a.Replay(7)
+	c := a.Replay(8)
+
+	if !a.Query(target).Belief.Equal(b.Query(target).Belief) {
+		t.Error("same seed produced different beliefs")
+	}
+	if a.Query(target).Belief.Equal(c.Query(target).Belief) {
+		t.Error("different seed produced identical beliefs")
+	}
+
+	calls := a.Calls()
+	if len(calls) != 10 {
+		t.Fatalf("expected 10 recorded calls, got %d", len(calls))
+	}
+	for i := 1; i < len(calls); i++ {
+		if !calls[i].Timestamp.IsAfter(calls[i-1].Timestamp) {
+			t.Errorf("calls not in order at %d", i)
+		}
+	}
+}
+
+func TestHarnessClockAndProbe(t *testing.T) {
+	h := NewHarness(types.NewNodeID(1), 1)
+	target := types.NewNodeID(99)
+
+	if _, err := h.Probe(types.NewNodeID(2), target); err != ErrNoProbeFunc {
+		t.Errorf("expected ErrNoProbeFunc, got %v", err)
+	}
+
+	if now := h.AdvanceClock(100); now != 100 {
+		t.Errorf("expected clock at 100, got %s", now)
+	}
+
+	h.SetProbeFunc(func(id types.NodeID) observer.ProbeResult {
+		return observer.ProbeResult{Target: id, Success: true}
+	})
+	result, err := h.Probe(types.NewNodeID(2), target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Timestamp != 101 {
+		t.Errorf("probe report should be stamped after the advanced clock, got %s", result.Timestamp)
+	}
+	if q := h.Query(target); q.Belief.Dominant() != types.StateAlive {
+		t.Errorf("successful probe should lean alive, got %s", q.Belief)
+	}
+}
+
+func TestQueryGroup(t *testing.T) {
+	up, down, quiet := types.NewNodeID(10), types.NewNodeID(11), types.NewNodeID(12)
+	orc := New(types.NewNodeID(1))
+	reportAll(orc, up, 1, 3, types.MustBelief(0.9, 0.05, 0.05))
+	reportAll(orc, down, 1, 3, types.MustBelief(0.05, 0.9, 0.05))
+	// quiet has no reports, pure unknown
+
+	mixed := []types.NodeID{up, down, quiet}
+
+	any := orc.QueryGroup(mixed, AnyAlive)
+	if any.Belief.Dominant() != types.StateAlive {
+		t.Errorf("AnyAlive with one confident member should lean alive: %s", any.Belief)
+	}
+
+	all := orc.QueryGroup(mixed, AllAlive)
+	if all.Belief.Dominant() != types.StateDead {
+		t.Errorf("AllAlive with a dead member should lean dead: %s", all.Belief)
+	}
+
+	majority := orc.QueryGroup(mixed, MajorityAlive)
+	if majority.Belief.Dominant() != types.StateUnknown {
+		t.Errorf("MajorityAlive with one up, one down, one unknown should be unknown: %s", majority.Belief)
+	}
+
+	// Varied reports avoid the P11 correlation penalty on each member
+	up2, up3 := types.NewNodeID(13), types.NewNodeID(14)
+	for _, id := range []types.NodeID{up2, up3} {
+		orc.ReceiveReport(types.NewNodeID(1), id, types.MustBelief(0.97, 0.01, 0.02))
+		orc.ReceiveReport(types.NewNodeID(2), id, types.MustBelief(0.85, 0.1, 0.05))
+	}
+	majority = orc.QueryGroup([]types.NodeID{up2, up3, down}, MajorityAlive)
+	if majority.Belief.Dominant() != types.StateAlive {
+		t.Errorf("MajorityAlive with two of three up should lean alive: %s", majority.Belief)
+	}
+
+	for _, r := range []QueryResult{any, all, majority} {
+		if !r.Belief.IsValid() {
+			t.Errorf("invalid group belief %s", r.Belief)
+		}
+		if r.Belief.Unknown().Value() < types.UnknownFloor()-types.BeliefSumEpsilon {
+			t.Errorf("group belief below unknown floor: %s", r.Belief)
+		}
+	}
+}
+
+func TestReceiveRelayedReportDropsLoops(t *testing.T) {
+	self := types.NewNodeID(1)
+	o := New(self)
+	target := types.NewNodeID(100)
+
+	looped := witness.WitnessReport{
+		Witness:   types.NewNodeID(10),
+		Target:    target,
+		Belief:    types.MustBelief(0.8, 0.1, 0.1),
+		RelayPath: []types.NodeID{types.NewNodeID(20), self, types.NewNodeID(30)},
+	}
+	if err := o.ReceiveRelayedReport(looped); !errors.Is(err, ErrRelayLoop) {
+		t.Fatalf("expected ErrRelayLoop, got %v", err)
+	}
+	if n := o.Query(target).WitnessCount; n != 0 {
+		t.Errorf("looped report was recorded, witness count %d", n)
+	}
+}
+
+func TestReceiveRelayedReportCollapsesSameRoot(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	target := types.NewNodeID(100)
+	belief := types.MustBelief(0.8, 0.1, 0.1)
+	root := types.NewNodeID(10)
+
+	paths := [][]types.NodeID{
+		{types.NewNodeID(20)},
+		{types.NewNodeID(30)},
+		{types.NewNodeID(30), types.NewNodeID(40)},
+	}
+	accepted := 0
+	for _, path := range paths {
+		err := o.ReceiveRelayedReport(witness.WitnessReport{
+			Witness: root, Target: target, Belief: belief, RelayPath: path,
+		})
+		switch {
+		case err == nil:
+			accepted++
+		case !errors.Is(err, ErrDuplicateRelay):
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if accepted != 1 {
+		t.Errorf("accepted %d relays of one observation, want 1", accepted)
+	}
+	if n := o.Query(target).WitnessCount; n != 1 {
+		t.Errorf("witness count %d, want 1", n)
+	}
+}
+
+func TestReceiveRelayedReportKeepsRepeatedObservations(t *testing.T) {
+	target := types.NewNodeID(100)
+	belief := types.MustBelief(0.8, 0.1, 0.1)
+	steady := func(origin uint64, hop uint64) witness.WitnessReport {
+		return witness.WitnessReport{
+			Witness: types.NewNodeID(10), Target: target, Belief: belief,
+			Timestamp: styxtime.LogicalTimestamp(origin),
+			RelayPath: []types.NodeID{types.NewNodeID(hop)},
+		}
+	}
+
+	for _, basis := range []evidence.EvidenceKind{evidence.KindWitnessReport, evidence.KindDirectResponse} {
+		o := New(types.NewNodeID(1))
+		// the witness repeats its belief, each report is a new observation
+		for _, origin := range []uint64{5, 9} {
+			if err := o.ReceiveRelayedReportWithBasis(steady(origin, 20), basis); err != nil {
+				t.Fatalf("%s: observation %d rejected: %v", basis, origin, err)
+			}
+		}
+		// a copy of the second over another path is not
+		if err := o.ReceiveRelayedReportWithBasis(steady(9, 30), basis); !errors.Is(err, ErrDuplicateRelay) {
+			t.Errorf("%s: copy of observation 9: got %v, want ErrDuplicateRelay", basis, err)
+		}
+	}
+}
+
+func TestDeclareDeathWithoutCluster(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	target := types.NewNodeID(100)
+	o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.0, 0.95, 0.05))
+	o.ReceiveReport(types.NewNodeID(11), target, types.MustBelief(0.04, 0.9, 0.06))
+	o.ReceiveReport(types.NewNodeID(12), target, types.MustBelief(0.0, 0.9, 0.1))
+
+	// P15: silence alone never kills
+	if err := o.DeclareDeath(target, false); !errors.Is(err, finality.ErrSilenceOnly) {
+		t.Fatalf("expected ErrSilenceOnly, got %v", err)
+	}
+	if err := o.DeclareDeath(target, true); err != nil {
+		t.Fatalf("DeclareDeath: %v", err)
+	}
+	if !o.Query(target).Dead {
+		t.Error("target not dead")
+	}
+}
+
+func TestReportTTLExpiresStaleOpinions(t *testing.T) {
+	run := func(ttl uint64) QueryResult {
+		o := New(types.NewNodeID(1)).WithReportTTL(ttl)
+		target := types.NewNodeID(100)
+
+		o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.05, 0.9, 0.05))
+		alive := []types.Belief{
+			types.MustBelief(0.8, 0.1, 0.1),
+			types.MustBelief(0.7, 0.1, 0.2),
+			types.MustBelief(0.9, 0.05, 0.05),
+		}
+		for i := 0; i < 6; i++ {
+			o.ReceiveReport(types.NewNodeID(uint64(11+i)), target, alive[i%len(alive)])
+		}
+		return o.Query(target)
+	}
+
+	kept, expired := run(0), run(5)
+
+	if expired.WitnessCount != kept.WitnessCount-1 {
+		t.Errorf("stale report still voting: %d vs %d witnesses", expired.WitnessCount, kept.WitnessCount)
+	}
+	if expired.Belief.Dead().Value() >= kept.Belief.Dead().Value() {
+		t.Errorf("expired dead report still pulls toward dead: %v vs %v", expired.Belief, kept.Belief)
+	}
+}
+
+func TestDisagreementTrendRises(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	target := types.NewNodeID(100)
+
+	o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.8, 0.1, 0.1))
+	// each new witness leans further toward dead
+	diverging := []types.Belief{
+		types.MustBelief(0.75, 0.15, 0.1),
+		types.MustBelief(0.6, 0.3, 0.1),
+		types.MustBelief(0.5, 0.4, 0.1),
+		types.MustBelief(0.35, 0.55, 0.1),
+	}
+	for i, b := range diverging {
+		o.ReceiveReport(types.NewNodeID(uint64(11+i)), target, b)
+		o.Query(target)
+		o.Query(target) // unchanged reports add no sample
+	}
+
+	trend := o.DisagreementTrend(target)
+	if len(trend) != len(diverging) {
+		t.Fatalf("got %d samples, want %d: %v", len(trend), len(diverging), trend)
+	}
+	if s := o.DisagreementSlope(target); s <= 0 {
+		t.Errorf("disagreement slope %f, want positive (trend %v)", s, trend)
+	}
+}
+
+func TestQueryRecordsBeliefFlips(t *testing.T) {
+	m := &metrics.Metrics{}
+	o := New(types.NewNodeID(1)).WithMetrics(m)
+	target := types.NewNodeID(100)
+
+	o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.8, 0.1, 0.1))
+	o.Query(target)
+	o.Query(target)
+	if n := m.DominantFlips(target); n != 0 {
+		t.Fatalf("stable answers counted as flips: %d", n)
+	}
+
+	// witness changes its mind
+	o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.0, 0.95, 0.05))
+	o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.0, 0.9, 0.1))
+	o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.05, 0.9, 0.05))
+	if q := o.Query(target); q.Belief.Dominant() != types.StateDead {
+		t.Fatalf("fixture should turn dead, got %v", q.Belief)
+	}
+	if n := m.DominantFlips(target); n != 1 {
+		t.Errorf("flips = %d, want 1", n)
+	}
+}
+
+func TestOraclesKeepTheirOwnMetrics(t *testing.T) {
+	a, b := New(types.NewNodeID(1)), New(types.NewNodeID(1))
+	target := types.NewNodeID(100)
+	a.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.8, 0.1, 0.1))
+	a.Query(target)
+
+	if a.Metrics() == b.Metrics() || a.Metrics() == metrics.Default {
+		t.Fatal("Oracles share a metrics instance")
+	}
+	if a.Metrics().QueriesTotal != 1 || b.Metrics().QueriesTotal != 0 {
+		t.Errorf("queries a %d b %d, want 1 and 0", a.Metrics().QueriesTotal, b.Metrics().QueriesTotal)
+	}
+}
+
+func TestQueryMetricsPerTarget(t *testing.T) {
+	m := &metrics.Metrics{}
+	o := New(types.NewNodeID(1)).WithMetrics(m)
+	split, busy := types.NewNodeID(100), types.NewNodeID(101)
+
+	// split: few witnesses, confirmed partition, always refused
+	reportAll(o, split, 10, 11, types.MustBelief(0.9, 0.05, 0.05))
+	reportAll(o, split, 12, 13, types.MustBelief(0.05, 0.9, 0.05))
+	// busy: many agreeing witnesses, answered but costly to aggregate
+	for i := uint64(0); i < 80; i++ {
+		alive := 0.8 + float64(i%10)/100
+		o.ReceiveReport(types.NewNodeID(100+i), busy, types.MustBelief(alive, 0.05, 0.95-alive))
+	}
+
+	for range 20 {
+		o.Query(split)
+		o.Query(busy)
+	}
+
+	s, b := m.TargetQueryStats(split), m.TargetQueryStats(busy)
+	if s.Queries != 20 || b.Queries != 20 {
+		t.Fatalf("queries = %d, %d; want 20 each", s.Queries, b.Queries)
+	}
+	if s.RefusalRate != 1 || b.RefusalRate != 0 {
+		t.Errorf("refusal rates = %f, %f; want 1, 0", s.RefusalRate, b.RefusalRate)
+	}
+	if b.P50 <= s.P50 {
+		t.Errorf("busy target p50 %v not above refused target p50 %v", b.P50, s.P50)
+	}
+	if s.P50 > s.P99 || b.P50 > b.P90 || b.P90 > b.P99 {
+		t.Errorf("quantiles out of order: %+v %+v", s, b)
+	}
+	if m.QueriesTotal != 40 || m.RefusalsTotal != 20 {
+		t.Errorf("totals = %d queries, %d refusals", m.QueriesTotal, m.RefusalsTotal)
+	}
+}
+
+func TestConcurrentQueryAndReceiveReport(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	target := types.NewNodeID(100)
+	beliefs := []types.Belief{
+		types.MustBelief(0.9, 0.05, 0.05),
+		types.MustBelief(0.05, 0.9, 0.05),
+		types.MustBelief(0.2, 0.2, 0.6),
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				o.ReceiveReport(types.NewNodeID(uint64(10+w)), target, beliefs[(w+i)%len(beliefs)])
+			}
+		}(w)
+	}
+	for q := 0; q < 8; q++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if r := o.Query(target); !r.Belief.IsValid() {
+					t.Errorf("invalid belief under contention: %v", r.Belief)
+					return
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("concurrent Query and ReceiveReport deadlocked")
+	}
+}
+
+// holdingLock runs fn with the Oracle locked, simulating an Oracle stuck on
+// slow work: queries block until fn returns
+func holdingLock(o *Oracle, fn observer.ProbeFunc) observer.ProbeFunc {
+	return func(id types.NodeID) observer.ProbeResult {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		return fn(id)
+	}
+}
+
+func TestQueryCtxCancelledQueryRecordsNothing(t *testing.T) {
+	h := NewHarness(types.NewNodeID(1), 1)
+	target := types.NewNodeID(100)
+	started := make(chan struct{})
+	h.SetProbeFunc(holdingLock(h.Oracle(), func(id types.NodeID) observer.ProbeResult {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		return observer.ProbeResult{Target: id, Success: true}
+	}))
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		h.Probe(types.NewNodeID(10), target)
+	}()
+	<-started
+	goroutines := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := h.Oracle().QueryCtx(ctx, target); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := h.Oracle().QueryCtx(ctx, target); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	// the abandoned query left nothing waiting on the lock
+	if n := runtime.NumGoroutine(); n > goroutines {
+		t.Errorf("%d goroutines left behind by cancelled queries", n-goroutines)
+	}
+	<-finished
+
+	// the abandoned query must not answer after the stall
+	time.Sleep(10 * time.Millisecond)
+	h.Oracle().answers.mu.Lock()
+	_, answered := h.Oracle().answers.beliefs[target]
+	h.Oracle().answers.mu.Unlock()
+	if answered {
+		t.Error("cancelled query still recorded an answer")
+	}
+
+	if r, err := h.Oracle().QueryCtx(context.Background(), target); err != nil || r.WitnessCount != 1 {
+		t.Errorf("QueryCtx after stall: %+v, %v", r, err)
+	}
+}
+
+func TestConfirmedPartitionReturnsBothSides(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	target := types.NewNodeID(100)
+
+	for i := uint64(0); i < 3; i++ {
+		jitter := float64(i) * 0.02
+		o.ReceiveReport(types.NewNodeID(10+i), target, types.MustBelief(0.85-jitter, 0.05+jitter, 0.1))
+		o.ReceiveReport(types.NewNodeID(20+i), target, types.MustBelief(0.05+jitter, 0.85-jitter, 0.1))
+	}
+
+	result := o.Query(target)
+	if result.PartitionState != partition.ConfirmedPartition || !result.Refused {
+		t.Fatalf("expected refused confirmed partition, got %s refused=%v", result.PartitionState, result.Refused)
+	}
+	if len(result.PartitionedBeliefs) != 2 {
+		t.Fatalf("expected 2 group beliefs, got %v", result.PartitionedBeliefs)
+	}
+	alive, dead := result.PartitionedBeliefs[0], result.PartitionedBeliefs[1]
+	if alive.Dominant() != types.StateAlive || dead.Dominant() != types.StateDead {
+		t.Errorf("expected an alive and a dead side, got %v and %v", alive, dead)
+	}
+}
+
+func TestPartitionStateRecordedOnIngestNotQuery(t *testing.T) {
+	o := New(types.NewNodeID(1)).WithPartitionCooldown(30)
+	target, quiet := types.NewNodeID(100), types.NewNodeID(200)
+
+	// nobody queries while the split is reported
+	for i := uint64(0); i < 3; i++ {
+		jitter := float64(i) * 0.02
+		o.ReceiveReport(types.NewNodeID(10+i), target, types.MustBelief(0.85-jitter, 0.05+jitter, 0.1))
+		o.ReceiveReport(types.NewNodeID(20+i), target, types.MustBelief(0.05+jitter, 0.85-jitter, 0.1))
+	}
+	if got := o.PartitionState(); got != partition.ConfirmedPartition {
+		t.Fatalf("ingest did not record the split: %s", got)
+	}
+	history := o.PartitionHistory(0)
+
+	for range 20 {
+		o.Query(target)
+		o.Query(quiet)
+	}
+	if got := o.PartitionHistory(0); len(got) != len(history) {
+		t.Errorf("queries recorded partition transitions: %v, was %v", got, history)
+	}
+
+	// the cooldown started at ingest, agreeable reports do not end it early
+	for i := uint64(0); i < 10; i++ {
+		jitter := float64(i%3) * 0.02
+		o.ReceiveReport(types.NewNodeID(30+i), target, types.MustBelief(0.85-jitter, 0.05+jitter, 0.1))
+	}
+	if got := o.Query(target); got.PartitionState != partition.ConfirmedPartition || !got.Refused {
+		t.Errorf("cooldown should hold the partition, got %s refused=%v", got.PartitionState, got.Refused)
+	}
+}
+
+func TestRegisterWitnessesKeepsExistingTrust(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	dup := types.NewNodeID(11)
+	o.RegisterWitness(dup)
+	o.registry.RecordWrong(dup)
+	lowered := o.registry.GetTrust(dup)
+
+	o.RegisterWitnesses([]types.NodeID{types.NewNodeID(10), dup, types.NewNodeID(12), dup})
+
+	records := o.Witnesses()
+	if len(records) != 3 {
+		t.Fatalf("registered %d witnesses, want 3", len(records))
+	}
+	for _, rec := range records {
+		want := witness.DefaultTrust
+		if rec.ID == dup {
+			want = lowered
+		}
+		if rec.Trust != want {
+			t.Errorf("%s trust = %v, want %v", rec.ID, rec.Trust, want)
+		}
+	}
+}
+
+func TestConcurrentReportsVisibleToLaterQuery(t *testing.T) {
+	o := New(types.NewNodeID(1)).WithMaxReportsPerTarget(40)
+	target := types.NewNodeID(100)
+
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 10 {
+				o.ReceiveReport(types.NewNodeID(uint64(10+w*10+i)), target, types.MustBelief(0.8, 0.1, 0.1))
+				o.Query(target)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// every report happened-before this query, the store keeps the newest 40
+	if got := o.Query(target).WitnessCount; got != 40 {
+		t.Errorf("witness count %d, want 40", got)
+	}
+	if o.ReportCount(target) != 40 {
+		t.Errorf("report count %d, want 40", o.ReportCount(target))
+	}
+}
+
+func TestMaxReportsChattyWitnessKeepsOthers(t *testing.T) {
+	o := New(types.NewNodeID(1)).WithMaxReportsPerTarget(10)
+	target := types.NewNodeID(100)
+	reportAll(o, target, 10, 14, types.MustBelief(0.8, 0.1, 0.1))
+
+	chatty := types.NewNodeID(99)
+	for range 50 {
+		o.ReceiveReport(chatty, target, types.MustBelief(0.1, 0.8, 0.1))
+	}
+
+	perWitness := make(map[types.NodeID]int)
+	for _, r := range o.reports.load(target) {
+		perWitness[r.Witness]++
+	}
+	for i := uint64(10); i <= 14; i++ {
+		if perWitness[types.NewNodeID(i)] != 1 {
+			t.Errorf("witness %d has %d reports left, want 1", i, perWitness[types.NewNodeID(i)])
+		}
+	}
+	if perWitness[chatty] != 5 {
+		t.Errorf("chatty witness holds %d reports, want the 5 left over", perWitness[chatty])
+	}
+}
+
+func TestReportSnapshotsStableAcrossAppends(t *testing.T) {
+	o := New(types.NewNodeID(1)).WithMaxReportsPerTarget(8)
+	target := types.NewNodeID(100)
+	reportAll(o, target, 10, 15, types.MustBelief(0.8, 0.1, 0.1))
+
+	snapshot := o.reports.load(target)
+	want := append([]witness.WitnessReport(nil), snapshot...)
+
+	// appends land past the snapshot or in a new array, never inside it
+	_ = append(snapshot, witness.WitnessReport{Witness: types.NewNodeID(99)})
+	reportAll(o, target, 20, 40, types.MustBelief(0.7, 0.2, 0.1))
+	for i := range want {
+		if snapshot[i].Witness != want[i].Witness || snapshot[i].Timestamp != want[i].Timestamp {
+			t.Fatalf("snapshot[%d] changed to %v, was %v", i, snapshot[i], want[i])
+		}
+	}
+
+	reports := o.reports.load(target)
+	if len(reports) != 8 {
+		t.Fatalf("%d reports stored, want 8", len(reports))
+	}
+	for i := 1; i < len(reports); i++ {
+		if reports[i].Timestamp <= reports[i-1].Timestamp {
+			t.Errorf("reports out of stamp order at %d: %d after %d", i, reports[i].Timestamp, reports[i-1].Timestamp)
+		}
+	}
+}
+
+func TestPooledReportsNotSharedAcrossQueries(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	target := types.NewNodeID(100)
+	reportAll(o, target, 10, 14, types.MustBelief(0.8, 0.1, 0.1))
+	for i := range 3 {
+		w := types.NewNodeID(uint64(50 + i))
+		if err := o.ReceiveEvidence(w, target, evidence.NewDirectResponse(1, 20, w, target)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := o.Query(target).WitnessCount
+
+	// a slice reused while another query still reads it trips -race
+	// or shows up as a wrong witness count
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				if got := o.Query(target).WitnessCount; got != want {
+					t.Errorf("witness count %d, want %d", got, want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 50 {
+			o.Query(types.NewNodeID(uint64(200 + i%4)))
+			o.ReceiveReport(types.NewNodeID(30), types.NewNodeID(uint64(200+i%4)), types.MustBelief(0.7, 0.2, 0.1))
+		}
+	}()
+	wg.Wait()
+}
+
+func TestClockFollowsCarriedTimestamps(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	target := types.NewNodeID(100)
+	alive := types.MustBelief(0.8, 0.1, 0.1)
+
+	var last styxtime.LogicalTimestamp
+	for i := range 5 {
+		ts := o.ReceiveReport(types.NewNodeID(uint64(10+i)), target, alive)
+		if ts <= last || o.Clock() != ts {
+			t.Fatalf("report %d stamped %d after %d, clock %d", i, ts, last, o.Clock())
+		}
+		last = ts
+	}
+
+	// a witness ahead of us pulls the clock forward, max(clock, carried) + 1,
+	// by at most MaxClockJump per report
+	ahead := types.NewNodeID(20)
+	o.RegisterWitness(ahead)
+	if ts := o.ReceiveWitnessReport(witness.WitnessReport{Witness: ahead, Target: target, Belief: alive, Timestamp: last + 3}); ts != last+4 {
+		t.Errorf("carried %d: stamped %d, want %d", last+3, ts, last+4)
+	}
+	before := o.Clock()
+	if ts := o.ReceiveWitnessReport(witness.WitnessReport{Witness: ahead, Target: target, Belief: alive, Timestamp: math.MaxUint64}); ts != before.Add(MaxClockJump)+1 {
+		t.Errorf("carried max: stamped %d, want %d", ts, before.Add(MaxClockJump)+1)
+	}
+	// one behind us never moves it back
+	if ts := o.ReceiveWitnessReport(witness.WitnessReport{Witness: types.NewNodeID(21), Target: target, Belief: alive, Timestamp: 3}); ts != o.Clock() || ts <= before.Add(MaxClockJump)+1 {
+		t.Errorf("carried 3: stamped %d, clock %d", ts, o.Clock())
+	}
+	// nor can a witness nobody has heard of move it at all
+	before = o.Clock()
+	if ts := o.ReceiveWitnessReport(witness.WitnessReport{Witness: types.NewNodeID(22), Target: target, Belief: alive, Timestamp: 1 << 40}); ts != before+1 {
+		t.Errorf("unknown witness carrying 1<<40: stamped %d, want %d", ts, before+1)
+	}
+
+	if q := o.Query(target); q.Timestamp != o.Clock() || q.WitnessCount != 9 {
+		t.Errorf("answer at %d with %d witnesses, clock %d", q.Timestamp, q.WitnessCount, o.Clock())
+	}
+}
+
+func TestTransitionsRecordEachHop(t *testing.T) {
+	// reports expire after 3 units so a new batch replaces the last
+	o := New(types.NewNodeID(1)).WithReportTTL(3)
+	target := types.NewNodeID(100)
+
+	if got := o.Query(target).Belief.Dominant(); got != types.StateUnknown {
+		t.Fatalf("no reports answered %s", got)
+	}
+	if log := o.Transitions(target); len(log) != 0 {
+		t.Fatalf("unknown to unknown logged %+v", log)
+	}
+
+	o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.9, 0.05, 0.05))
+	o.ReceiveReport(types.NewNodeID(11), target, types.MustBelief(0.85, 0.05, 0.1))
+	o.ReceiveReport(types.NewNodeID(12), target, types.MustBelief(0.8, 0.1, 0.1))
+	o.Query(target)
+	o.Query(target) // same state, no hop
+
+	o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0, 0.95, 0.05))
+	o.ReceiveReport(types.NewNodeID(11), target, types.MustBelief(0.04, 0.9, 0.06))
+	o.ReceiveReport(types.NewNodeID(12), target, types.MustBelief(0, 0.9, 0.1))
+	o.ReceiveReport(types.NewNodeID(13), target, types.MustBelief(0.02, 0.88, 0.1))
+	if got := o.Query(target).Belief.Dominant(); got != types.StateDead {
+		t.Fatalf("dead reports answered %s", got)
+	}
+
+	log := o.Transitions(target)
+	want := []StateTransition{
+		{From: types.StateUnknown, To: types.StateAlive, At: 3, WitnessCount: 3},
+		{From: types.StateAlive, To: types.StateDead, At: 7, WitnessCount: 4},
+	}
+	if len(log) != len(want) {
+		t.Fatalf("got %d transitions, want %d: %+v", len(log), len(want), log)
+	}
+	for i := range want {
+		if log[i] != want[i] {
+			t.Errorf("transition %d = %+v, want %+v", i, log[i], want[i])
+		}
+	}
+	if other := o.Transitions(types.NewNodeID(200)); len(other) != 0 {
+		t.Errorf("unqueried target has transitions %+v", other)
+	}
+}
+
+func TestTransitionsBoundedTargets(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	o.transitions.maxTargets = 2
+	alive := []types.Belief{types.MustBelief(0.9, 0.05, 0.05), types.MustBelief(0.85, 0.05, 0.1), types.MustBelief(0.8, 0.1, 0.1)}
+	for n := uint64(100); n < 104; n++ {
+		target := types.NewNodeID(n)
+		for i, b := range alive {
+			o.ReceiveReport(types.NewNodeID(10+uint64(i)), target, b)
+		}
+		o.Query(target)
+	}
+
+	if len(o.transitions.targets) != 2 {
+		t.Errorf("%d targets with their own record, want 2", len(o.transitions.targets))
+	}
+	if log := o.Transitions(types.NewNodeID(101)); len(log) != 1 {
+		t.Errorf("target within the bound: %+v", log)
+	}
+	if log := o.Transitions(types.NewNodeID(103)); len(log) != 0 {
+		t.Errorf("target past the bound has its own log %+v", log)
+	}
+	// both targets past the bound answered alive: one shared hop
+	if log := o.OtherTransitions(); len(log) != 1 || log[0].To != types.StateAlive {
+		t.Errorf("other transitions %+v, want one hop to alive", log)
+	}
+}
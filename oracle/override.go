@@ -0,0 +1,42 @@
+package oracle
+
+import (
+	"errors"
+
+	"github.com/styx-oracle/styx/finality"
+	"github.com/styx-oracle/styx/types"
+)
+
+// ErrOverrideDisabled is returned by ForceFinality unless operator
+// overrides were enabled with WithOperatorOverride
+var ErrOverrideDisabled = errors.New("operator override disabled")
+
+// WithOperatorOverride allows operators to declare nodes dead by hand with
+// ForceFinality, off by default
+func (o *Oracle) WithOperatorOverride(allowForceFinality bool) *Oracle {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.allowForce = allowForceFinality
+	return o
+}
+
+// ForceFinality declares target dead on an operator's word, e.g. the
+// hardware is physically gone
+// Skips the witness, confidence and P15 checks and any attached Cluster,
+// the record carries reason, no witnesses and a certainly dead belief
+// P14 still holds: the death is final, forcing a dead node returns
+// finality.ErrAlreadyDead
+func (o *Oracle) ForceFinality(target types.NodeID, reason string) error {
+	o.mu.RLock()
+	allowed := o.allowForce
+	o.mu.RUnlock()
+	if !allowed {
+		return ErrOverrideDisabled
+	}
+
+	return o.finality.Commit(&finality.DeathRecord{
+		NodeID:      target,
+		FinalBelief: types.CertainlyDead(),
+		Reason:      reason,
+	})
+}
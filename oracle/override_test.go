@@ -0,0 +1,41 @@
+package oracle
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/styx-oracle/styx/finality"
+	"github.com/styx-oracle/styx/types"
+)
+
+func TestForceFinality(t *testing.T) {
+	target := types.NewNodeID(100)
+
+	locked := New(types.NewNodeID(1))
+	if err := locked.ForceFinality(target, "rack destroyed"); !errors.Is(err, ErrOverrideDisabled) {
+		t.Fatalf("expected ErrOverrideDisabled, got %v", err)
+	}
+	if locked.Query(target).Dead {
+		t.Fatal("disabled override declared death")
+	}
+
+	o := New(types.NewNodeID(1)).WithOperatorOverride(true)
+	// No witnesses at all, normal finality could never pass
+	if err := o.ForceFinality(target, "rack destroyed"); err != nil {
+		t.Fatalf("ForceFinality: %v", err)
+	}
+
+	rec := o.finality.GetDeathRecord(target)
+	if rec == nil || rec.Reason != "rack destroyed" || rec.Witnesses != nil || !rec.FinalBelief.Equal(types.CertainlyDead()) {
+		t.Errorf("unexpected death record: %+v", rec)
+	}
+
+	// P14: still dead, even with fresh alive reports
+	o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.9, 0.05, 0.05))
+	if !o.Query(target).Dead || !o.finality.IsDead(target) {
+		t.Error("P14 VIOLATED: forced death did not stick")
+	}
+	if err := o.ForceFinality(target, "again"); !errors.Is(err, finality.ErrAlreadyDead) {
+		t.Errorf("expected ErrAlreadyDead, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package oracle
+
+import (
+	"time"
+
+	"github.com/styx-oracle/styx/partition"
+)
+
+// WithPartitionCooldown keeps a confirmed partition refusing for at least
+// units of logical time (reports received) after it was last confirmed
+// stops flapping witnesses toggling answers on and off, 0 disables it
+func (o *Oracle) WithPartitionCooldown(units uint64) *Oracle {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.partition.WithCooldown(units)
+	o.invalidateAll()
+	return o
+}
+
+// WithVariancePartitionDetection also looks for partitions in the variance
+// of the full witness beliefs, catching confident splits the dominant vote
+// reads as unknown, see partition.Detector.AssessVariance
+// threshold is the belief variance that counts, 0 disables it
+func (o *Oracle) WithVariancePartitionDetection(threshold float64) *Oracle {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.partition.WithVarianceDetection(threshold)
+	o.invalidateAll()
+	return o
+}
+
+// PartitionState returns the worst partition state seen across targets
+func (o *Oracle) PartitionState() partition.PartitionState {
+	o.syncPartitions()
+	return o.partition.State()
+}
+
+// PartitionHistory returns the last limit partition state transitions,
+// oldest first, answering "when did the partition start"
+func (o *Oracle) PartitionHistory(limit int) []partition.PartitionTransition {
+	o.syncPartitions()
+	return o.partition.History(limit)
+}
+
+// PartitionDuration returns how long the current partition state has held
+func (o *Oracle) PartitionDuration() time.Duration {
+	o.syncPartitions()
+	return o.partition.PartitionDuration()
+}
+
+// syncPartitions records the partition state of targets changed since
+// their last recording, so the detector is current when read
+func (o *Oracle) syncPartitions() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.flushPartitions()
+}
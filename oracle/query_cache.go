@@ -0,0 +1,203 @@
+package oracle
+
+import (
+	"sync"
+	"time"
+
+	"github.com/styx-oracle/styx/partition"
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+)
+
+// queryCache keeps assessed results so read heavy callers skip
+// re-aggregating, see WithQueryCache and WithBeliefCache
+// every invalidation bumps the target generation, invalidateAll the epoch
+// of every target, an assessment started before either is never stored,
+// so a report racing a query cannot be hidden
+type queryCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration // 0 = entries live until invalidated
+	now     func() time.Time
+	entries map[types.NodeID]cachedAssessment
+	gens    map[types.NodeID]uint64
+	epoch   uint64
+}
+
+// cacheGen is the generation an assessment was started under
+type cacheGen struct {
+	target, epoch uint64
+}
+
+// cachedAssessment is an assessment plus what it was computed from
+type cachedAssessment struct {
+	result  QueryResult
+	settled bool
+	at      time.Time
+	// registry and collusion versions at fill time: trust, eviction and
+	// lockstep reports about other targets move the weights of this one
+	registry, collusion uint64
+	// clock is the Oracle clock at fill time, only checked if clockBound:
+	// evidence decay, report TTL and partition cooldown move with the clock
+	// even without reports for this target
+	clock      styxtime.LogicalTimestamp
+	clockBound bool
+}
+
+func newQueryCache(ttl time.Duration) *queryCache {
+	return &queryCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[types.NodeID]cachedAssessment),
+		gens:    make(map[types.NodeID]uint64),
+	}
+}
+
+// get returns a fresh entry for target and the generation to store under
+func (c *queryCache) get(target types.NodeID) (entry cachedAssessment, ok bool, gen cacheGen) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok = c.entries[target]
+	if ok && c.ttl > 0 && c.now().Sub(entry.at) >= c.ttl {
+		delete(c.entries, target)
+		ok = false
+	}
+	return entry, ok, cacheGen{target: c.gens[target], epoch: c.epoch}
+}
+
+// put stores an assessment made at generation gen, if still current
+func (c *queryCache) put(target types.NodeID, gen cacheGen, entry cachedAssessment) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gens[target] != gen.target || c.epoch != gen.epoch {
+		return
+	}
+	entry.result.Evidence = append([]string(nil), entry.result.Evidence...)
+	entry.at = c.now()
+	c.entries[target] = entry
+}
+
+// invalidate drops target and stops in-flight assessments from storing
+func (c *queryCache) invalidate(target types.NodeID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, target)
+	c.gens[target]++
+}
+
+// invalidateAll drops every target, for changes that move every answer
+func (c *queryCache) invalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+	c.epoch++
+}
+
+// WithQueryCache serves Query results from a cache for up to ttl instead of
+// re-aggregating on every call
+// a new report or evidence for a target invalidates it at once, so do
+// configuration, trust and collusion changes for every target, a dead
+// target is never served from the cache, Refresh forces recomputation
+// 0 disables the TTL cache (the default), WithBeliefCache is separate
+func (o *Oracle) WithQueryCache(ttl time.Duration) *Oracle {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.cacheTTL = max(ttl, 0)
+	o.resetCache()
+	return o
+}
+
+// WithBeliefCache caches Query results until they are invalidated, for
+// dashboards polling targets that rarely change
+// invalidated like WithQueryCache, for targets whose answer moves with the
+// clock (evidence decay, report TTL, partition cooldown) an entry is only
+// served while the clock is unchanged too
+// with a WithQueryCache TTL entries also expire after it, disabling one
+// cache leaves the other as configured
+// disabled by default
+func (o *Oracle) WithBeliefCache(enabled bool) *Oracle {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.beliefCache = enabled
+	o.resetCache()
+	return o
+}
+
+// resetCache rebuilds the cache from the cache options, empty
+// caller must hold o.mu for writing
+func (o *Oracle) resetCache() {
+	if o.cacheTTL == 0 && !o.beliefCache {
+		o.cache = nil
+		return
+	}
+	o.cache = newQueryCache(o.cacheTTL)
+}
+
+// Refresh discards the cached result for target and recomputes it now
+func (o *Oracle) Refresh(target types.NodeID) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if o.cache == nil {
+		return
+	}
+	o.cache.invalidate(target)
+	o.assessCached(target)
+}
+
+// invalidate drops any cached result for target
+// caller must hold o.mu (read is enough)
+func (o *Oracle) invalidate(target types.NodeID) {
+	if o.cache != nil {
+		o.cache.invalidate(target)
+	}
+}
+
+// invalidateAll drops every cached result, for configuration changes
+// caller must hold o.mu (read is enough)
+func (o *Oracle) invalidateAll() {
+	if o.cache != nil {
+		o.cache.invalidateAll()
+	}
+}
+
+// assessCached is assess served from the query cache when enabled
+// dead targets bypass the cache so finality is never served stale, and
+// assess runs for them anyway to fire death callbacks
+// caller must hold o.mu (read is enough)
+func (o *Oracle) assessCached(target types.NodeID) (QueryResult, bool) {
+	if o.cache == nil || o.finality.IsDead(target) {
+		return o.assess(target)
+	}
+
+	entry, hit, gen := o.cache.get(target)
+	registry, collusion := o.registry.Version(), o.collusion.Version()
+	if hit && (entry.registry != registry || entry.collusion != collusion || entry.clockBound && entry.clock != o.clock) {
+		hit = false
+	}
+	if m := o.metrics.Load(); m != nil {
+		m.RecordQueryCache(hit)
+	}
+	if hit {
+		result := entry.result
+		result.Evidence = append([]string(nil), result.Evidence...)
+		// an aggregated answer is a disagreement sample, served or not
+		if !entry.settled {
+			o.trends.record(target, o.clock, result.Disagreement)
+		}
+		return result, entry.settled
+	}
+
+	result, settled := o.assess(target)
+	if !result.Dead {
+		o.cache.put(target, gen, cachedAssessment{
+			result:     result,
+			settled:    settled,
+			registry:   registry,
+			collusion:  collusion,
+			clock:      o.clock,
+			clockBound: len(o.evidence[target]) > 0 || o.reportTTL > 0 || result.PartitionState != partition.NoPartition,
+		})
+	}
+	return result, settled
+}
@@ -0,0 +1,240 @@
+package oracle
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/styx-oracle/styx/metrics"
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+// assertFresh fails if the (possibly cached) answer for target differs
+// from a fresh aggregation, returns the answer
+func assertFresh(t *testing.T, o *Oracle, target types.NodeID, after string) types.Belief {
+	t.Helper()
+	got := o.Query(target)
+	o.mu.RLock()
+	want, _ := o.assess(target)
+	o.mu.RUnlock()
+	if !got.Belief.Equal(want.Belief) {
+		t.Errorf("after %s: served %v, fresh %v", after, got.Belief, want.Belief)
+	}
+	return want.Belief
+}
+
+func TestQueryCache(t *testing.T) {
+	m := &metrics.Metrics{}
+	o := New(types.NewNodeID(1)).WithMetrics(m).WithQueryCache(time.Minute)
+	now := time.Unix(1000, 0)
+	o.cache.now = func() time.Time { return now }
+	target := types.NewNodeID(100)
+	o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.8, 0.1, 0.1))
+
+	first := o.Query(target)
+	second := o.Query(target)
+	if m.QueryCacheHits != 1 || m.QueryCacheMisses != 1 {
+		t.Fatalf("hits %d misses %d, want 1 and 1", m.QueryCacheHits, m.QueryCacheMisses)
+	}
+	if !second.Belief.Equal(first.Belief) || second.WitnessCount != 1 {
+		t.Errorf("cached %+v differs from %+v", second, first)
+	}
+
+	// a new report is visible on the very next query
+	o.ReceiveReport(types.NewNodeID(11), target, types.MustBelief(0.7, 0.2, 0.1))
+	if got := o.Query(target).WitnessCount; got != 2 || m.QueryCacheMisses != 2 {
+		t.Errorf("after report: %d witnesses, %d misses", got, m.QueryCacheMisses)
+	}
+
+	// past the TTL the answer is recomputed
+	now = now.Add(time.Minute)
+	o.Query(target)
+	if m.QueryCacheMisses != 3 {
+		t.Errorf("expired entry served, misses %d", m.QueryCacheMisses)
+	}
+
+	// Refresh recomputes now, the next query is a hit on the fresh result
+	o.Refresh(target)
+	o.Query(target)
+	if m.QueryCacheHits != 2 || m.QueryCacheMisses != 4 {
+		t.Errorf("after Refresh hits %d misses %d, want 2 and 4", m.QueryCacheHits, m.QueryCacheMisses)
+	}
+}
+
+func TestQueryCacheNeverServesStaleFinality(t *testing.T) {
+	o := New(types.NewNodeID(1)).WithQueryCache(time.Hour)
+	target := types.NewNodeID(100)
+	o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.0, 0.95, 0.05))
+	o.ReceiveReport(types.NewNodeID(11), target, types.MustBelief(0.04, 0.9, 0.06))
+	o.ReceiveReport(types.NewNodeID(12), target, types.MustBelief(0.0, 0.9, 0.1))
+
+	if o.Query(target).Dead {
+		t.Fatal("dead before declaration")
+	}
+	if err := o.DeclareDeath(target, true); err != nil {
+		t.Fatalf("DeclareDeath: %v", err)
+	}
+	if q := o.Query(target); !q.Dead {
+		t.Errorf("cached pre-death answer served: %+v", q)
+	}
+}
+
+func TestBeliefCacheHitsUntilNewReport(t *testing.T) {
+	m := &metrics.Metrics{}
+	o := New(types.NewNodeID(1)).WithMetrics(m).WithBeliefCache(true)
+	target := types.NewNodeID(100)
+	reportAll(o, target, 10, 12, types.MustBelief(0.8, 0.1, 0.1))
+
+	// no TTL: a target without new reports stays cached
+	for range 5 {
+		o.Query(target)
+	}
+	if m.QueryCacheHits != 4 || m.QueryCacheMisses != 1 {
+		t.Fatalf("hits %d misses %d, want 4 and 1", m.QueryCacheHits, m.QueryCacheMisses)
+	}
+
+	o.ReceiveReport(types.NewNodeID(13), target, types.MustBelief(0.7, 0.2, 0.1))
+	if got := o.Query(target).WitnessCount; got != 4 || m.QueryCacheMisses != 2 {
+		t.Errorf("after report: %d witnesses, %d misses", got, m.QueryCacheMisses)
+	}
+
+	o.WithBeliefCache(false)
+	o.Query(target)
+	if m.QueryCacheHits+m.QueryCacheMisses != 6 {
+		t.Errorf("disabled cache still consulted")
+	}
+}
+
+func TestBeliefCacheNoDivergence(t *testing.T) {
+	cached := New(types.NewNodeID(1)).WithBeliefCache(true)
+	targets := []types.NodeID{types.NewNodeID(100), types.NewNodeID(101)}
+	beliefs := []types.Belief{types.MustBelief(0.8, 0.1, 0.1), types.MustBelief(0.7, 0.2, 0.1)}
+
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := range 25 {
+				target := targets[i%len(targets)]
+				id := types.NewNodeID(uint64(10 + w*25 + i))
+				cached.ReceiveReport(id, target, beliefs[i%len(beliefs)])
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := range 25 {
+				cached.Query(targets[i%len(targets)])
+			}
+		}()
+	}
+	wg.Wait()
+
+	// the cached answer must match a fresh aggregation
+	for _, target := range targets {
+		got := cached.Query(target)
+		cached.mu.RLock()
+		want, _ := cached.assess(target)
+		cached.mu.RUnlock()
+		if !got.Belief.Equal(want.Belief) || got.WitnessCount != want.WitnessCount {
+			t.Errorf("%s: cached %v from %d, fresh %v from %d", target, got.Belief, got.WitnessCount, want.Belief, want.WitnessCount)
+		}
+	}
+}
+
+func TestBeliefCacheInvalidatedByGlobalChanges(t *testing.T) {
+	o := New(types.NewNodeID(1)).WithBeliefCache(true)
+	target := types.NewNodeID(100)
+	a, b, c := types.NewNodeID(10), types.NewNodeID(11), types.NewNodeID(12)
+	o.ReceiveReport(a, target, types.MustBelief(0.9, 0.05, 0.05))
+	o.ReceiveReport(b, target, types.MustBelief(0.8, 0.1, 0.1))
+	o.ReceiveReport(c, target, types.MustBelief(0.4, 0.3, 0.3))
+	last := assertFresh(t, o, target, "reports")
+
+	// each change moves the answer without a report about target
+	changes := []struct {
+		name  string
+		apply func()
+	}{
+		{"trust change", func() {
+			for range 5 {
+				o.registry.RecordWrong(c)
+			}
+		}},
+		{"collusion on other targets", func() {
+			for i := range witness.DefaultCollusionMinCoReports {
+				other := types.NewNodeID(uint64(200 + i))
+				o.ReceiveReport(a, other, types.MustBelief(0.8, 0.1, 0.1))
+				o.ReceiveReport(b, other, types.MustBelief(0.7, 0.2, 0.1))
+				// enough witnesses on other for lockstep to count
+				for k := range witness.DefaultCollusionMinWitnesses - 2 {
+					o.ReceiveReport(types.NewNodeID(uint64(300+10*i+k)), other, types.MustBelief(0.6, 0.2, 0.2))
+				}
+			}
+		}},
+		{"aggregation mode", func() { o.WithAggregationMode(witness.ModeWeightedMedian) }},
+		{"max witness share", func() { o.WithAggregationMode(witness.ModeWeightedMean).WithMaxWitnessShare(0.34) }},
+	}
+	for _, ch := range changes {
+		o.Query(target) // cached
+		ch.apply()
+		got := assertFresh(t, o, target, ch.name)
+		if got.Equal(last) {
+			t.Errorf("%s did not move the answer, the test proves nothing", ch.name)
+		}
+		last = got
+	}
+}
+
+func TestCacheOptionsIndependent(t *testing.T) {
+	m := &metrics.Metrics{}
+	o := New(types.NewNodeID(1)).WithMetrics(m).WithQueryCache(time.Minute).WithBeliefCache(false)
+	target := types.NewNodeID(100)
+	o.ReceiveReport(types.NewNodeID(10), target, types.MustBelief(0.8, 0.1, 0.1))
+
+	o.Query(target)
+	o.Query(target)
+	if m.QueryCacheHits != 1 {
+		t.Errorf("WithBeliefCache(false) disabled the TTL cache: %d hits", m.QueryCacheHits)
+	}
+
+	o.WithBeliefCache(true).WithQueryCache(0)
+	o.Query(target)
+	o.Query(target)
+	if m.QueryCacheHits != 2 {
+		t.Errorf("WithQueryCache(0) disabled the belief cache: %d hits", m.QueryCacheHits)
+	}
+}
+
+func TestQueryCacheHitsKeepTrendsAndTrustCurrent(t *testing.T) {
+	m := &metrics.Metrics{}
+	o := New(types.NewNodeID(1)).WithMetrics(m).WithQueryCache(time.Hour)
+	h := NewHarnessFor(o, 1)
+	target := types.NewNodeID(100)
+	a, b, c := types.NewNodeID(10), types.NewNodeID(11), types.NewNodeID(12)
+	o.ReceiveReport(a, target, types.MustBelief(0.9, 0.05, 0.05))
+	o.ReceiveReport(b, target, types.MustBelief(0.8, 0.1, 0.1))
+	o.ReceiveReport(c, target, types.MustBelief(0.4, 0.3, 0.3))
+
+	// every answer after the first is a hit and, with the clock moving on,
+	// still a disagreement sample
+	for range 3 {
+		o.Query(target)
+		h.AdvanceClock(1)
+	}
+	if m.QueryCacheHits != 2 {
+		t.Fatalf("%d hits, want 2", m.QueryCacheHits)
+	}
+	if n := len(o.DisagreementTrend(target)); n != 3 {
+		t.Errorf("%d disagreement samples over 3 answers, want 3", n)
+	}
+
+	before := assertFresh(t, o, target, "hits")
+	for range 5 {
+		o.registry.RecordWrong(c)
+	}
+	if got := assertFresh(t, o, target, "trust change"); got.Equal(before) {
+		t.Error("trust change did not move the answer, the test proves nothing")
+	}
+}
@@ -0,0 +1,100 @@
+package oracle
+
+import "github.com/styx-oracle/styx/partition"
+
+// Recommendation is an actionable reading of a QueryResult under a
+// caller supplied RecommendPolicy, never a bare alive/dead boolean
+type Recommendation int
+
+const (
+	// Wait - evidence is not strong enough either way yet, ask again later
+	Wait Recommendation = iota
+	// ProceedAsAlive - alive mass clears the policy, keep routing to the node
+	ProceedAsAlive
+	// TreatAsDead - dead mass clears the policy (or death is final)
+	TreatAsDead
+	// EscalateToHuman - the oracle cannot settle it, e.g. a confirmed
+	// partition or contradictory evidence, an operator must decide
+	EscalateToHuman
+)
+
+func (r Recommendation) String() string {
+	switch r {
+	case ProceedAsAlive:
+		return "PROCEED_AS_ALIVE"
+	case TreatAsDead:
+		return "TREAT_AS_DEAD"
+	case EscalateToHuman:
+		return "ESCALATE_TO_HUMAN"
+	default:
+		return "WAIT"
+	}
+}
+
+// RecommendPolicy is the caller's risk appetite, there is deliberately
+// no default: MinAlive and MinDead must both be in (0, 1] or Recommend
+// escalates instead of guessing
+type RecommendPolicy struct {
+	// MinAlive is the alive mass needed to proceed
+	MinAlive float64
+	// MinDead is the dead mass needed to treat the node as dead
+	MinDead float64
+	// MaxUnknown is the most unknown mass either decision may carry,
+	// 0 means no limit
+	MaxUnknown float64
+	// RequireFinalDeath only treats a node as dead once death is final,
+	// a strong but unfinalized dead belief yields Wait
+	RequireFinalDeath bool
+	// EscalateOnPartition escalates confirmed partitions instead of waiting
+	// for them to heal
+	EscalateOnPartition bool
+	// MaxDisagreement escalates answers whose witnesses disagree more than
+	// this, 0 disables the check
+	MaxDisagreement float64
+}
+
+// valid reports whether the policy states both thresholds
+func (p RecommendPolicy) valid() bool {
+	return p.MinAlive > 0 && p.MinAlive <= 1 && p.MinDead > 0 && p.MinDead <= 1 &&
+		p.MaxUnknown >= 0 && p.MaxDisagreement >= 0
+}
+
+// Recommend maps the result onto a Recommendation under policy
+// finalized death always wins, partitions and refusals never proceed
+func (r QueryResult) Recommend(policy RecommendPolicy) Recommendation {
+	if !policy.valid() {
+		return EscalateToHuman
+	}
+	if r.Dead {
+		return TreatAsDead
+	}
+	if r.PartitionState == partition.ConfirmedPartition {
+		if policy.EscalateOnPartition {
+			return EscalateToHuman
+		}
+		return Wait
+	}
+	if r.Refused {
+		return Wait
+	}
+	if policy.MaxDisagreement > 0 && r.Disagreement > policy.MaxDisagreement {
+		return EscalateToHuman
+	}
+	if policy.MaxUnknown > 0 && r.Belief.Unknown().Value() > policy.MaxUnknown {
+		return Wait
+	}
+
+	alive := r.Belief.Alive().Value() >= policy.MinAlive
+	dead := r.Belief.Dead().Value() >= policy.MinDead
+	switch {
+	case alive && dead:
+		// loose thresholds both met, the evidence contradicts itself
+		return EscalateToHuman
+	case alive:
+		return ProceedAsAlive
+	case dead && !policy.RequireFinalDeath:
+		return TreatAsDead
+	default:
+		return Wait
+	}
+}
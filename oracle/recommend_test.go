@@ -0,0 +1,42 @@
+package oracle
+
+import (
+	"testing"
+
+	"github.com/styx-oracle/styx/partition"
+	"github.com/styx-oracle/styx/types"
+)
+
+func TestRecommend(t *testing.T) {
+	policy := RecommendPolicy{MinAlive: 0.7, MinDead: 0.7, MaxUnknown: 0.3, EscalateOnPartition: true, MaxDisagreement: 0.3}
+	strictDead := policy
+	strictDead.RequireFinalDeath = true
+	loose := RecommendPolicy{MinAlive: 0.4, MinDead: 0.4}
+
+	cases := []struct {
+		name   string
+		result QueryResult
+		policy RecommendPolicy
+		want   Recommendation
+	}{
+		{"certainly alive", QueryResult{Belief: types.CertainlyAlive()}, policy, ProceedAsAlive},
+		{"certainly dead", QueryResult{Belief: types.CertainlyDead()}, policy, TreatAsDead},
+		{"unknown", QueryResult{Belief: types.UnknownBelief()}, policy, Wait},
+		{"weak alive", QueryResult{Belief: types.MustBelief(0.6, 0.2, 0.2)}, policy, Wait},
+		{"too uncertain", QueryResult{Belief: types.MustBelief(0.6, 0, 0.4)}, RecommendPolicy{MinAlive: 0.5, MinDead: 0.5, MaxUnknown: 0.3}, Wait},
+		{"dead not final", QueryResult{Belief: types.CertainlyDead()}, strictDead, Wait},
+		{"dead final", QueryResult{Belief: types.CertainlyDead(), Dead: true}, strictDead, TreatAsDead},
+		{"contradictory", QueryResult{Belief: types.MustBelief(0.5, 0.45, 0.05)}, loose, EscalateToHuman},
+		{"witnesses split", QueryResult{Belief: types.MustBelief(0.8, 0.15, 0.05), Disagreement: 0.4}, policy, EscalateToHuman},
+		{"refused", QueryResult{Belief: types.CertainlyAlive(), Refused: true, RefusalReason: "insufficient"}, policy, Wait},
+		{"partition escalates", QueryResult{Belief: types.CertainlyAlive(), Refused: true, PartitionState: partition.ConfirmedPartition}, policy, EscalateToHuman},
+		{"partition waits", QueryResult{Belief: types.CertainlyAlive(), Refused: true, PartitionState: partition.ConfirmedPartition}, loose, Wait},
+		{"suspected partition answered", QueryResult{Belief: types.CertainlyAlive(), PartitionState: partition.SuspectedPartition}, policy, ProceedAsAlive},
+		{"no policy", QueryResult{Belief: types.CertainlyAlive()}, RecommendPolicy{}, EscalateToHuman},
+	}
+	for _, c := range cases {
+		if got := c.result.Recommend(c.policy); got != c.want {
+			t.Errorf("%s: got %s, want %s", c.name, got, c.want)
+		}
+	}
+}
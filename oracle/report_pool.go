@@ -0,0 +1,49 @@
+package oracle
+
+import (
+	"sync"
+
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+// maxPooledReports keeps one huge target from pinning a big array in the pool
+const maxPooledReports = 1024
+
+// reportPool recycles the aggregation input assess builds for targets with
+// evidence, expired or evicted reports, saving a slice per query
+// holds pointers so Put does not allocate
+var reportPool = sync.Pool{New: func() any {
+	s := make([]witness.WitnessReport, 0, 8)
+	return &s
+}}
+
+// pooledReports is aggregation input borrowed from reportPool
+type pooledReports struct {
+	reports []witness.WitnessReport
+	buf     *[]witness.WitnessReport
+	built   bool
+}
+
+// pooledReportsFor is reportsFor backed by reportPool
+// the reports must never escape the aggregation they were fetched for:
+// release hands the slice back to be reused by the next query, call it
+// only once nothing reads the reports anymore
+// caller must hold o.mu
+func (o *Oracle) pooledReportsFor(target types.NodeID) pooledReports {
+	buf := reportPool.Get().(*[]witness.WitnessReport)
+	reports, built := o.reportsInto(target, *buf)
+	return pooledReports{reports: reports, buf: buf, built: built}
+}
+
+// release returns the borrowed slice to reportPool
+func (p pooledReports) release() {
+	if p.built {
+		if cap(p.reports) > maxPooledReports {
+			return
+		}
+		clear(p.reports)
+		*p.buf = p.reports[:0]
+	}
+	reportPool.Put(p.buf)
+}
@@ -0,0 +1,107 @@
+package oracle
+
+import (
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+// reportStore maps targets to their report lists, oldest first
+// guarded by o.mu like the rest of the Oracle state
+// an append writes past the length of every list already handed out, or
+// into a new array, never inside one, so a list loaded under the lock
+// stays valid after it is released and appends cost O(1) amortized
+type reportStore struct {
+	targets map[types.NodeID][]witness.WitnessReport
+}
+
+// load returns the reports of target, callers must not modify them
+// capped at its length so an append by the caller copies instead of
+// writing into the shared array
+// caller must hold o.mu
+func (s *reportStore) load(target types.NodeID) []witness.WitnessReport {
+	reports := s.targets[target]
+	return reports[:len(reports):len(reports)]
+}
+
+// append adds report under its target keeping at most limit reports,
+// 0 keeps every report
+// caller must hold o.mu for writing
+func (s *reportStore) append(report witness.WitnessReport, limit int) {
+	if s.targets == nil {
+		s.targets = make(map[types.NodeID][]witness.WitnessReport)
+	}
+	reports := s.targets[report.Target]
+	if limit > 0 && len(reports) >= limit {
+		reports = trimReports(reports, len(reports)-limit+1)
+	}
+	s.targets[report.Target] = append(reports, report)
+}
+
+// trimAll applies limit to every target, see trimReports
+// caller must hold o.mu for writing
+func (s *reportStore) trimAll(limit int) {
+	if limit == 0 {
+		return
+	}
+	for target, reports := range s.targets {
+		if len(reports) > limit {
+			s.targets[target] = trimReports(reports, len(reports)-limit)
+		}
+	}
+}
+
+// removeWitness drops every report from id, returns the targets it reported on
+// caller must hold o.mu for writing
+func (s *reportStore) removeWitness(id types.NodeID) []types.NodeID {
+	var targets []types.NodeID
+	for target, reports := range s.targets {
+		next := make([]witness.WitnessReport, 0, len(reports))
+		for _, r := range reports {
+			if r.Witness != id {
+				next = append(next, r)
+			}
+		}
+		if len(next) != len(reports) {
+			s.targets[target] = next
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// trimReports drops n reports, each time the oldest report of the witness
+// holding the most, so one chatty witness cannot push everyone else out
+// of a capped list, with one report per witness the oldest goes
+// the result never shares written elements with a published snapshot
+func trimReports(reports []witness.WitnessReport, n int) []witness.WitnessReport {
+	for ; n > 0 && len(reports) > 0; n-- {
+		i := chattiestOldest(reports)
+		if i == 0 {
+			reports = reports[1:]
+			continue
+		}
+		next := make([]witness.WitnessReport, 0, cap(reports))
+		next = append(next, reports[:i]...)
+		reports = append(next, reports[i+1:]...)
+	}
+	return reports
+}
+
+// chattiestOldest returns the index of the oldest report of the witness
+// with the most reports, ties go to the witness with the oldest report
+func chattiestOldest(reports []witness.WitnessReport) int {
+	counts := make(map[types.NodeID]int, len(reports))
+	best := 0
+	for _, r := range reports {
+		counts[r.Witness]++
+		if counts[r.Witness] > best {
+			best = counts[r.Witness]
+		}
+	}
+	for i, r := range reports {
+		if counts[r.Witness] == best {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,146 @@
+package oracle
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/styx-oracle/styx/evidence"
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+func TestVerifyAndReceiveReport(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherPriv, _ := ed25519.GenerateKey(nil)
+
+	o := New(types.NewNodeID(1))
+	w, target := types.NewNodeID(10), types.NewNodeID(100)
+	if err := o.SetPublicKey(w, pub); err != nil {
+		t.Fatal(err)
+	}
+	belief := types.MustBelief(0.8, 0.1, 0.1)
+
+	if err := o.VerifyAndReceiveReport(types.NewSignedReport(w, target, belief, priv)); err != nil {
+		t.Fatalf("valid signature rejected: %v", err)
+	}
+
+	tampered := types.NewSignedReport(w, target, belief, priv)
+	tampered.Belief = types.MustBelief(0.1, 0.8, 0.1)
+	if err := o.VerifyAndReceiveReport(tampered); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("tampered belief: got %v", err)
+	}
+
+	forged := types.NewSignedReport(w, target, belief, otherPriv)
+	if err := o.VerifyAndReceiveReport(forged); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("wrong key: got %v", err)
+	}
+
+	unknown := types.NewSignedReport(types.NewNodeID(11), target, belief, otherPriv)
+	if err := o.VerifyAndReceiveReport(unknown); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("unknown signer: got %v", err)
+	}
+
+	unsigned := types.SignedReport{Witness: w, Target: target, Belief: belief}
+	if err := o.VerifyAndReceiveReport(unsigned); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("missing signature: got %v", err)
+	}
+
+	if n := o.Query(target).WitnessCount; n != 1 {
+		t.Errorf("witness count %d, want only the valid report", n)
+	}
+
+	// No-key mode is unchanged
+	o.ReceiveReport(types.NewNodeID(12), target, belief)
+	if n := o.Query(target).WitnessCount; n != 2 {
+		t.Errorf("unsigned ReceiveReport not recorded, witness count %d", n)
+	}
+
+	// one signing scheme: a SignedReport is a signed WitnessReport
+	sr := types.SignedReport{Witness: w, Target: target, Belief: belief, Timestamp: 7}.Sign(priv)
+	if _, err := o.ReceiveSignedReport(witness.WitnessReport{
+		Witness: w, Target: target, Belief: belief, Timestamp: 7, Signature: sr.Signature,
+	}); err != nil {
+		t.Errorf("SignedReport signature rejected by ReceiveSignedReport: %v", err)
+	}
+
+	// verification goes through the registry verifier
+	o.registry.SetVerifier(rejectAll{})
+	if err := o.VerifyAndReceiveReport(types.NewSignedReport(w, target, belief, priv)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("registry verifier bypassed: got %v", err)
+	}
+	o.registry.SetVerifier(witness.Ed25519Verifier{})
+
+	o.EvictWitness(w)
+	if err := o.VerifyAndReceiveReport(types.NewSignedReport(w, target, belief, priv)); !errors.Is(err, ErrWitnessEvicted) {
+		t.Errorf("evicted witness: got %v, want ErrWitnessEvicted", err)
+	}
+}
+
+// rejectAll is a witness.Verifier refusing every signature
+type rejectAll struct{}
+
+func (rejectAll) Verify(publicKey, message, signature []byte) bool { return false }
+
+func TestSetPublicKeyRejectsBadKey(t *testing.T) {
+	o := New(types.NewNodeID(1))
+	if err := o.SetPublicKey(types.NewNodeID(10), ed25519.PublicKey("short")); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Errorf("expected ErrInvalidPublicKey, got %v", err)
+	}
+}
+
+func TestReceiveSignedReport(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherPriv, _ := ed25519.GenerateKey(nil)
+
+	o := New(types.NewNodeID(1))
+	w, target := types.NewNodeID(10), types.NewNodeID(100)
+	if err := o.SetPublicKey(w, pub); err != nil {
+		t.Fatal(err)
+	}
+	if !pub.Equal(ed25519.PublicKey(o.registry.PublicKey(w))) {
+		t.Fatal("key set on the Oracle is not the registry key")
+	}
+
+	// the witness signs its own timestamp, the Oracle restamps after checking
+	signed := witness.WitnessReport{
+		Witness:   w,
+		Target:    target,
+		Belief:    types.MustBelief(0.8, 0.1, 0.1),
+		Timestamp: styxtime.LogicalTimestamp(42),
+	}.Sign(priv)
+	if _, err := o.ReceiveSignedReport(signed); err != nil {
+		t.Fatalf("valid signature rejected: %v", err)
+	}
+
+	tampered := signed
+	tampered.Timestamp = styxtime.LogicalTimestamp(43)
+	if _, err := o.ReceiveSignedReport(tampered); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("tampered timestamp: got %v", err)
+	}
+	unknown := signed
+	unknown.Witness = types.NewNodeID(11)
+	unknown = unknown.Sign(otherPriv)
+	if _, err := o.ReceiveSignedReport(unknown); !errors.Is(err, ErrUnknownSigner) {
+		t.Errorf("unknown signer: got %v", err)
+	}
+
+	// a keyed witness can no longer report unsigned
+	o.ReceiveReport(w, target, types.MustBelief(0.0, 0.9, 0.1))
+	if _, err := o.ReceiveReportWithBasis(witness.WitnessReport{Witness: w, Target: target, Belief: types.MustBelief(0.0, 0.9, 0.1)}, evidence.KindWitnessReport); !errors.Is(err, ErrMissingSignature) {
+		t.Errorf("unsigned report with basis: got %v", err)
+	}
+
+	// the stored report was restamped, it still counts
+	q := o.Query(target)
+	if q.WitnessCount != 1 || q.Belief.Dead().Value() > 0.2 {
+		t.Errorf("got %+v, want only the signed alive report", q)
+	}
+}
@@ -0,0 +1,115 @@
+package oracle
+
+import (
+	"sync"
+
+	"github.com/styx-oracle/styx/metrics"
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+)
+
+// MaxTransitionsPerTarget bounds the transitions kept per target
+const MaxTransitionsPerTarget = 64
+
+// StateTransition is a change of the dominant state answered for a target
+type StateTransition struct {
+	From         types.BeliefState
+	To           types.BeliefState
+	At           styxtime.LogicalTimestamp // Oracle clock of the answer
+	WitnessCount int                       // witnesses behind the answer
+}
+
+// MaxTransitionTargets bounds the targets with a transition log of their
+// own, like the per target metrics series
+const MaxTransitionTargets = metrics.DefaultMaxQueryTargets
+
+// transitionRecord is the last answered state and the transitions of one
+// target, or of all targets past MaxTransitionTargets
+type transitionRecord struct {
+	state types.BeliefState
+	log   []StateTransition
+}
+
+// transitionLog keeps recent dominant state transitions per target
+// a target never answered is unknown, its first known answer is a transition
+// targets past maxTargets share the other record, whose state is the last
+// answer about any of them
+// own lock so queries can record under the Oracle read lock
+type transitionLog struct {
+	mu         sync.Mutex
+	targets    map[types.NodeID]*transitionRecord
+	other      transitionRecord
+	maxTargets int
+}
+
+func newTransitionLog() *transitionLog {
+	return &transitionLog{
+		targets:    make(map[types.NodeID]*transitionRecord),
+		other:      transitionRecord{state: types.StateUnknown},
+		maxTargets: MaxTransitionTargets,
+	}
+}
+
+func (l *transitionLog) record(target types.NodeID, result QueryResult) {
+	to := result.Belief.Dominant()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rec, ok := l.targets[target]
+	if !ok {
+		if len(l.targets) >= l.maxTargets {
+			rec = &l.other
+		} else {
+			rec = &transitionRecord{state: types.StateUnknown}
+			l.targets[target] = rec
+		}
+	}
+
+	from := rec.state
+	rec.state = to
+	if from == to {
+		return
+	}
+	if len(rec.log) >= MaxTransitionsPerTarget {
+		rec.log = append(rec.log[:0], rec.log[1:]...)
+	}
+	rec.log = append(rec.log, StateTransition{
+		From:         from,
+		To:           to,
+		At:           result.Timestamp,
+		WitnessCount: result.WitnessCount,
+	})
+}
+
+func (l *transitionLog) get(target types.NodeID) []StateTransition {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if rec, ok := l.targets[target]; ok {
+		return append([]StateTransition(nil), rec.log...)
+	}
+	return nil
+}
+
+func (l *transitionLog) getOther() []StateTransition {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]StateTransition(nil), l.other.log...)
+}
+
+// Transitions returns the dominant state changes queries answered for
+// target, oldest first, to see flapping or a progression toward death
+// after the fact
+// only answers count, a state no query saw leaves no trace
+// targets past MaxTransitionTargets have none of their own, see
+// OtherTransitions
+func (o *Oracle) Transitions(target types.NodeID) []StateTransition {
+	return o.transitions.get(target)
+}
+
+// OtherTransitions returns the shared transitions of the targets past
+// MaxTransitionTargets, oldest first: changes of the dominant state
+// between consecutive answers about any of them
+func (o *Oracle) OtherTransitions() []StateTransition {
+	return o.transitions.getOther()
+}
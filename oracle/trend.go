@@ -0,0 +1,79 @@
+package oracle
+
+import (
+	"sync"
+
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+)
+
+// DisagreementHistorySize bounds the disagreement samples kept per target
+const DisagreementHistorySize = 32
+
+// trendLog keeps recent aggregate disagreement per target
+// sampled when a query sees new reports, repeated queries on unchanged
+// reports add nothing
+// own lock so queries can sample under the Oracle read lock
+type trendLog struct {
+	mu      sync.Mutex
+	samples map[types.NodeID][]float64
+	lastAt  map[types.NodeID]styxtime.LogicalTimestamp
+}
+
+func newTrendLog() *trendLog {
+	return &trendLog{
+		samples: make(map[types.NodeID][]float64),
+		lastAt:  make(map[types.NodeID]styxtime.LogicalTimestamp),
+	}
+}
+
+func (t *trendLog) record(target types.NodeID, at styxtime.LogicalTimestamp, disagreement float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if last, ok := t.lastAt[target]; ok && last == at {
+		return
+	}
+	t.lastAt[target] = at
+
+	s := t.samples[target]
+	if len(s) >= DisagreementHistorySize {
+		s = append(s[:0], s[1:]...)
+	}
+	t.samples[target] = append(s, disagreement)
+}
+
+func (t *trendLog) get(target types.NodeID) []float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]float64(nil), t.samples[target]...)
+}
+
+// DisagreementTrend returns recent aggregate disagreement samples, oldest first
+// Rising disagreement is an early warning before a partition is confirmed
+func (o *Oracle) DisagreementTrend(target types.NodeID) []float64 {
+	return o.trends.get(target)
+}
+
+// DisagreementSlope is the least squares slope of DisagreementTrend
+// positive = witnesses drifting apart, negative = healing
+func (o *Oracle) DisagreementSlope(target types.NodeID) float64 {
+	return linearSlope(o.trends.get(target))
+}
+
+// linearSlope fits ys against their index
+func linearSlope(ys []float64) float64 {
+	if len(ys) < 2 {
+		return 0
+	}
+	n := float64(len(ys))
+	var sumX, sumY, sumXY, sumXX float64
+	for i, y := range ys {
+		x := float64(i)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	return (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+}
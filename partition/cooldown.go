@@ -0,0 +1,55 @@
+package partition
+
+import (
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+// WithCooldown keeps a confirmed partition confirmed for at least units of
+// logical time after it was last seen, so one agreeable batch during
+// flapping does not flip the target back to answering
+// 0 disables the cooldown (the default)
+func (d *Detector) WithCooldown(units uint64) *Detector {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cooldown = units
+	return d
+}
+
+// AnalyzeAt is Analyze at logical time now
+// Analyze counts each analysis of a target as one unit of logical time
+func (d *Detector) AnalyzeAt(reports []witness.WitnessReport, target types.NodeID, now styxtime.LogicalTimestamp) (PartitionState, *SplitReality) {
+	return d.analyze(reports, target, now, false)
+}
+
+// AssessAt is Assess at logical time now, including the cooldown hold of
+// the state last recorded for target, without touching detector state
+// safe for concurrent queries, recording is left to Analyze and AnalyzeAt
+func (d *Detector) AssessAt(reports []witness.WitnessReport, target types.NodeID, now styxtime.LogicalTimestamp) (PartitionState, *SplitReality) {
+	state, split := d.assess(reports, target, now)
+	if state == ConfirmedPartition {
+		return state, split
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	ts, ok := d.targets[target]
+	if ok && ts.state == ConfirmedPartition && d.cooldown > 0 && ts.confirmedAt.AgeSince(now) < d.cooldown {
+		return ConfirmedPartition, ts.lastSplit
+	}
+	return state, split
+}
+
+// holdConfirmed applies the cooldown to a freshly assessed state
+// returns the state to record, caller must hold d.mu
+func (d *Detector) holdConfirmed(ts *targetState, state PartitionState, now styxtime.LogicalTimestamp) PartitionState {
+	if state == ConfirmedPartition {
+		ts.confirmedAt = now
+		return state
+	}
+	if ts.state == ConfirmedPartition && d.cooldown > 0 && ts.confirmedAt.AgeSince(now) < d.cooldown {
+		return ConfirmedPartition
+	}
+	return state
+}
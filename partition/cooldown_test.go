@@ -0,0 +1,95 @@
+package partition
+
+import (
+	"testing"
+
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+func agreeingReports() []witness.WitnessReport {
+	alive := types.MustBelief(0.9, 0.05, 0.05)
+	return splitReports(alive, alive)
+}
+
+func TestCooldownHoldsConfirmedPartition(t *testing.T) {
+	target := types.NewNodeID(100)
+	split := splitReports(types.MustBelief(0.9, 0.05, 0.05), types.MustBelief(0.05, 0.9, 0.05))
+	d := NewDetector().WithCooldown(3)
+
+	// flapping: split at 0, agreeable every other unit
+	steps := []struct {
+		at      styxtime.LogicalTimestamp
+		reports []witness.WitnessReport
+		want    PartitionState
+	}{
+		{0, split, ConfirmedPartition},
+		{1, agreeingReports(), ConfirmedPartition}, // held
+		{2, split, ConfirmedPartition},             // refreshed
+		{3, agreeingReports(), ConfirmedPartition},
+		{4, agreeingReports(), ConfirmedPartition},
+		{5, agreeingReports(), NoPartition}, // 3 units after the last split
+		{6, split, ConfirmedPartition},
+	}
+	for _, s := range steps {
+		state, got := d.AnalyzeAt(s.reports, target, s.at)
+		if state != s.want {
+			t.Errorf("@%d: got %s, want %s", s.at, state, s.want)
+		}
+		if state == ConfirmedPartition && got == nil {
+			t.Errorf("@%d: confirmed without a split", s.at)
+		}
+		if d.ShouldRefuseAnswer(target) != (s.want == ConfirmedPartition) {
+			t.Errorf("@%d: refusal does not match %s", s.at, s.want)
+		}
+	}
+}
+
+func TestCooldownCountsAnalyses(t *testing.T) {
+	target := types.NewNodeID(100)
+	split := splitReports(types.MustBelief(0.9, 0.05, 0.05), types.MustBelief(0.05, 0.9, 0.05))
+
+	// without a cooldown the state follows every batch
+	d := NewDetector()
+	for i := range 4 {
+		reports, want := split, ConfirmedPartition
+		if i%2 == 1 {
+			reports, want = agreeingReports(), NoPartition
+		}
+		if state, _ := d.Analyze(reports, target); state != want {
+			t.Fatalf("analysis %d: got %s, want %s", i, state, want)
+		}
+	}
+
+	// Analyze counts each call as one unit
+	d = NewDetector().WithCooldown(2)
+	d.Analyze(split, target)
+	if state, _ := d.Analyze(agreeingReports(), target); state != ConfirmedPartition {
+		t.Errorf("first agreeable batch: got %s, want held CONFIRMED_PARTITION", state)
+	}
+	if state, _ := d.Analyze(agreeingReports(), target); state != NoPartition {
+		t.Errorf("after cooldown: got %s, want NO_PARTITION", state)
+	}
+}
+
+func TestAssessAtAppliesCooldownWithoutRecording(t *testing.T) {
+	target := types.NewNodeID(100)
+	split := splitReports(types.MustBelief(0.9, 0.05, 0.05), types.MustBelief(0.05, 0.9, 0.05))
+	d := NewDetector().WithCooldown(3)
+	d.AnalyzeAt(split, target, 0)
+
+	if state, got := d.AssessAt(agreeingReports(), target, 2); state != ConfirmedPartition || got == nil {
+		t.Errorf("inside the cooldown: got %s with split %v", state, got)
+	}
+	if state, _ := d.AssessAt(agreeingReports(), target, 3); state != NoPartition {
+		t.Errorf("after the cooldown: got %s", state)
+	}
+
+	// assessing never records, the target and the overall state are unchanged
+	other := types.NewNodeID(200)
+	d.AssessAt(split, other, 4)
+	if d.GetState(other) != NoPartition || len(d.History(0)) != 1 {
+		t.Errorf("AssessAt recorded state: %s, history %v", d.GetState(other), d.History(0))
+	}
+}
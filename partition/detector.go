@@ -1,8 +1,11 @@
 package partition
 
 import (
+	"math"
 	"sync"
+	"time"
 
+	styxtime "github.com/styx-oracle/styx/time"
 	"github.com/styx-oracle/styx/types"
 	"github.com/styx-oracle/styx/witness"
 )
@@ -47,29 +50,128 @@ type SplitReality struct {
 }
 
 // Detector detects network partitions from witness reports
+// state is tracked per target, a partition around one node says nothing
+// about another
 type Detector struct {
 	mu                    sync.RWMutex
-	state                 PartitionState
-	lastSplit             *SplitReality
+	targets               map[types.NodeID]*targetState
 	disagreementThreshold float64
+
+	// overall state, the worst state of any target
+	counts     [ConfirmedPartition + 1]int
+	overall    PartitionState
+	since      time.Time
+	history    []PartitionTransition
+	maxHistory int
+
+	// latest network instability reading per target and witness, see
+	// RecordNetworkInstability, instabilityClock is the newest reading time
+	instability      map[types.NodeID]map[types.NodeID]instabilityReading
+	instabilityClock styxtime.LogicalTimestamp
+	instabilitySwept styxtime.LogicalTimestamp
+
+	// logical time a confirmed partition holds, see WithCooldown
+	cooldown uint64
+
+	// belief variance that confirms a bimodal split, see WithVarianceDetection
+	varianceThreshold float64
+}
+
+// targetState is the last analysis recorded for one target
+type targetState struct {
+	state       PartitionState
+	lastSplit   *SplitReality
+	lastAt      styxtime.LogicalTimestamp
+	confirmedAt styxtime.LogicalTimestamp
+}
+
+// StrongOpinionMargin is the alive/dead gap at which a witness vote counts fully
+// witnesses closer to the fence count proportionally less
+const StrongOpinionMargin = 0.5
+
+// opinionStrength weights a vote by how decided the witness is, in [0,1]
+func opinionStrength(b types.Belief) float64 {
+	margin := math.Abs(b.Alive().Value() - b.Dead().Value())
+	return math.Min(margin/StrongOpinionMargin, 1.0)
 }
 
 // NewDetector creates a partition detector
 func NewDetector() *Detector {
 	return &Detector{
-		state:                 NoPartition,
+		targets:               make(map[types.NodeID]*targetState),
 		disagreementThreshold: 0.4,
+		overall:               NoPartition,
+		since:                 time.Now(),
+		maxHistory:            DefaultMaxHistory,
+		instability:           make(map[types.NodeID]map[types.NodeID]instabilityReading),
 	}
 }
 
 // Analyze checks for partition based on witness reports
 // Returns partition state and any split realities detected
+// and records them as the state of target
+// during a cooldown a confirmed partition is returned with its last split
 func (d *Detector) Analyze(reports []witness.WitnessReport, target types.NodeID) (PartitionState, *SplitReality) {
+	return d.analyze(reports, target, 0, true)
+}
+
+// analyze records an assessment of target at now
+// tick advances the target's own logical time instead
+func (d *Detector) analyze(reports []witness.WitnessReport, target types.NodeID, now styxtime.LogicalTimestamp, tick bool) (PartitionState, *SplitReality) {
+	state, split := d.assess(reports, target, now)
+
 	d.mu.Lock()
 	defer d.mu.Unlock()
+	ts, ok := d.targets[target]
+	if !ok {
+		ts = &targetState{state: NoPartition}
+		d.targets[target] = ts
+		d.counts[NoPartition]++
+	}
+	if tick {
+		now = ts.lastAt + 1
+	}
+	ts.lastAt = now
+
+	if held := d.holdConfirmed(ts, state, now); held != state {
+		state, split = held, ts.lastSplit
+	}
+	d.counts[ts.state]--
+	d.counts[state]++
+	ts.state = state
+	if split != nil {
+		ts.lastSplit = split
+	}
+	d.updateOverall(target, split)
+	return state, split
+}
 
+// Assess is Analyze without touching detector state
+// safe for concurrent queries, each gets its own result
+// with variance detection enabled the worse of the vote and AssessVariance wins
+// an unstable network path to target turns NoPartition into SuspectedPartition
+func (d *Detector) Assess(reports []witness.WitnessReport, target types.NodeID) (PartitionState, *SplitReality) {
+	return d.assess(reports, target, 0)
+}
+
+// assess is Assess at logical time now, which only ages instability
+// readings, 0 reads them at the newest reading
+func (d *Detector) assess(reports []witness.WitnessReport, target types.NodeID, now styxtime.LogicalTimestamp) (PartitionState, *SplitReality) {
+	state, split := d.assessReports(reports, target)
+	if state != ConfirmedPartition && d.varianceDetection() > 0 {
+		if vstate, vsplit := d.AssessVariance(reports, target); vstate > state {
+			state, split = vstate, vsplit
+		}
+	}
+	if state == NoPartition && d.networkUnstable(target, now) {
+		return SuspectedPartition, nil
+	}
+	return state, split
+}
+
+// assessReports looks for a split in the reports alone
+func (d *Detector) assessReports(reports []witness.WitnessReport, target types.NodeID) (PartitionState, *SplitReality) {
 	if len(reports) < 2 {
-		d.state = NoPartition
 		return NoPartition, nil
 	}
 
@@ -77,13 +179,16 @@ func (d *Detector) Analyze(reports []witness.WitnessReport, target types.NodeID)
 	aliveVotes := 0
 	deadVotes := 0
 	unknownVotes := 0
+	var aliveWeight, deadWeight float64
 
 	for _, r := range reports {
 		switch r.Belief.Dominant() {
 		case types.StateAlive:
 			aliveVotes++
+			aliveWeight += opinionStrength(r.Belief)
 		case types.StateDead:
 			deadVotes++
+			deadWeight += opinionStrength(r.Belief)
 		case types.StateUnknown:
 			unknownVotes++
 		}
@@ -92,13 +197,13 @@ func (d *Detector) Analyze(reports []witness.WitnessReport, target types.NodeID)
 	total := len(reports)
 
 	// If witnesses strongly disagree, suspect partition
+	// weak opinions count for less, a split of marginal beliefs is not a partition
 	if aliveVotes > 0 && deadVotes > 0 {
-		disagreement := float64(min(aliveVotes, deadVotes)) / float64(total)
+		disagreement := math.Min(aliveWeight, deadWeight) / float64(total)
 
-		if disagreement > d.disagreementThreshold {
+		// Threshold drops for small witness sets and rises for large ones
+		if disagreement > witness.AdaptiveThreshold(d.disagreementThreshold, total) {
 			// Confirmed split - some see alive, some see dead
-			d.state = ConfirmedPartition
-
 			split := &SplitReality{
 				Disagreement: disagreement,
 				Ambiguous:    []types.NodeID{target},
@@ -125,51 +230,45 @@ func (d *Detector) Analyze(reports []witness.WitnessReport, target types.NodeID)
 			}
 
 			split.Groups = []WitnessGroup{aliveGroup, deadGroup}
-			d.lastSplit = split
-
 			return ConfirmedPartition, split
 		}
 
 		// Some disagreement but not extreme
-		d.state = SuspectedPartition
 		return SuspectedPartition, nil
 	}
 
 	// High unknown votes also suggest partition
 	if float64(unknownVotes)/float64(total) > 0.5 {
-		d.state = SuspectedPartition
 		return SuspectedPartition, nil
 	}
 
-	d.state = NoPartition
 	return NoPartition, nil
 }
 
-// GetState returns current partition state
-func (d *Detector) GetState() PartitionState {
+// GetState returns the last recorded partition state of target
+// targets never analyzed have NoPartition
+func (d *Detector) GetState(target types.NodeID) PartitionState {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
-	return d.state
+	if ts, ok := d.targets[target]; ok {
+		return ts.state
+	}
+	return NoPartition
 }
 
-// GetLastSplit returns the last detected split reality
-func (d *Detector) GetLastSplit() *SplitReality {
+// GetLastSplit returns the last split reality detected around target
+func (d *Detector) GetLastSplit(target types.NodeID) *SplitReality {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
-	return d.lastSplit
+	if ts, ok := d.targets[target]; ok {
+		return ts.lastSplit
+	}
+	return nil
 }
 
-// ShouldRefuseAnswer returns true if partition makes answering dishonest
+// ShouldRefuseAnswer returns true if a partition around target makes
+// answering about it dishonest
 // STYX refuses to guess during partitions
-func (d *Detector) ShouldRefuseAnswer() bool {
-	d.mu.RLock()
-	defer d.mu.RUnlock()
-	return d.state == ConfirmedPartition
-}
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
+func (d *Detector) ShouldRefuseAnswer(target types.NodeID) bool {
+	return d.GetState(target) == ConfirmedPartition
 }
@@ -0,0 +1,224 @@
+package partition
+
+import (
+	"testing"
+
+	"github.com/styx-oracle/styx/evidence"
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+func splitReports(alive, dead types.Belief) []witness.WitnessReport {
+	target := types.NewNodeID(100)
+	reports := make([]witness.WitnessReport, 0, 4)
+	for i := uint64(1); i <= 2; i++ {
+		reports = append(reports,
+			witness.WitnessReport{Witness: types.NewNodeID(i), Target: target, Belief: alive},
+			witness.WitnessReport{Witness: types.NewNodeID(i + 10), Target: target, Belief: dead},
+		)
+	}
+	return reports
+}
+
+func TestAnalyzeWeighsOpinionStrength(t *testing.T) {
+	target := types.NewNodeID(100)
+
+	// Same 2 vs 2 vote count, only conviction differs
+	weak := splitReports(types.MustBelief(0.5, 0.38, 0.12), types.MustBelief(0.38, 0.5, 0.12))
+	strong := splitReports(types.MustBelief(0.9, 0.05, 0.05), types.MustBelief(0.05, 0.9, 0.05))
+
+	if state, _ := NewDetector().Analyze(weak, target); state == ConfirmedPartition {
+		t.Errorf("weak split confirmed as partition")
+	}
+
+	state, split := NewDetector().Analyze(strong, target)
+	if state != ConfirmedPartition {
+		t.Fatalf("strong split: got %s, want CONFIRMED_PARTITION", state)
+	}
+	if split.Disagreement != 0.5 {
+		t.Errorf("strong split disagreement %f, want 0.5", split.Disagreement)
+	}
+}
+
+// oneThirdDissent builds n strong reports where every third says dead
+func oneThirdDissent(n int) []witness.WitnessReport {
+	return dissent(n, n/3)
+}
+
+// dissent builds n strong reports where the first dead say dead
+func dissent(n, dead int) []witness.WitnessReport {
+	target := types.NewNodeID(100)
+	reports := make([]witness.WitnessReport, n)
+	for i := range reports {
+		b := types.MustBelief(0.9, 0.05, 0.05)
+		if i < dead {
+			b = types.MustBelief(0.05, 0.9, 0.05)
+		}
+		reports[i] = witness.WitnessReport{Witness: types.NewNodeID(uint64(i + 1)), Target: target, Belief: b}
+	}
+	return reports
+}
+
+func TestAnalyzeThresholdAdaptsToWitnessCount(t *testing.T) {
+	target := types.NewNodeID(100)
+
+	// 1 of 3 disagreeing is a real split in a tiny cluster
+	if state, _ := NewDetector().Analyze(oneThirdDissent(3), target); state != ConfirmedPartition {
+		t.Errorf("3 witnesses: got %s, want CONFIRMED_PARTITION", state)
+	}
+	// the same third of 300 stays below the tighter large-cluster band
+	if state, _ := NewDetector().Analyze(oneThirdDissent(300), target); state != SuspectedPartition {
+		t.Errorf("300 witnesses: got %s, want SUSPECTED_PARTITION", state)
+	}
+	// 41% dissent clears the base 0.4 but is within the large-cluster band
+	if state, _ := NewDetector().Analyze(dissent(300, 123), target); state != SuspectedPartition {
+		t.Errorf("300 witnesses, 41%% dissent: got %s, want SUSPECTED_PARTITION", state)
+	}
+	// 40% of 10 is a split in a small cluster
+	if state, _ := NewDetector().Analyze(dissent(10, 4), target); state != ConfirmedPartition {
+		t.Errorf("10 witnesses, 40%% dissent: got %s, want CONFIRMED_PARTITION", state)
+	}
+}
+
+func TestAnalyzeTracksStatePerTarget(t *testing.T) {
+	d := NewDetector()
+	split, calm := types.NewNodeID(100), types.NewNodeID(200)
+
+	d.Analyze(splitReports(types.MustBelief(0.9, 0.05, 0.05), types.MustBelief(0.05, 0.9, 0.05)), split)
+	d.Analyze([]witness.WitnessReport{
+		{Witness: types.NewNodeID(1), Target: calm, Belief: types.MustBelief(0.9, 0.05, 0.05)},
+		{Witness: types.NewNodeID(2), Target: calm, Belief: types.MustBelief(0.85, 0.05, 0.1)},
+	}, calm)
+
+	// analyzing calm last must not clear the partition around split
+	if got := d.GetState(split); got != ConfirmedPartition {
+		t.Errorf("split target state %s, want CONFIRMED_PARTITION", got)
+	}
+	if !d.ShouldRefuseAnswer(split) || d.GetLastSplit(split) == nil {
+		t.Error("split target should be refused with a recorded split")
+	}
+	if got := d.GetState(calm); got != NoPartition {
+		t.Errorf("calm target state %s, want NO_PARTITION", got)
+	}
+	if d.ShouldRefuseAnswer(calm) || d.GetLastSplit(calm) != nil {
+		t.Error("calm target should be answered")
+	}
+	if d.ShouldRefuseAnswer(types.NewNodeID(300)) {
+		t.Error("unanalyzed target refused")
+	}
+}
+
+func TestHistoryRecordsPartitionAndHealing(t *testing.T) {
+	d := NewDetector()
+	target := types.NewNodeID(100)
+	alive := types.MustBelief(0.9, 0.05, 0.05)
+
+	d.Analyze(splitReports(alive, types.MustBelief(0.05, 0.9, 0.05)), target)
+	d.Analyze(splitReports(alive, types.MustBelief(0.05, 0.9, 0.05)), target) // no change, no entry
+	d.Analyze(splitReports(alive, alive), target)
+
+	h := d.History(0)
+	if len(h) != 2 {
+		t.Fatalf("got %d transitions, want 2: %+v", len(h), h)
+	}
+	if h[0].From != NoPartition || h[0].To != ConfirmedPartition || h[0].Disagreement != 0.5 {
+		t.Errorf("first transition %+v, want NO -> CONFIRMED at 0.5", h[0])
+	}
+	if h[1].From != ConfirmedPartition || h[1].To != NoPartition || h[1].Target != target {
+		t.Errorf("second transition %+v, want CONFIRMED -> NO", h[1])
+	}
+	if h[1].At.Before(h[0].At) {
+		t.Error("transitions out of order")
+	}
+	if d.State() != NoPartition || d.PartitionDuration() < 0 {
+		t.Errorf("state %s for %v after healing", d.State(), d.PartitionDuration())
+	}
+
+	if last := d.History(1); len(last) != 1 || last[0] != h[1] {
+		t.Errorf("History(1) = %+v, want the healing transition", last)
+	}
+	d.WithMaxHistory(1)
+	if kept := d.History(0); len(kept) != 1 || kept[0] != h[1] {
+		t.Errorf("after WithMaxHistory(1) kept %+v", kept)
+	}
+}
+
+func TestNetworkInstabilitySuggestsPartition(t *testing.T) {
+	target := types.NewNodeID(100)
+	src := types.NewNodeID(1)
+	agreeing := []witness.WitnessReport{
+		{Witness: types.NewNodeID(1), Target: target, Belief: types.MustBelief(0.8, 0.1, 0.1)},
+		{Witness: types.NewNodeID(2), Target: target, Belief: types.MustBelief(0.75, 0.1, 0.15)},
+	}
+
+	d := NewDetector()
+	if state, _ := d.Analyze(agreeing, target); state != NoPartition {
+		t.Fatalf("agreeing witnesses: got %s, want NO_PARTITION", state)
+	}
+
+	d.RecordNetworkInstability(src, target, evidence.NewNetworkInstability(1, 0.8, 500, src, target))
+	if state, _ := d.Analyze(agreeing, target); state != SuspectedPartition {
+		t.Errorf("lossy path: got %s, want SUSPECTED_PARTITION", state)
+	}
+	if state, _ := d.Assess(agreeing, types.NewNodeID(101)); state != NoPartition {
+		t.Errorf("instability leaked to another target: %s", state)
+	}
+
+	d.RecordNetworkInstability(src, target, evidence.NewNetworkInstability(2, 0, 0, src, target))
+	if state, _ := d.Analyze(agreeing, target); state != NoPartition {
+		t.Errorf("healthy reading should clear suspicion, got %s", state)
+	}
+}
+
+func TestNetworkInstabilityPerWitnessAndExpiring(t *testing.T) {
+	target, other := types.NewNodeID(100), types.NewNodeID(101)
+	a, b, c := types.NewNodeID(1), types.NewNodeID(2), types.NewNodeID(3)
+	agreeing := []witness.WitnessReport{
+		{Witness: a, Target: target, Belief: types.MustBelief(0.8, 0.1, 0.1)},
+		{Witness: b, Target: target, Belief: types.MustBelief(0.75, 0.1, 0.15)},
+	}
+	unstable := func(d *Detector, id types.NodeID, now styxtime.LogicalTimestamp) bool {
+		state, _ := d.AssessAt(agreeing, id, now)
+		return state == SuspectedPartition
+	}
+
+	// keyed by the target it was recorded for, not the evidence's claim
+	d := NewDetector()
+	d.RecordNetworkInstability(a, target, evidence.NewNetworkInstability(1, 1, 10000, a, other))
+	if !unstable(d, target, 1) || unstable(d, other, 1) {
+		t.Errorf("instability recorded for %v applied elsewhere", target)
+	}
+
+	// a healthy witness does not wipe another's reading, it outvotes it
+	d.RecordNetworkInstability(b, target, evidence.NewNetworkInstability(2, 0, 0, b, target))
+	if !unstable(d, target, 2) {
+		t.Error("one healthy reading cleared another witness's instability")
+	}
+	d.RecordNetworkInstability(c, target, evidence.NewNetworkInstability(3, 0, 0, c, target))
+	if unstable(d, target, 3) {
+		t.Error("two healthy witnesses should outweigh one unstable one")
+	}
+
+	// weights are capped, a forged weight of 1 cannot outvote two healthy readings
+	d = NewDetector()
+	forged := evidence.NewNetworkInstability(1, 1, 0, a, target)
+	forged.Weight = 1
+	d.RecordNetworkInstability(a, target, forged)
+	d.RecordNetworkInstability(b, target, evidence.NewNetworkInstability(1, 0, 0, b, target))
+	d.RecordNetworkInstability(c, target, evidence.NewNetworkInstability(1, 0, 0, c, target))
+	if unstable(d, target, 1) {
+		t.Error("instability weight above the cap counted in full")
+	}
+
+	// readings expire
+	d = NewDetector()
+	d.RecordNetworkInstability(a, target, evidence.NewNetworkInstability(1, 0.8, 500, a, target))
+	if unstable(d, target, styxtime.LogicalTimestamp(NetworkInstabilityTTL+2)) {
+		t.Error("expired reading still marks the path unstable")
+	}
+	d.RecordNetworkInstability(b, other, evidence.NewNetworkInstability(styxtime.LogicalTimestamp(3*NetworkInstabilityTTL), 0.8, 500, b, other))
+	if len(d.instability) != 1 {
+		t.Errorf("expired readings kept: %d targets tracked, want 1", len(d.instability))
+	}
+}
@@ -0,0 +1,94 @@
+package partition
+
+import (
+	"time"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+// DefaultMaxHistory is how many transitions a detector keeps by default
+const DefaultMaxHistory = 100
+
+// PartitionTransition is a change of the overall partition state
+// Target is the node whose analysis caused it
+// Disagreement is the split disagreement, 0 when no split was found
+type PartitionTransition struct {
+	From         PartitionState
+	To           PartitionState
+	At           time.Time
+	Disagreement float64
+	Target       types.NodeID
+}
+
+// WithMaxHistory bounds the transition history, oldest entries go first
+// n <= 0 keeps DefaultMaxHistory
+func (d *Detector) WithMaxHistory(n int) *Detector {
+	if n <= 0 {
+		n = DefaultMaxHistory
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.maxHistory = n
+	if len(d.history) > n {
+		d.history = append([]PartitionTransition(nil), d.history[len(d.history)-n:]...)
+	}
+	return d
+}
+
+// State returns the overall partition state, the worst state of any target
+func (d *Detector) State() PartitionState {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.overall
+}
+
+// History returns the last limit transitions of the overall state, oldest
+// first, limit <= 0 returns all kept transitions
+func (d *Detector) History(limit int) []PartitionTransition {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	h := d.history
+	if limit > 0 && len(h) > limit {
+		h = h[len(h)-limit:]
+	}
+	return append([]PartitionTransition(nil), h...)
+}
+
+// PartitionDuration returns how long the overall state has been active
+func (d *Detector) PartitionDuration() time.Duration {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return time.Since(d.since)
+}
+
+// updateOverall recomputes the overall state and logs a transition if it
+// changed, caller must hold d.mu
+func (d *Detector) updateOverall(target types.NodeID, split *SplitReality) {
+	overall := NoPartition
+	switch {
+	case d.counts[ConfirmedPartition] > 0:
+		overall = ConfirmedPartition
+	case d.counts[SuspectedPartition] > 0:
+		overall = SuspectedPartition
+	}
+	if overall == d.overall {
+		return
+	}
+
+	t := PartitionTransition{
+		From:   d.overall,
+		To:     overall,
+		At:     time.Now(),
+		Target: target,
+	}
+	if split != nil {
+		t.Disagreement = split.Disagreement
+	}
+	if len(d.history) >= d.maxHistory {
+		d.history = append(d.history[:0], d.history[1:]...)
+	}
+	d.history = append(d.history, t)
+	d.overall = overall
+	d.since = t.At
+}
@@ -0,0 +1,94 @@
+package partition
+
+import (
+	"math"
+
+	"github.com/styx-oracle/styx/evidence"
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+)
+
+// NetworkInstabilityThreshold is the network instability weight at which the
+// path to a target counts as unstable, see RecordNetworkInstability
+const NetworkInstabilityThreshold = 0.3
+
+// NetworkInstabilityTTL is the logical time a reading counts for, a path
+// nobody measured since is no longer suspect
+const NetworkInstabilityTTL = evidence.DefaultHalfLife
+
+// instabilityReading is the latest instability one witness measured
+type instabilityReading struct {
+	weight float64
+	at     styxtime.LogicalTimestamp
+}
+
+// RecordNetworkInstability feeds network instability evidence witness
+// gathered about the path to target into the detector, other kinds are
+// ignored
+// each witness keeps its latest reading, a healthy reading only replaces
+// that witness's own earlier one, readings expire after
+// NetworkInstabilityTTL and weights are capped at
+// evidence.MaxNetworkInstabilityWeight
+// an unstable path is supporting evidence for SuspectedPartition, it never
+// confirms a partition on its own
+func (d *Detector) RecordNetworkInstability(witness, target types.NodeID, e evidence.Evidence) {
+	if !e.SuggestsNetworkIssue() {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if e.Timestamp > d.instabilityClock {
+		d.instabilityClock = e.Timestamp
+	}
+	byWitness := d.instability[target]
+	if byWitness == nil {
+		byWitness = make(map[types.NodeID]instabilityReading)
+		d.instability[target] = byWitness
+	}
+	byWitness[witness] = instabilityReading{
+		weight: math.Min(e.Weight, evidence.MaxNetworkInstabilityWeight),
+		at:     e.Timestamp,
+	}
+	d.expireInstability()
+}
+
+// expireInstability drops expired readings, at most once per TTL so
+// recording stays cheap, caller must hold d.mu for writing
+func (d *Detector) expireInstability() {
+	if d.instabilitySwept.AgeSince(d.instabilityClock) < NetworkInstabilityTTL {
+		return
+	}
+	d.instabilitySwept = d.instabilityClock
+	for target, byWitness := range d.instability {
+		for w, r := range byWitness {
+			if r.at.AgeSince(d.instabilityClock) > NetworkInstabilityTTL {
+				delete(byWitness, w)
+			}
+		}
+		if len(byWitness) == 0 {
+			delete(d.instability, target)
+		}
+	}
+}
+
+// networkUnstable reports whether the witnesses measuring the path to
+// target at now find it unstable on average, expired readings are skipped
+func (d *Detector) networkUnstable(target types.NodeID, now styxtime.LogicalTimestamp) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.instabilityClock > now {
+		now = d.instabilityClock
+	}
+	var sum float64
+	var n int
+	for _, r := range d.instability[target] {
+		if r.at.AgeSince(now) > NetworkInstabilityTTL {
+			continue
+		}
+		sum += r.weight
+		n++
+	}
+	return n > 0 && sum/float64(n) >= NetworkInstabilityThreshold
+}
@@ -0,0 +1,182 @@
+package partition
+
+import (
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+// DefaultVarianceThreshold is the belief variance above which witnesses
+// split in two count as a partition, an RMS distance of 0.1 from the mean
+const DefaultVarianceThreshold = 0.01
+
+// BimodalFraction is the share of the belief variance two clusters must
+// explain for witnesses to count as split in two
+// evenly spread beliefs leave about a quarter unexplained
+const BimodalFraction = 0.8
+
+// maxClusterRounds bounds the reassignment rounds of the two way split
+const maxClusterRounds = 10
+
+// WithVarianceDetection also assesses reports by belief variance whenever
+// the dominant vote finds no confirmed partition, see AssessVariance
+// threshold is the variance that counts, 0 disables it (the default)
+func (d *Detector) WithVarianceDetection(threshold float64) *Detector {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.varianceThreshold = max(threshold, 0)
+	return d
+}
+
+// varianceDetection returns the configured threshold, 0 when disabled
+func (d *Detector) varianceDetection() float64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.varianceThreshold
+}
+
+// AssessVariance looks for a split in the full belief vectors instead of
+// dominant votes, so confident splits near the dominance margins are not
+// read as unknown or weak votes
+// variance is the mean squared Distance from the mean belief, witnesses are
+// clustered in two around the most distant pair of beliefs
+// the partition is confirmed when variance exceeds the threshold, the two
+// clusters explain BimodalFraction of it, their centres lean opposite ways
+// (one alive, one dead, unknown mass is abstention) and the smaller cluster is large
+// enough to be a split rather than outliers (the adaptive disagreement
+// threshold), high variance without that is only suspected
+// uses DefaultVarianceThreshold unless WithVarianceDetection set another
+// does not touch detector state
+func (d *Detector) AssessVariance(reports []witness.WitnessReport, target types.NodeID) (PartitionState, *SplitReality) {
+	if len(reports) < 2 {
+		return NoPartition, nil
+	}
+	threshold := d.varianceDetection()
+	if threshold == 0 {
+		threshold = DefaultVarianceThreshold
+	}
+
+	beliefs := make([]types.Belief, len(reports))
+	for i, r := range reports {
+		beliefs[i] = r.Belief
+	}
+	n := float64(len(beliefs))
+	mean := meanBelief(beliefs)
+	variance := spread(beliefs, func(int) types.Belief { return mean }) / n
+	if variance <= threshold {
+		return NoPartition, nil
+	}
+
+	first, centres := splitTwo(beliefs, mean)
+	within := spread(beliefs, func(i int) types.Belief {
+		if first[i] {
+			return centres[0]
+		}
+		return centres[1]
+	}) / n
+	if 1-within/variance < BimodalFraction {
+		return SuspectedPartition, nil
+	}
+	// confident witnesses beside abstaining or hesitant ones on the same
+	// side are two camps but not a split
+	if lean(centres[0]) == types.StateUnknown || lean(centres[0]) == lean(centres[1]) {
+		return NoPartition, nil
+	}
+
+	groups := [2]WitnessGroup{
+		{Witnesses: make([]types.NodeID, 0), Beliefs: map[types.NodeID]types.Belief{target: centres[0]}},
+		{Witnesses: make([]types.NodeID, 0), Beliefs: map[types.NodeID]types.Belief{target: centres[1]}},
+	}
+	for i, r := range reports {
+		g := 1
+		if first[i] {
+			g = 0
+		}
+		groups[g].Witnesses = append(groups[g].Witnesses, r.Witness)
+	}
+
+	minority := float64(min(len(groups[0].Witnesses), len(groups[1].Witnesses))) / n
+	if minority <= witness.AdaptiveThreshold(d.disagreementThreshold, len(reports)) {
+		return SuspectedPartition, nil
+	}
+	return ConfirmedPartition, &SplitReality{
+		Groups:       groups[:],
+		Disagreement: minority,
+		Ambiguous:    []types.NodeID{target},
+	}
+}
+
+// lean returns whether b leans alive or dead by DominantMargin, ignoring
+// unknown mass so beliefs near the dominance margin still take a side,
+// StateUnknown when it leans neither way
+func lean(b types.Belief) types.BeliefState {
+	alive, dead := b.Alive().Value(), b.Dead().Value()
+	switch {
+	case alive > dead+types.DominantMargin:
+		return types.StateAlive
+	case dead > alive+types.DominantMargin:
+		return types.StateDead
+	}
+	return types.StateUnknown
+}
+
+// meanBelief averages beliefs component wise
+func meanBelief(beliefs []types.Belief) types.Belief {
+	mean := beliefs[0]
+	for i, b := range beliefs[1:] {
+		mean = mean.Interpolate(b, 1/float64(i+2))
+	}
+	return mean
+}
+
+// spread sums the squared Distance of each belief from its centre
+func spread(beliefs []types.Belief, centre func(i int) types.Belief) float64 {
+	var sum float64
+	for i, b := range beliefs {
+		dist := b.Distance(centre(i))
+		sum += dist * dist
+	}
+	return sum
+}
+
+// splitTwo clusters beliefs in two, seeded with the belief farthest from the
+// mean and the belief farthest from that one
+// returns whether each belief is in the first cluster and both centres
+func splitTwo(beliefs []types.Belief, mean types.Belief) ([]bool, [2]types.Belief) {
+	a := farthest(beliefs, mean)
+	centres := [2]types.Belief{beliefs[a], beliefs[farthest(beliefs, beliefs[a])]}
+
+	first := make([]bool, len(beliefs))
+	for round := 0; round < maxClusterRounds; round++ {
+		changed := round == 0
+		var members [2][]types.Belief
+		for i, b := range beliefs {
+			in := b.Distance(centres[0]) <= b.Distance(centres[1])
+			changed = changed || in != first[i]
+			first[i] = in
+			if in {
+				members[0] = append(members[0], b)
+			} else {
+				members[1] = append(members[1], b)
+			}
+		}
+		if len(members[0]) == 0 || len(members[1]) == 0 {
+			break
+		}
+		centres = [2]types.Belief{meanBelief(members[0]), meanBelief(members[1])}
+		if !changed {
+			break
+		}
+	}
+	return first, centres
+}
+
+// farthest returns the index of the belief most distant from from
+func farthest(beliefs []types.Belief, from types.Belief) int {
+	best, bestDist := 0, -1.0
+	for i, b := range beliefs {
+		if dist := b.Distance(from); dist > bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
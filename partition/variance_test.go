@@ -0,0 +1,109 @@
+package partition
+
+import (
+	"testing"
+
+	"github.com/styx-oracle/styx/types"
+	"github.com/styx-oracle/styx/witness"
+)
+
+// campReports builds n reports per camp, every report in a camp the same
+func campReports(n int, camps ...types.Belief) []witness.WitnessReport {
+	target := types.NewNodeID(100)
+	var reports []witness.WitnessReport
+	for c, b := range camps {
+		for i := 0; i < n; i++ {
+			id := types.NewNodeID(uint64(c*100 + i + 1))
+			reports = append(reports, witness.WitnessReport{Witness: id, Target: target, Belief: b})
+		}
+	}
+	return reports
+}
+
+func TestVarianceCatchesSplitsVotingMisses(t *testing.T) {
+	target := types.NewNodeID(100)
+	cases := []struct {
+		name    string
+		reports []witness.WitnessReport
+		vote    PartitionState
+	}{
+		// each camp is within the dominance margin of unknown, so every vote is unknown
+		{"near margin", campReports(3, types.MustBelief(0.5, 0.05, 0.45), types.MustBelief(0.05, 0.5, 0.45)), SuspectedPartition},
+		// dominant votes, but too weak for the opinion weighted disagreement
+		{"moderate opinions", campReports(3, types.MustBelief(0.55, 0.25, 0.2), types.MustBelief(0.25, 0.55, 0.2)), SuspectedPartition},
+	}
+	for _, tc := range cases {
+		if state, _ := NewDetector().Assess(tc.reports, target); state != tc.vote {
+			t.Errorf("%s: vote found %s, want %s", tc.name, state, tc.vote)
+		}
+
+		state, split := NewDetector().AssessVariance(tc.reports, target)
+		if state != ConfirmedPartition {
+			t.Fatalf("%s: variance found %s, want CONFIRMED_PARTITION", tc.name, state)
+		}
+		if len(split.Groups) != 2 || len(split.Groups[0].Witnesses) != 3 || len(split.Groups[1].Witnesses) != 3 {
+			t.Errorf("%s: groups %+v, want the two camps", tc.name, split.Groups)
+		}
+		if split.Groups[0].Beliefs[target].Distance(tc.reports[0].Belief) > 1e-9 {
+			t.Errorf("%s: first group centred on %v, want %v", tc.name, split.Groups[0].Beliefs[target], tc.reports[0].Belief)
+		}
+
+		d := NewDetector().WithVarianceDetection(DefaultVarianceThreshold)
+		if state, _ := d.Analyze(tc.reports, target); state != ConfirmedPartition || !d.ShouldRefuseAnswer(target) {
+			t.Errorf("%s: with variance detection analyzed as %s", tc.name, state)
+		}
+	}
+}
+
+func TestVarianceIgnoresAgreementAndSpread(t *testing.T) {
+	target := types.NewNodeID(100)
+
+	// the weak split TestAnalyzeWeighsOpinionStrength keeps answering
+	weak := splitReports(types.MustBelief(0.5, 0.38, 0.12), types.MustBelief(0.38, 0.5, 0.12))
+	if state, _ := NewDetector().AssessVariance(weak, target); state != NoPartition {
+		t.Errorf("weak split: got %s, want NO_PARTITION", state)
+	}
+	if state, _ := NewDetector().AssessVariance(agreeingReports(), target); state != NoPartition {
+		t.Errorf("agreement: got %s, want NO_PARTITION", state)
+	}
+
+	// beliefs spread evenly from alive to dead vary a lot but are not two camps
+	var spread []witness.WitnessReport
+	for i := 0; i <= 10; i++ {
+		alive := 0.9 - 0.085*float64(i)
+		spread = append(spread, witness.WitnessReport{
+			Witness: types.NewNodeID(uint64(i + 1)),
+			Target:  target,
+			Belief:  types.MustBelief(alive, 0.95-alive, 0.05),
+		})
+	}
+	if state, _ := NewDetector().AssessVariance(spread, target); state != SuspectedPartition {
+		t.Errorf("even spread: got %s, want SUSPECTED_PARTITION", state)
+	}
+
+	// one confident dissenter among many is an outlier, not a split
+	outlier := campReports(9, types.MustBelief(0.9, 0.05, 0.05))
+	outlier = append(outlier, witness.WitnessReport{Witness: types.NewNodeID(999), Target: target, Belief: types.MustBelief(0.05, 0.9, 0.05)})
+	if state, _ := NewDetector().AssessVariance(outlier, target); state != SuspectedPartition {
+		t.Errorf("single outlier: got %s, want SUSPECTED_PARTITION", state)
+	}
+
+	// abstaining or hesitant witnesses beside confident ones are not a split,
+	// the camps must lean opposite ways
+	confident := types.MustBelief(0.9, 0.05, 0.05)
+	for name, other := range map[string]types.Belief{
+		"abstention": types.UnknownBelief(),
+		"hesitation": types.MustBelief(0.4, 0.1, 0.5),
+	} {
+		if state, split := NewDetector().AssessVariance(campReports(3, confident, other), target); state != NoPartition || split != nil {
+			t.Errorf("%s: got %s, want NO_PARTITION", name, state)
+		}
+	}
+
+	// a threshold above the split's variance leaves the vote to decide
+	near := campReports(3, types.MustBelief(0.5, 0.05, 0.45), types.MustBelief(0.05, 0.5, 0.45))
+	d := NewDetector().WithVarianceDetection(0.5)
+	if state, _ := d.Analyze(near, target); state == ConfirmedPartition {
+		t.Error("a variance threshold above the split's variance still confirmed it")
+	}
+}
@@ -15,8 +15,13 @@ type LocalBelief struct {
 	belief      types.Belief
 	evidence    *evidence.EvidenceSet
 	lastUpdated styxtime.LogicalTimestamp
+	history     []float64 // alive confidence after each recomputation, oldest first
+	dirty       bool      // evidence added since belief was computed
 }
 
+// BeliefHistorySize bounds the alive confidence snapshots kept per target.
+const BeliefHistorySize = 64
+
 // NewLocalBelief creates a new LocalBelief for a target node.
 // Starts with pure uncertainty and no evidence.
 func NewLocalBelief(target types.NodeID) *LocalBelief {
@@ -34,10 +39,22 @@ func (lb *LocalBelief) Target() types.NodeID {
 }
 
 // Belief returns the current belief distribution.
+// Evidence recorded since the last call is folded in first.
 func (lb *LocalBelief) Belief() types.Belief {
+	if lb.dirty {
+		lb.ForceRecompute()
+	}
 	return lb.belief
 }
 
+// ForceRecompute recomputes the belief from the evidence now, for callers
+// that want the cost paid at write time rather than on the next read.
+func (lb *LocalBelief) ForceRecompute() {
+	lb.belief = lb.evidence.ComputeBelief(lb.lastUpdated)
+	lb.dirty = false
+	lb.snapshot()
+}
+
 // Evidence returns the evidence set.
 func (lb *LocalBelief) Evidence() *evidence.EvidenceSet {
 	return lb.evidence
@@ -49,35 +66,65 @@ func (lb *LocalBelief) LastUpdated() styxtime.LogicalTimestamp {
 }
 
 // RecordEvidence adds new evidence and recomputes the belief.
+// Each call takes one alive history snapshot.
 func (lb *LocalBelief) RecordEvidence(e evidence.Evidence) types.Belief {
+	lb.AddEvidence(e)
+	lb.ForceRecompute()
+	return lb.belief
+}
+
+// AddEvidence adds new evidence without recomputing the belief.
+// The belief is recomputed on the next read, so a burst of evidence costs
+// one recomputation instead of one per item, and takes one alive history
+// snapshot for the whole burst.
+func (lb *LocalBelief) AddEvidence(e evidence.Evidence) {
 	if e.Timestamp > lb.lastUpdated {
 		lb.lastUpdated = e.Timestamp
 	}
 	lb.evidence.Add(e)
-	lb.belief = lb.evidence.ComputeBelief(lb.lastUpdated)
-	return lb.belief
+	lb.dirty = true
+}
+
+// snapshot appends the current alive confidence to the bounded history.
+func (lb *LocalBelief) snapshot() {
+	if len(lb.history) >= BeliefHistorySize {
+		lb.history = append(lb.history[:0], lb.history[1:]...)
+	}
+	lb.history = append(lb.history, lb.belief.Alive().Value())
+}
+
+// AliveHistory returns the most recent alive confidence snapshots, oldest
+// first, at most window of them. RecordEvidence takes a snapshot per
+// evidence item, AddEvidence one per burst.
+func (lb *LocalBelief) AliveHistory(window int) []float64 {
+	lb.Belief()
+	if window <= 0 || window > len(lb.history) {
+		window = len(lb.history)
+	}
+	return append([]float64(nil), lb.history[len(lb.history)-window:]...)
 }
 
 // RecomputeAt recomputes the belief at a given time (for decay).
 func (lb *LocalBelief) RecomputeAt(now styxtime.LogicalTimestamp) {
 	lb.belief = lb.evidence.ComputeBelief(now)
 	lb.lastUpdated = now
+	lb.dirty = false
 }
 
 // IsCertainAlive checks if we're certain the target is alive.
 func (lb *LocalBelief) IsCertainAlive() bool {
-	return lb.belief.IsCertainAlive()
+	return lb.Belief().IsCertainAlive()
 }
 
 // IsCertainDead checks if we're certain the target is dead.
 func (lb *LocalBelief) IsCertainDead() bool {
-	return lb.belief.IsCertainDead()
+	return lb.Belief().IsCertainDead()
 }
 
 // Reasoning returns a summary of why we believe what we believe.
 func (lb *LocalBelief) Reasoning() BeliefReasoning {
 	return BeliefReasoning{
-		Belief:             lb.belief,
+		Belief:             lb.Belief(),
 		EvidenceCount:      lb.evidence.Len(),
 		AliveEvidenceCount: len(lb.evidence.AliveEvidence()),
 		DeadEvidenceCount:  len(lb.evidence.DeadEvidence()),
@@ -87,7 +134,7 @@ func (lb *LocalBelief) Reasoning() BeliefReasoning {
 
 func (lb *LocalBelief) String() string {
 	return fmt.Sprintf("LocalBelief(%s → %s, %d evidence)",
-		lb.target, lb.belief, lb.evidence.Len())
+		lb.target, lb.Belief(), lb.evidence.Len())
 }
 
 // BeliefReasoning summarizes why we hold a particular belief.
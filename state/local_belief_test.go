@@ -0,0 +1,56 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/styx-oracle/styx/evidence"
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+)
+
+func TestLocalBeliefRecomputesLazily(t *testing.T) {
+	self, target := types.NewNodeID(1), types.NewNodeID(2)
+	lazy, eager := NewLocalBelief(target), NewLocalBelief(target)
+
+	for i := range 10 {
+		e := evidence.NewDirectResponse(styxtime.LogicalTimestamp(i+1), 10, self, target)
+		lazy.AddEvidence(e)
+		eager.RecordEvidence(e)
+	}
+	if len(lazy.history) != 0 {
+		t.Errorf("lazy belief recomputed %d times before a read", len(lazy.history))
+	}
+	if !lazy.Belief().Equal(eager.Belief()) {
+		t.Errorf("lazy %v, eager %v", lazy.Belief(), eager.Belief())
+	}
+	if len(lazy.AliveHistory(0)) != 1 || len(eager.AliveHistory(0)) != 10 {
+		t.Errorf("history lazy %d, eager %d snapshots", len(lazy.AliveHistory(0)), len(eager.AliveHistory(0)))
+	}
+}
+
+const recomputeEvidence = 1000
+
+// benchmarkWriteThenRead records recomputeEvidence items and reads once
+func benchmarkWriteThenRead(b *testing.B, eager bool) {
+	self, target := types.NewNodeID(1), types.NewNodeID(2)
+	items := make([]evidence.Evidence, recomputeEvidence)
+	for i := range items {
+		items[i] = evidence.NewDirectResponse(styxtime.LogicalTimestamp(i+1), 10, self, target)
+	}
+
+	b.ResetTimer()
+	for range b.N {
+		lb := NewLocalBelief(target)
+		for _, e := range items {
+			if eager {
+				lb.RecordEvidence(e)
+			} else {
+				lb.AddEvidence(e)
+			}
+		}
+		lb.Belief()
+	}
+}
+
+func BenchmarkWriteThenReadEager(b *testing.B) { benchmarkWriteThenRead(b, true) }
+func BenchmarkWriteThenReadLazy(b *testing.B)  { benchmarkWriteThenRead(b, false) }
@@ -0,0 +1,174 @@
+package state
+
+import (
+	"github.com/styx-oracle/styx/evidence"
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+)
+
+// MergeObserverStates combines the views of several observers running on
+// the same node (e.g. probers on different network paths) into one belief
+// per target.
+//
+// Beliefs are not averaged. Instead the evidence behind them is pooled and
+// the belief recomputed with EvidenceSet.ComputeBelief, so agreeing
+// observers reinforce each other and disagreeing ones widen unknown
+// (Property 9) rather than cancelling out. Each observer has its own
+// logical clock, so its evidence is first shifted to line its clock up
+// with the latest one; relative ages, and hence decay, are preserved.
+//
+// Targets known to a single observer keep that observer's belief.
+// Nil states are skipped. The states are only read, but like any
+// ObserverState they must not be modified concurrently.
+func MergeObserverStates(states ...*ObserverState) map[types.NodeID]types.Belief {
+	now := styxtime.Zero()
+	for _, os := range states {
+		if os != nil && os.logicalClock > now {
+			now = os.logicalClock
+		}
+	}
+
+	pooled := make(map[types.NodeID]*evidence.EvidenceSet)
+	for _, os := range states {
+		if os == nil {
+			continue
+		}
+		shift := now - os.logicalClock
+		for target, lb := range os.beliefs {
+			set := pooled[target]
+			if set == nil {
+				set = evidence.NewEvidenceSet()
+				pooled[target] = set
+			}
+			for _, e := range lb.evidence.All() {
+				e.Timestamp += shift
+				set.Add(e)
+			}
+		}
+	}
+
+	merged := make(map[types.NodeID]types.Belief, len(pooled))
+	for target, set := range pooled {
+		merged[target] = set.ComputeBeliefNow()
+	}
+	return merged
+}
+
+// MergeStats summarizes what ObserverState.MergeFrom changed.
+type MergeStats struct {
+	// NewEvidenceAdded counts remote evidence the local state did not have.
+	NewEvidenceAdded int
+	// ConflictsDetected counts evidence both sides had with different weights.
+	ConflictsDetected int
+	// BeliefChanges counts targets whose belief changed, one event each
+	// in Events.
+	BeliefChanges int
+	Events        []BeliefChangeEvent
+}
+
+// BeliefChangeEvent records a belief changed by a merge.
+type BeliefChangeEvent struct {
+	Target types.NodeID
+	Old    types.Belief
+	New    types.Belief
+	At     styxtime.LogicalTimestamp
+}
+
+// evidenceKey identifies one observation across observers.
+type evidenceKey struct {
+	source types.NodeID
+	kind   evidence.EvidenceKind
+	ts     styxtime.LogicalTimestamp
+}
+
+func keyOf(e evidence.Evidence) evidenceKey {
+	return evidenceKey{source: e.Source, kind: e.Kind, ts: e.Timestamp}
+}
+
+// MergeFrom imports the evidence of a remote observer when two observers
+// meet, so both end up reasoning from the same observations.
+//
+// Evidence is identified by Source, Kind and Timestamp. Remote evidence
+// missing locally is added; when both sides hold the same observation with
+// different weights the higher weight is kept. Timestamps are taken as they
+// are, observers exchanging evidence are expected to share a Lamport clock,
+// and the local clock is advanced past the remote one as on any receive.
+//
+// Beliefs of affected targets are recomputed and every target whose belief
+// changed is reported in the returned stats. Merging is idempotent: merging
+// the same remote again adds nothing. A nil remote is a no-op.
+func (os *ObserverState) MergeFrom(remote *ObserverState) MergeStats {
+	var stats MergeStats
+	if remote == nil || remote == os {
+		return stats
+	}
+	os.Receive(remote.logicalClock)
+
+	targets := make([]types.NodeID, 0, len(remote.beliefs))
+	for target := range remote.beliefs {
+		targets = append(targets, target)
+	}
+	types.SortNodeIDs(targets)
+
+	for _, target := range targets {
+		lb, ok := os.beliefs[target]
+		if !ok {
+			lb = NewLocalBelief(target)
+		}
+		merged, added, conflicts := mergeEvidence(lb.evidence.All(), remote.beliefs[target].evidence.All())
+		stats.NewEvidenceAdded += added
+		stats.ConflictsDetected += conflicts
+		if added == 0 && conflicts == 0 {
+			continue
+		}
+		os.beliefs[target] = lb
+
+		old := lb.Belief()
+		lb.evidence = evidence.NewEvidenceSet()
+		for _, e := range merged {
+			lb.evidence.Add(e)
+		}
+		if latest := lb.evidence.LatestTimestamp(); latest > lb.lastUpdated {
+			lb.lastUpdated = latest
+		}
+		lb.ForceRecompute()
+
+		if !lb.belief.Equal(old) {
+			stats.BeliefChanges++
+			stats.Events = append(stats.Events, BeliefChangeEvent{
+				Target: target,
+				Old:    old,
+				New:    lb.belief,
+				At:     os.logicalClock,
+			})
+		}
+	}
+	return stats
+}
+
+// mergeEvidence returns local plus the remote evidence it lacks, with the
+// higher weight winning where both hold an observation. Conflicts only
+// count where the weights differ.
+func mergeEvidence(local, remote []evidence.Evidence) (merged []evidence.Evidence, added, conflicts int) {
+	merged = append([]evidence.Evidence(nil), local...)
+	index := make(map[evidenceKey]int, len(merged))
+	for i, e := range merged {
+		index[keyOf(e)] = i
+	}
+	for _, e := range remote {
+		i, ok := index[keyOf(e)]
+		if !ok {
+			index[keyOf(e)] = len(merged)
+			merged = append(merged, e)
+			added++
+			continue
+		}
+		if e.Weight != merged[i].Weight {
+			conflicts++
+			if e.Weight > merged[i].Weight {
+				merged[i] = e
+			}
+		}
+	}
+	return merged, added, conflicts
+}
@@ -0,0 +1,130 @@
+package state
+
+import (
+	"math"
+	"testing"
+
+	"github.com/styx-oracle/styx/evidence"
+	"github.com/styx-oracle/styx/types"
+)
+
+func TestMergeObserverStatesAgreeing(t *testing.T) {
+	target := types.NewNodeID(9)
+	a := NewObserverState(types.NewNodeID(1))
+	b := NewObserverState(types.NewNodeID(2))
+	for i := 0; i < 2; i++ {
+		a.RecordEvidence(target, evidence.NewDirectResponse(a.Tick(), 10, a.SelfID(), target))
+		b.RecordEvidence(target, evidence.NewDirectResponse(b.Tick(), 10, b.SelfID(), target))
+	}
+	// b has been running longer; its clock must not age a's evidence
+	for i := 0; i < 50; i++ {
+		b.Tick()
+	}
+
+	single := a.Query(target).Belief
+	merged := MergeObserverStates(a, b)[target]
+	if merged.Alive().Value() <= single.Alive().Value() {
+		t.Errorf("agreeing observers should reinforce: single %v, merged %v", single, merged)
+	}
+}
+
+func TestMergeObserverStatesDisagreeing(t *testing.T) {
+	target, onlyA := types.NewNodeID(9), types.NewNodeID(10)
+	a := NewObserverState(types.NewNodeID(1))
+	b := NewObserverState(types.NewNodeID(2))
+	for i := 0; i < 3; i++ {
+		a.RecordEvidence(target, evidence.NewDirectResponse(a.Tick(), 10, a.SelfID(), target))
+	}
+	for i := 0; i < 10; i++ {
+		b.RecordEvidence(target, evidence.NewTimeout(b.Tick(), 100, 1000, b.SelfID(), target))
+	}
+	a.RecordEvidence(onlyA, evidence.NewDirectResponse(a.Tick(), 10, a.SelfID(), onlyA))
+
+	merged := MergeObserverStates(a, nil, b)
+	got := merged[target]
+	for _, side := range []types.Belief{a.Query(target).Belief, b.Query(target).Belief} {
+		if got.Unknown().Value() <= side.Unknown().Value() {
+			t.Errorf("disagreement should widen unknown: side %v, merged %v", side, got)
+		}
+	}
+
+	if want := a.Query(onlyA).Belief; !merged[onlyA].Equal(want) {
+		t.Errorf("target seen by one observer: got %v, want %v", merged[onlyA], want)
+	}
+}
+
+// entropy is the Shannon entropy of a belief in bits.
+func entropy(b types.Belief) float64 {
+	var h float64
+	for _, p := range []float64{b.Alive().Value(), b.Dead().Value(), b.Unknown().Value()} {
+		if p > 0 {
+			h -= p * math.Log2(p)
+		}
+	}
+	return h
+}
+
+func TestMergeFromDisjointEvidence(t *testing.T) {
+	target := types.NewNodeID(9)
+	a := NewObserverState(types.NewNodeID(1))
+	b := NewObserverState(types.NewNodeID(2))
+	for i := 0; i < 2; i++ {
+		a.RecordEvidence(target, evidence.NewDirectResponse(a.Tick(), 10, a.SelfID(), target))
+		b.RecordEvidence(target, evidence.NewDirectResponse(b.Tick(), 10, b.SelfID(), target))
+	}
+	aloneA, aloneB := a.Query(target).Belief, b.Query(target).Belief
+
+	stats := a.MergeFrom(b)
+	if stats.NewEvidenceAdded != 2 || stats.ConflictsDetected != 0 || stats.BeliefChanges != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if len(stats.Events) != 1 || stats.Events[0].Target != target || !stats.Events[0].Old.Equal(aloneA) {
+		t.Errorf("events = %+v", stats.Events)
+	}
+
+	merged := a.Query(target).Belief
+	for _, alone := range []types.Belief{aloneA, aloneB} {
+		if entropy(merged) >= entropy(alone) {
+			t.Errorf("merged entropy %f not below single observer %f", entropy(merged), entropy(alone))
+		}
+	}
+	if a.LogicalTime() <= b.LogicalTime() {
+		t.Errorf("local clock %s not advanced past remote %s", a.LogicalTime(), b.LogicalTime())
+	}
+
+	if again := a.MergeFrom(b); again.NewEvidenceAdded != 0 || again.BeliefChanges != 0 {
+		t.Errorf("merging twice changed state: %+v", again)
+	}
+}
+
+func TestMergeFromConflictKeepsHigherWeight(t *testing.T) {
+	target := types.NewNodeID(9)
+	source := types.NewNodeID(5)
+	a := NewObserverState(types.NewNodeID(1))
+	b := NewObserverState(types.NewNodeID(2))
+
+	// the same observation, relayed to both with different weights
+	slow := evidence.NewDirectResponse(3, 2000, source, target)
+	fast := slow
+	fast.Weight = 1.0
+	a.RecordEvidence(target, slow)
+	b.RecordEvidence(target, fast)
+
+	// the lower weight never overrides the higher one
+	if stats := b.MergeFrom(a); stats.ConflictsDetected != 1 || stats.BeliefChanges != 0 {
+		t.Errorf("lower weight: stats = %+v", stats)
+	}
+
+	stats := a.MergeFrom(b)
+	if stats.ConflictsDetected != 1 || stats.NewEvidenceAdded != 0 || stats.BeliefChanges != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	all := a.Query(target).Reasoning
+	if all.EvidenceCount != 1 {
+		t.Fatalf("conflict duplicated evidence: %d records", all.EvidenceCount)
+	}
+	if !a.Query(target).Belief.Equal(b.Query(target).Belief) {
+		t.Errorf("higher weight not kept: got %v, want %v", a.Query(target).Belief, b.Query(target).Belief)
+	}
+
+}
@@ -44,14 +44,78 @@ func (os *ObserverState) Receive(receivedTS styxtime.LogicalTimestamp) styxtime.
 	return os.logicalClock.Update(receivedTS)
 }
 
-// RecordEvidence records evidence about a target node.
+// RecordEvidence records evidence about a target node and returns the
+// updated belief.
+// Reading the belief takes a trend snapshot, see AddEvidence.
 func (os *ObserverState) RecordEvidence(target types.NodeID, e evidence.Evidence) types.Belief {
+	os.AddEvidence(target, e)
+	return os.beliefs[target].Belief()
+}
+
+// AddEvidence records evidence about a target node without recomputing the
+// belief, for callers that do not need it yet.
+// The belief is recomputed on the next read, and a burst of evidence
+// between reads is one BeliefTrend snapshot.
+func (os *ObserverState) AddEvidence(target types.NodeID, e evidence.Evidence) {
 	lb, ok := os.beliefs[target]
 	if !ok {
 		lb = NewLocalBelief(target)
 		os.beliefs[target] = lb
 	}
-	return lb.RecordEvidence(e)
+	lb.AddEvidence(e)
+}
+
+// DecreasingRun is how many consecutive declines IsDecreasing requires.
+const DecreasingRun = 5
+
+// BeliefTrend returns the least-squares slope of alive confidence over the
+// last window belief snapshots for target, per snapshot.
+// A snapshot is taken whenever the belief is read after new evidence
+// (RecordEvidence, Query, probes), so it follows what callers observed.
+// A negative slope means confidence in liveness is declining.
+// Returns 0 with fewer than two snapshots.
+func (os *ObserverState) BeliefTrend(target types.NodeID, window int) float64 {
+	lb, ok := os.beliefs[target]
+	if !ok {
+		return 0
+	}
+	return slope(lb.AliveHistory(window))
+}
+
+// IsDecreasing returns true if alive confidence fell with each of the last
+// DecreasingRun belief snapshots.
+func (os *ObserverState) IsDecreasing(target types.NodeID) bool {
+	lb, ok := os.beliefs[target]
+	if !ok {
+		return false
+	}
+	h := lb.AliveHistory(DecreasingRun + 1)
+	if len(h) < DecreasingRun+1 {
+		return false
+	}
+	for i := 1; i < len(h); i++ {
+		if h[i] >= h[i-1] {
+			return false
+		}
+	}
+	return true
+}
+
+// slope fits y against its index by least squares.
+func slope(ys []float64) float64 {
+	n := float64(len(ys))
+	if len(ys) < 2 {
+		return 0
+	}
+	var sumX, sumY, sumXY, sumXX float64
+	for i, y := range ys {
+		x := float64(i)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	return (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
 }
 
 // Query returns the belief about a specific node.
@@ -0,0 +1,78 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/styx-oracle/styx/evidence"
+	"github.com/styx-oracle/styx/types"
+)
+
+func TestBeliefTrend(t *testing.T) {
+	os := NewObserverState(types.NewNodeID(1))
+	self, target := types.NewNodeID(1), types.NewNodeID(2)
+
+	// Establish liveness first, then 10 timeouts erode it
+	for i := 0; i < 3; i++ {
+		os.RecordEvidence(target, evidence.NewDirectResponse(os.Tick(), 10, self, target))
+	}
+	for i := 0; i < 10; i++ {
+		os.RecordEvidence(target, evidence.NewTimeout(os.Tick(), 100, 1000, self, target))
+	}
+
+	if s := os.BeliefTrend(target, 10); s >= 0 {
+		t.Errorf("slope after timeouts = %f, want negative", s)
+	}
+	if !os.IsDecreasing(target) {
+		t.Error("IsDecreasing false during steady decline")
+	}
+
+	for i := 0; i < 5; i++ {
+		os.RecordEvidence(target, evidence.NewDirectResponse(os.Tick(), 10, self, target))
+	}
+
+	if s := os.BeliefTrend(target, 5); s <= 0 {
+		t.Errorf("slope after recovery = %f, want positive", s)
+	}
+	if os.IsDecreasing(target) {
+		t.Error("IsDecreasing true after recovery")
+	}
+}
+
+func TestBeliefTrendWithoutHistory(t *testing.T) {
+	os := NewObserverState(types.NewNodeID(1))
+	if s := os.BeliefTrend(types.NewNodeID(2), 10); s != 0 {
+		t.Errorf("unknown target slope = %f, want 0", s)
+	}
+}
+
+func TestAddEvidenceSnapshotsOnRead(t *testing.T) {
+	lazy, eager := NewObserverState(types.NewNodeID(1)), NewObserverState(types.NewNodeID(1))
+	self, target := types.NewNodeID(1), types.NewNodeID(2)
+
+	for i := 0; i < 10; i++ {
+		lazy.AddEvidence(target, evidence.NewDirectResponse(lazy.Tick(), 10, self, target))
+		eager.RecordEvidence(target, evidence.NewDirectResponse(eager.Tick(), 10, self, target))
+	}
+	if n := len(lazy.beliefs[target].history); n != 0 {
+		t.Fatalf("AddEvidence recomputed %d times before a read", n)
+	}
+
+	// the read folds the burst in as one snapshot
+	if got, want := lazy.QueryOrUnknown(target).Belief, eager.QueryOrUnknown(target).Belief; !got.Equal(want) {
+		t.Errorf("lazy %v, eager %v", got, want)
+	}
+	if n := len(lazy.beliefs[target].history); n != 1 {
+		t.Errorf("burst read as %d snapshots, want 1", n)
+	}
+
+	// the trend reads the timeouts added since as the next snapshot
+	for i := 0; i < 5; i++ {
+		lazy.AddEvidence(target, evidence.NewTimeout(lazy.Tick(), 100, 1000, self, target))
+	}
+	if s := lazy.BeliefTrend(target, 10); s >= 0 {
+		t.Errorf("slope after timeouts = %f, want negative", s)
+	}
+	if n := len(lazy.beliefs[target].history); n != 2 {
+		t.Errorf("%d snapshots after two reads, want 2", n)
+	}
+}
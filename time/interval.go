@@ -0,0 +1,36 @@
+package time
+
+import "fmt"
+
+// LogicalInterval is a closed range of logical time [Start, End].
+//
+// Both ends are included, so an interval with Start == End holds exactly one
+// timestamp. An interval with End before Start is empty.
+type LogicalInterval struct {
+	Start LogicalTimestamp
+	End   LogicalTimestamp
+}
+
+// IsEmpty reports whether the interval holds no timestamps.
+func (i LogicalInterval) IsEmpty() bool {
+	return i.End < i.Start
+}
+
+// Contains reports whether ts lies within the interval.
+func (i LogicalInterval) Contains(ts LogicalTimestamp) bool {
+	return i.Start <= ts && ts <= i.End
+}
+
+// Overlaps reports whether the two intervals share at least one timestamp.
+// Empty intervals overlap nothing.
+func (i LogicalInterval) Overlaps(other LogicalInterval) bool {
+	if i.IsEmpty() || other.IsEmpty() {
+		return false
+	}
+	return i.Start <= other.End && other.Start <= i.End
+}
+
+// String returns a human-readable representation.
+func (i LogicalInterval) String() string {
+	return fmt.Sprintf("[%s, %s]", i.Start, i.End)
+}
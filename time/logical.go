@@ -4,7 +4,10 @@
 // Wall clocks lie. Causality doesn't.
 package time
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 // LogicalTimestamp is a Lamport-style logical timestamp.
 //
@@ -73,6 +76,48 @@ func (t LogicalTimestamp) AgeSince(now LogicalTimestamp) uint64 {
 	return uint64(now - t)
 }
 
+// Add returns the timestamp delta units later.
+// Saturates at the largest timestamp instead of wrapping, so a later
+// timestamp is never before an earlier one.
+func (t LogicalTimestamp) Add(delta uint64) LogicalTimestamp {
+	if delta > math.MaxUint64-uint64(t) {
+		return LogicalTimestamp(math.MaxUint64)
+	}
+	return t + LogicalTimestamp(delta)
+}
+
+// Subtract returns the timestamp delta units earlier, flooring at Zero.
+func (t LogicalTimestamp) Subtract(delta uint64) LogicalTimestamp {
+	if delta > uint64(t) {
+		return Zero()
+	}
+	return t - LogicalTimestamp(delta)
+}
+
+// Diff returns the absolute distance between two timestamps.
+// Unlike AgeSince it does not care which one is earlier.
+func (t LogicalTimestamp) Diff(other LogicalTimestamp) uint64 {
+	if t < other {
+		return uint64(other - t)
+	}
+	return uint64(t - other)
+}
+
+// Clamp limits the timestamp to [min, max].
+// Swapped bounds are put back in order.
+func (t LogicalTimestamp) Clamp(min, max LogicalTimestamp) LogicalTimestamp {
+	if min > max {
+		min, max = max, min
+	}
+	if t < min {
+		return min
+	}
+	if t > max {
+		return max
+	}
+	return t
+}
+
 // String returns a human-readable representation.
 func (t LogicalTimestamp) String() string {
 	return fmt.Sprintf("@%d", t)
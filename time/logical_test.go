@@ -0,0 +1,86 @@
+package time
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAddSubtractAreInverse(t *testing.T) {
+	for _, ts := range []LogicalTimestamp{0, 1, 7, 1000} {
+		for _, delta := range []uint64{0, 1, 5, 999} {
+			if got := ts.Add(delta).Subtract(delta); got != ts {
+				t.Errorf("%s.Add(%d).Subtract(%d) = %s", ts, delta, delta, got)
+			}
+			if uint64(ts) >= delta {
+				if got := ts.Subtract(delta).Add(delta); got != ts {
+					t.Errorf("%s.Subtract(%d).Add(%d) = %s", ts, delta, delta, got)
+				}
+			}
+		}
+	}
+
+	if got := LogicalTimestamp(3).Subtract(10); got != Zero() {
+		t.Errorf("Subtract past zero = %s, want floor at zero", got)
+	}
+	if got := LogicalTimestamp(math.MaxUint64 - 1).Add(10); got != LogicalTimestamp(math.MaxUint64) {
+		t.Errorf("Add past max = %s, want saturation", got)
+	}
+}
+
+func TestDiffIsSymmetric(t *testing.T) {
+	pairs := [][2]LogicalTimestamp{{0, 0}, {3, 10}, {10, 3}, {0, math.MaxUint64}}
+	for _, p := range pairs {
+		a, b := p[0], p[1]
+		if a.Diff(b) != b.Diff(a) {
+			t.Errorf("Diff(%s, %s) = %d, reverse %d", a, b, a.Diff(b), b.Diff(a))
+		}
+	}
+	if d := LogicalTimestamp(3).Diff(10); d != 7 {
+		t.Errorf("Diff = %d, want 7", d)
+	}
+}
+
+func TestClampStaysInRange(t *testing.T) {
+	lo, hi := LogicalTimestamp(5), LogicalTimestamp(10)
+	for ts := LogicalTimestamp(0); ts <= 15; ts++ {
+		got := ts.Clamp(lo, hi)
+		if got < lo || got > hi {
+			t.Errorf("%s.Clamp(%s, %s) = %s", ts, lo, hi, got)
+		}
+		if ts >= lo && ts <= hi && got != ts {
+			t.Errorf("%s.Clamp moved an in range value to %s", ts, got)
+		}
+		if swapped := ts.Clamp(hi, lo); swapped != got {
+			t.Errorf("%s.Clamp with swapped bounds = %s, want %s", ts, swapped, got)
+		}
+	}
+}
+
+func TestLogicalInterval(t *testing.T) {
+	i := LogicalInterval{Start: 5, End: 10}
+	for ts, want := range map[LogicalTimestamp]bool{4: false, 5: true, 7: true, 10: true, 11: false} {
+		if i.Contains(ts) != want {
+			t.Errorf("%s.Contains(%s) = %v", i, ts, !want)
+		}
+	}
+
+	tests := []struct {
+		other LogicalInterval
+		want  bool
+	}{
+		{LogicalInterval{0, 4}, false},
+		{LogicalInterval{0, 5}, true}, // shared endpoint
+		{LogicalInterval{6, 8}, true},
+		{LogicalInterval{10, 20}, true},
+		{LogicalInterval{11, 20}, false},
+		{LogicalInterval{8, 6}, false}, // empty
+	}
+	for _, tt := range tests {
+		if got := i.Overlaps(tt.other); got != tt.want {
+			t.Errorf("%s.Overlaps(%s) = %v, want %v", i, tt.other, got, tt.want)
+		}
+		if got := tt.other.Overlaps(i); got != tt.want {
+			t.Errorf("%s.Overlaps(%s) = %v, want %v", tt.other, i, got, tt.want)
+		}
+	}
+}
@@ -1,9 +1,13 @@
 package types
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"strings"
+	"sync/atomic"
 )
 
 // BeliefState represents the dominant state of a belief distribution.
@@ -29,9 +33,46 @@ func (s BeliefState) String() string {
 	}
 }
 
+// ErrUnknownBeliefState is returned when parsing an unrecognized state name.
+var ErrUnknownBeliefState = errors.New("unknown belief state")
+
+// ParseBeliefState is the inverse of String.
+// Names are matched case-insensitively.
+func ParseBeliefState(s string) (BeliefState, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "ALIVE":
+		return StateAlive, nil
+	case "DEAD":
+		return StateDead, nil
+	case "UNKNOWN":
+		return StateUnknown, nil
+	default:
+		return StateUnknown, fmt.Errorf("%w: %q", ErrUnknownBeliefState, s)
+	}
+}
+
+// MarshalText encodes the state as its String name.
+func (s BeliefState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a state name accepted by ParseBeliefState.
+func (s *BeliefState) UnmarshalText(text []byte) error {
+	state, err := ParseBeliefState(string(text))
+	if err != nil {
+		return err
+	}
+	*s = state
+	return nil
+}
+
 // Belief errors
 var (
-	ErrBeliefInvalidSum = errors.New("belief values must sum to 1.0")
+	ErrBeliefInvalidSum    = errors.New("belief values must sum to 1.0")
+	ErrAliveOutOfRange     = errors.New("alive confidence out of range")
+	ErrDeadOutOfRange      = errors.New("dead confidence out of range")
+	ErrUnknownOutOfRange   = errors.New("unknown confidence out of range")
+	ErrInvalidUnknownFloor = errors.New("unknown floor must be in (0.0, 1.0)")
 )
 
 // CertaintyThreshold is the threshold for considering a belief "certain".
@@ -45,6 +86,61 @@ const DominantMargin = 0.1
 // BeliefSumEpsilon is the tolerance for belief sum validation.
 const BeliefSumEpsilon = 1e-9
 
+// DefaultUnknownFloor is the default minimum unknown confidence.
+const DefaultUnknownFloor = 0.05
+
+// unknownFloorBits holds the math.Float64bits of the minimum unknown
+// confidence any derived belief may carry, atomic so the floor may be
+// changed while beliefs are derived concurrently.
+// Property 8: Unknown is always allowed (never forced to zero).
+var unknownFloorBits atomic.Uint64
+
+func init() {
+	unknownFloorBits.Store(math.Float64bits(DefaultUnknownFloor))
+}
+
+// UnknownFloor returns the minimum unknown confidence for derived beliefs.
+func UnknownFloor() float64 {
+	return math.Float64frombits(unknownFloorBits.Load())
+}
+
+// SetUnknownFloor changes the minimum unknown confidence for derived beliefs.
+// Returns an error if the floor is not strictly between 0.0 and 1.0.
+func SetUnknownFloor(floor float64) error {
+	if math.IsNaN(floor) || floor <= 0.0 || floor >= 1.0 {
+		return fmt.Errorf("%w: got %f", ErrInvalidUnknownFloor, floor)
+	}
+	unknownFloorBits.Store(math.Float64bits(floor))
+	return nil
+}
+
+// ApplyUnknownFloor raises unknown to the configured floor if needed.
+//
+// The excess is taken equally from alive and dead. If one of them cannot
+// cover its half, the remainder is taken from the other, so the result
+// always sums to 1.0 and no component goes negative.
+func ApplyUnknownFloor(alive, dead, unknown float64) (float64, float64, float64) {
+	floor := UnknownFloor()
+	if unknown >= floor {
+		return alive, dead, unknown
+	}
+
+	excess := floor - unknown
+	half := excess / 2
+	switch {
+	case alive < half:
+		dead -= excess - alive
+		alive = 0
+	case dead < half:
+		alive -= excess - dead
+		dead = 0
+	default:
+		alive -= half
+		dead -= half
+	}
+	return math.Max(alive, 0), math.Max(dead, 0), floor
+}
+
 // Belief represents a probability distribution over node liveness.
 //
 // This represents the probability distribution over three mutually
@@ -58,24 +154,27 @@ type Belief struct {
 }
 
 // NewBelief creates a new Belief from raw confidence values.
-// Returns an error if the values don't sum to 1.0 (within tolerance).
+// Each component is validated first; an invalid component returns
+// ErrAliveOutOfRange, ErrDeadOutOfRange or ErrUnknownOutOfRange wrapping
+// the underlying confidence error. Only then is the sum checked, returning
+// ErrBeliefInvalidSum if the values don't sum to 1.0 (within tolerance).
 func NewBelief(alive, dead, unknown float64) (Belief, error) {
-	sum := alive + dead + unknown
-	if math.Abs(sum-1.0) > BeliefSumEpsilon {
-		return Belief{}, fmt.Errorf("%w: got %f", ErrBeliefInvalidSum, sum)
-	}
-
 	aliveConf, err := NewConfidence(alive)
 	if err != nil {
-		return Belief{}, err
+		return Belief{}, fmt.Errorf("%w: %w", ErrAliveOutOfRange, err)
 	}
 	deadConf, err := NewConfidence(dead)
 	if err != nil {
-		return Belief{}, err
+		return Belief{}, fmt.Errorf("%w: %w", ErrDeadOutOfRange, err)
 	}
 	unknownConf, err := NewConfidence(unknown)
 	if err != nil {
-		return Belief{}, err
+		return Belief{}, fmt.Errorf("%w: %w", ErrUnknownOutOfRange, err)
+	}
+
+	sum := alive + dead + unknown
+	if math.Abs(sum-1.0) > BeliefSumEpsilon {
+		return Belief{}, fmt.Errorf("%w: got %f", ErrBeliefInvalidSum, sum)
 	}
 
 	return Belief{
@@ -170,10 +269,11 @@ func (b Belief) Dominant() BeliefState {
 }
 
 // IsValid checks that the belief invariant holds.
-// Returns true if alive + dead + unknown ≈ 1.0
+// Returns true if alive + dead + unknown ≈ 1.0, using the same
+// tolerance NewBelief accepts.
 func (b Belief) IsValid() bool {
 	sum := b.alive.Value() + b.dead.Value() + b.unknown.Value()
-	return math.Abs(sum-1.0) < BeliefSumEpsilon
+	return math.Abs(sum-1.0) <= BeliefSumEpsilon
 }
 
 // Equal checks if two beliefs are equal.
@@ -183,6 +283,180 @@ func (b Belief) Equal(other Belief) bool {
 		b.unknown.Equal(other.unknown)
 }
 
+// MoreAliveThan reports whether b has strictly higher alive confidence than
+// other. Confidences within ConfidenceEpsilon tie, and ties are never more.
+func (b Belief) MoreAliveThan(other Belief) bool {
+	return other.alive.Less(b.alive)
+}
+
+// MoreDeadThan reports whether b has strictly higher dead confidence than
+// other. Ties are never more.
+func (b Belief) MoreDeadThan(other Belief) bool {
+	return other.dead.Less(b.dead)
+}
+
+// MoreCertainThan reports whether b is strictly more certain than other,
+// that is, has less unknown mass. Ties are never more.
+func (b Belief) MoreCertainThan(other Belief) bool {
+	return b.unknown.Less(other.unknown)
+}
+
+// Update applies a likelihood to this belief as a sequential Bayesian update.
+//
+// Unknown is treated as unassigned mass that can support either state
+// (Dempster's rule of combination): alive mass survives where both agree
+// or where one side is unknown, likewise for dead, and only mass that is
+// unknown on both sides stays unknown. Contradicting mass (alive vs dead)
+// is discarded and the rest renormalized. The unknown floor is preserved
+// (Property 8), and total conflict yields pure uncertainty (Property 9).
+//
+// This is an online alternative to EvidenceSet.ComputeBelief for callers
+// managing their own evidence stream. It does not decay: updating and then
+// decaying is not the same as decaying and then updating, so callers that
+// need age-based decay must apply it themselves between updates.
+func (b Belief) Update(likelihood Belief) Belief {
+	a1, d1, u1 := b.alive.Value(), b.dead.Value(), b.unknown.Value()
+	a2, d2, u2 := likelihood.alive.Value(), likelihood.dead.Value(), likelihood.unknown.Value()
+
+	alive := a1*a2 + a1*u2 + u1*a2
+	dead := d1*d2 + d1*u2 + u1*d2
+	unknown := u1 * u2
+
+	norm := alive + dead + unknown
+	if norm < BeliefSumEpsilon {
+		return UnknownBelief()
+	}
+	alive, dead, unknown = ApplyUnknownFloor(alive/norm, dead/norm, unknown/norm)
+
+	// Absorb rounding so the invariant holds exactly
+	updated, err := NewBelief(alive, dead, 1.0-alive-dead)
+	if err != nil {
+		return UnknownBelief()
+	}
+	return updated
+}
+
+// WeightedCombine returns the convex combination weight*b + (1-weight)*other.
+// The weight is clamped to [0,1], so the result is always a valid belief.
+func (b Belief) WeightedCombine(other Belief, weight float64) Belief {
+	w := ClampedConfidence(weight).Value()
+	alive := w*b.alive.Value() + (1-w)*other.alive.Value()
+	dead := w*b.dead.Value() + (1-w)*other.dead.Value()
+	return Belief{
+		alive:   ClampedConfidence(alive),
+		dead:    ClampedConfidence(dead),
+		unknown: ClampedConfidence(1.0 - alive - dead),
+	}
+}
+
+// Interpolate returns the belief a fraction t of the way from b to other,
+// for animating belief transitions on dashboards.
+// Each component is interpolated linearly and the result renormalized so
+// it stays a valid belief. t is clamped to [0,1]; t=0 returns b and t=1
+// returns other unchanged.
+func (b Belief) Interpolate(other Belief, t float64) Belief {
+	t = ClampedConfidence(t).Value()
+	if t == 0 {
+		return b
+	}
+	if t == 1 {
+		return other
+	}
+
+	alive := b.alive.Value() + t*(other.alive.Value()-b.alive.Value())
+	dead := b.dead.Value() + t*(other.dead.Value()-b.dead.Value())
+	unknown := b.unknown.Value() + t*(other.unknown.Value()-b.unknown.Value())
+
+	sum := alive + dead + unknown
+	if sum < BeliefSumEpsilon {
+		return UnknownBelief()
+	}
+	alive, dead = alive/sum, dead/sum
+	return Belief{
+		alive:   ClampedConfidence(alive),
+		dead:    ClampedConfidence(dead),
+		unknown: ClampedConfidence(1.0 - alive - dead),
+	}
+}
+
+// Distance returns the total variation distance between two beliefs: half
+// the summed absolute difference of their components. It is 0 for equal
+// beliefs and 1 for beliefs with no mass in common, such as certainly
+// alive and certainly dead.
+func (b Belief) Distance(other Belief) float64 {
+	return (math.Abs(b.alive.Value()-other.alive.Value()) +
+		math.Abs(b.dead.Value()-other.dead.Value()) +
+		math.Abs(b.unknown.Value()-other.unknown.Value())) / 2
+}
+
+// beliefJSON is the wire representation of a Belief.
+type beliefJSON struct {
+	Alive   float64 `json:"alive"`
+	Dead    float64 `json:"dead"`
+	Unknown float64 `json:"unknown"`
+}
+
+// MarshalJSON encodes the belief as {"alive":..,"dead":..,"unknown":..}.
+func (b Belief) MarshalJSON() ([]byte, error) {
+	return json.Marshal(beliefJSON{
+		Alive:   b.alive.Value(),
+		Dead:    b.dead.Value(),
+		Unknown: b.unknown.Value(),
+	})
+}
+
+// UnmarshalJSON decodes a belief, enforcing the same rules as NewBelief.
+func (b *Belief) UnmarshalJSON(data []byte) error {
+	var raw beliefJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	decoded, err := NewBelief(raw.Alive, raw.Dead, raw.Unknown)
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+// BeliefBinarySize is the length of a MarshalBinary encoding.
+const BeliefBinarySize = 16
+
+// ErrInvalidBeliefEncoding is returned when binary data is not a belief.
+var ErrInvalidBeliefEncoding = errors.New("invalid binary belief encoding")
+
+// MarshalBinary encodes the belief as alive and dead big-endian float64s.
+// Unknown is implied by Property 18 (components sum to 1) and not stored.
+func (b Belief) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, BeliefBinarySize)
+	buf = binary.BigEndian.AppendUint64(buf, math.Float64bits(b.alive.Value()))
+	buf = binary.BigEndian.AppendUint64(buf, math.Float64bits(b.dead.Value()))
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a MarshalBinary encoding, enforcing the same rules
+// as NewBelief.
+func (b *Belief) UnmarshalBinary(data []byte) error {
+	if len(data) != BeliefBinarySize {
+		return fmt.Errorf("%w: got %d bytes, want %d", ErrInvalidBeliefEncoding, len(data), BeliefBinarySize)
+	}
+	alive := math.Float64frombits(binary.BigEndian.Uint64(data[:8]))
+	dead := math.Float64frombits(binary.BigEndian.Uint64(data[8:]))
+
+	// Derived unknown can land an ulp below zero for alive+dead == 1
+	unknown := 1.0 - alive - dead
+	if unknown < 0 && unknown > -BeliefSumEpsilon {
+		unknown = 0
+	}
+
+	decoded, err := NewBelief(alive, dead, unknown)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBeliefEncoding, err)
+	}
+	*b = decoded
+	return nil
+}
+
 // String returns a human-readable representation.
 func (b Belief) String() string {
 	return fmt.Sprintf("[A:%.0f%% D:%.0f%% U:%.0f%%] → %s",
@@ -0,0 +1,254 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNewBeliefComponentErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		alive, dead, unk float64
+		want             error
+		cause            error
+	}{
+		{"nan dead", 0.5, math.NaN(), 0.5, ErrDeadOutOfRange, ErrConfidenceNaN},
+		{"negative alive", -0.1, 0.6, 0.5, ErrAliveOutOfRange, ErrConfidenceBelowMinimum},
+		{"unknown above one", 0, 0, 1.2, ErrUnknownOutOfRange, ErrConfidenceAboveMaximum},
+		{"bad sum", 0.5, 0.5, 0.5, ErrBeliefInvalidSum, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBelief(tt.alive, tt.dead, tt.unk)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+			if tt.cause != nil && !errors.Is(err, tt.cause) {
+				t.Errorf("expected cause %v, got %v", tt.cause, err)
+			}
+		})
+	}
+}
+
+func TestBeliefUpdate(t *testing.T) {
+	strongAlive := MustBelief(0.8, 0.05, 0.15)
+
+	b := UnknownBelief().Update(strongAlive)
+	if b.Dominant() != StateAlive {
+		t.Fatalf("expected alive-leaning belief, got %s", b)
+	}
+	if !b.IsValid() {
+		t.Fatalf("invalid belief %s", b)
+	}
+
+	// Consistent updates raise confidence with shrinking increments
+	prev := b.Alive().Value()
+	prevGain := prev
+	for i := 0; i < 5; i++ {
+		b = b.Update(strongAlive)
+		gain := b.Alive().Value() - prev
+		if gain < 0 {
+			t.Fatalf("update %d lowered alive confidence: %s", i, b)
+		}
+		if gain > prevGain {
+			t.Errorf("update %d grew superlinearly: gain %f > %f", i, gain, prevGain)
+		}
+		if b.Unknown().Value() < UnknownFloor()-BeliefSumEpsilon {
+			t.Errorf("update %d dropped unknown below floor: %s", i, b)
+		}
+		prev, prevGain = b.Alive().Value(), gain
+	}
+}
+
+func TestBeliefUpdateTotalConflict(t *testing.T) {
+	b := CertainlyAlive().Update(CertainlyDead())
+	if !b.Equal(UnknownBelief()) {
+		t.Errorf("total conflict should yield unknown, got %s", b)
+	}
+}
+
+func TestParseBeliefState(t *testing.T) {
+	tests := []struct {
+		in   string
+		want BeliefState
+	}{
+		{"alive", StateAlive},
+		{"DEAD", StateDead},
+		{"unknown", StateUnknown},
+		{"Alive", StateAlive},
+	}
+	for _, tt := range tests {
+		got, err := ParseBeliefState(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseBeliefState(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	_, err := ParseBeliefState("zombie")
+	if !errors.Is(err, ErrUnknownBeliefState) {
+		t.Fatalf("expected ErrUnknownBeliefState, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "zombie") {
+		t.Errorf("error %q does not name the bad value", err)
+	}
+}
+
+func TestBeliefStateTextRoundTrip(t *testing.T) {
+	for _, s := range []BeliefState{StateUnknown, StateAlive, StateDead} {
+		text, err := s.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got BeliefState
+		if err := got.UnmarshalText(text); err != nil || got != s {
+			t.Errorf("round trip %v: got %v, %v", s, got, err)
+		}
+	}
+
+	data, err := json.Marshal(map[string]BeliefState{"state": StateDead})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"state":"DEAD"}` {
+		t.Errorf("json = %s", data)
+	}
+}
+
+func TestBeliefBinaryRoundTrip(t *testing.T) {
+	beliefs := []Belief{
+		UnknownBelief(),
+		MustBelief(0, 1, 0),
+		MustBelief(1, 0, 0),
+		MustBelief(0.7, 0.2, 0.1),
+		MustBelief(0.1, 0.3, 0.6),
+	}
+	for _, b := range beliefs {
+		data, err := b.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Belief
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary(%v): %v", b, err)
+		}
+		if !got.Equal(b) {
+			t.Errorf("round trip %v -> %v", b, got)
+		}
+
+		js, _ := json.Marshal(b)
+		if 2*len(data) > len(js) {
+			t.Errorf("binary %d bytes not half of json %d bytes", len(data), len(js))
+		}
+	}
+}
+
+func TestBeliefUnmarshalBinaryRejectsInvalid(t *testing.T) {
+	var b Belief
+	if err := b.UnmarshalBinary([]byte{1, 2, 3}); !errors.Is(err, ErrInvalidBeliefEncoding) {
+		t.Errorf("short input: got %v", err)
+	}
+
+	bad, _ := MustBelief(0.5, 0.5, 0).MarshalBinary()
+	bad[0] = 0x7f // alive becomes a huge float
+	if err := b.UnmarshalBinary(bad); !errors.Is(err, ErrInvalidBeliefEncoding) {
+		t.Errorf("out of range alive: got %v", err)
+	}
+}
+
+func TestBeliefInterpolate(t *testing.T) {
+	from := MustBelief(0.8, 0.1, 0.1)
+	to := MustBelief(0.3, 0.5, 0.2)
+
+	if got := from.Interpolate(to, 0); !got.Equal(from) {
+		t.Errorf("t=0: got %v, want %v", got, from)
+	}
+	if got := from.Interpolate(to, 1); !got.Equal(to) {
+		t.Errorf("t=1: got %v, want %v", got, to)
+	}
+
+	mid := from.Interpolate(to, 0.5)
+	const eps = 1e-9
+	if math.Abs(mid.Alive().Value()-0.55) > eps ||
+		math.Abs(mid.Dead().Value()-0.3) > eps ||
+		math.Abs(mid.Unknown().Value()-0.15) > eps {
+		t.Errorf("t=0.5: got %v, want component averages", mid)
+	}
+
+	for _, step := range []float64{-1, 0, 0.1, 0.33, 0.5, 0.77, 1, 2, math.NaN()} {
+		if b := from.Interpolate(to, step); !b.IsValid() {
+			t.Errorf("t=%v: invalid belief %v", step, b)
+		}
+	}
+}
+
+func TestBeliefDistance(t *testing.T) {
+	a := MustBelief(0.8, 0.1, 0.1)
+	b := MustBelief(0.3, 0.5, 0.2)
+
+	if d := a.Distance(a); d != 0 {
+		t.Errorf("distance to self %v, want 0", d)
+	}
+	if d, r := a.Distance(b), b.Distance(a); math.Abs(d-0.5) > 1e-9 || d != r {
+		t.Errorf("distance %v and %v, want symmetric 0.5", d, r)
+	}
+	if d := CertainlyAlive().Distance(CertainlyDead()); d != 1 {
+		t.Errorf("alive to dead %v, want 1", d)
+	}
+}
+
+func TestBeliefComparators(t *testing.T) {
+	alive := MustBelief(0.8, 0.1, 0.1)
+	dead := MustBelief(0.1, 0.6, 0.3)
+	unsure := MustBelief(0.3, 0.2, 0.5)
+
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"alive more alive than dead", alive.MoreAliveThan(dead), true},
+		{"dead not more alive than alive", dead.MoreAliveThan(alive), false},
+		{"dead more dead than alive", dead.MoreDeadThan(alive), true},
+		{"alive not more dead than dead", alive.MoreDeadThan(dead), false},
+		{"alive more certain than unsure", alive.MoreCertainThan(unsure), true},
+		{"unsure not more certain than dead", unsure.MoreCertainThan(dead), false},
+
+		// ties are never strictly more, either way round
+		{"alive tie", alive.MoreAliveThan(MustBelief(0.8, 0.05, 0.15)), false},
+		{"alive tie reversed", MustBelief(0.8, 0.05, 0.15).MoreAliveThan(alive), false},
+		{"dead tie", alive.MoreDeadThan(MustBelief(0.5, 0.1, 0.4)), false},
+		{"certain tie", alive.MoreCertainThan(MustBelief(0.2, 0.7, 0.1)), false},
+		{"self", alive.MoreCertainThan(alive), false},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestSetUnknownFloorConcurrentWithReaders(t *testing.T) {
+	prev := UnknownFloor()
+	defer SetUnknownFloor(prev)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			SetUnknownFloor(0.05 + float64(i%10)*0.01)
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		alive, dead, unknown := ApplyUnknownFloor(0.9, 0.1, 0)
+		if sum := alive + dead + unknown; math.Abs(sum-1) > BeliefSumEpsilon || unknown < 0.05-BeliefSumEpsilon {
+			t.Fatalf("floored to %f, %f, %f", alive, dead, unknown)
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"math"
+	"testing"
+)
+
+// Run locally or in CI with:
+//
+//	go test -run=^$ -fuzz=FuzzNewBelief -fuzztime=30s ./types
+//	go test -run=^$ -fuzz=FuzzMustBelief -fuzztime=30s ./types
+
+func addBeliefSeeds(f *testing.F) {
+	f.Add(0.0, 0.0, 1.0)
+	f.Add(0.333, 0.333, 0.334)
+	f.Add(math.NaN(), 0.5, 0.5)
+	f.Add(0.5, math.NaN(), 0.5)
+	f.Add(math.Inf(1), 0.0, 0.0)
+	f.Add(math.Inf(-1), 1.0, 1.0)
+	f.Add(0.5, 0.5, 1e-10)
+	f.Add(0.5, 0.5, -1e-10)
+	f.Add(1.0+1e-10, 0.0, 0.0)
+	f.Add(5e-324, 0.5, 0.5) // subnormal
+	f.Add(0.1, 0.2, 0.7)
+}
+
+// FuzzNewBelief checks that accepted beliefs always satisfy the invariant
+func FuzzNewBelief(f *testing.F) {
+	addBeliefSeeds(f)
+	f.Fuzz(func(t *testing.T, alive, dead, unknown float64) {
+		b, err := NewBelief(alive, dead, unknown)
+		if err != nil {
+			return
+		}
+		if !b.IsValid() {
+			t.Errorf("NewBelief(%v, %v, %v) accepted an invalid belief: %s", alive, dead, unknown, b)
+		}
+		for _, c := range []Confidence{b.Alive(), b.Dead(), b.Unknown()} {
+			if math.IsNaN(c.Value()) || c.Value() < 0 || c.Value() > 1 {
+				t.Errorf("component out of range: %v", c.Value())
+			}
+		}
+	})
+}
+
+// FuzzMustBelief checks that MustBelief panics exactly when NewBelief errors
+func FuzzMustBelief(f *testing.F) {
+	addBeliefSeeds(f)
+	f.Fuzz(func(t *testing.T, alive, dead, unknown float64) {
+		_, wantErr := NewBelief(alive, dead, unknown)
+
+		panicked := func() (p bool) {
+			defer func() { p = recover() != nil }()
+			MustBelief(alive, dead, unknown)
+			return false
+		}()
+
+		if panicked != (wantErr != nil) {
+			t.Errorf("MustBelief(%v, %v, %v): panicked=%v, NewBelief err=%v", alive, dead, unknown, panicked, wantErr)
+		}
+	})
+}
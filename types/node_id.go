@@ -1,6 +1,10 @@
 package types
 
-import "fmt"
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
 
 // NodeID uniquely identifies a node in the distributed system.
 //
@@ -55,3 +59,53 @@ func (n NodeID) String() string {
 func (n NodeID) Equal(other NodeID) bool {
 	return n.Base == other.Base && n.Generation == other.Generation
 }
+
+// Less reports whether n orders before other: by base, then generation.
+func (n NodeID) Less(other NodeID) bool {
+	if n.Base != other.Base {
+		return n.Base < other.Base
+	}
+	return n.Generation < other.Generation
+}
+
+// SortNodeIDs sorts ids in Less order, so output built from maps is stable.
+func SortNodeIDs(ids []NodeID) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Less(ids[j]) })
+}
+
+// FNV-1a 64-bit parameters.
+const (
+	fnvOffset64 uint64 = 14695981039346656037
+	fnvPrime64  uint64 = 1099511628211
+)
+
+// Hash returns a stable 64-bit hash of the NodeID.
+// It is FNV-1a over the little-endian base followed by the generation, so
+// it is identical across processes and releases. Equal NodeIDs hash
+// equally; generations of the same base hash differently.
+func (n NodeID) Hash() uint64 {
+	var buf [16]byte
+	binary.LittleEndian.PutUint64(buf[:8], n.Base)
+	binary.LittleEndian.PutUint64(buf[8:], n.Generation)
+	return fnv64a(buf[:])
+}
+
+// fnv64a returns the FNV-1a 64-bit hash of data.
+func fnv64a(data []byte) uint64 {
+	h := fnvOffset64
+	for _, b := range data {
+		h ^= uint64(b)
+		h *= fnvPrime64
+	}
+	return h
+}
+
+// Shard maps the NodeID to one of numShards shards, for routing targets
+// across Oracle instances. The result is in [0, numShards); a non-positive
+// numShards returns 0.
+func (n NodeID) Shard(numShards int) int {
+	if numShards <= 0 {
+		return 0
+	}
+	return int(n.Hash() % uint64(numShards))
+}
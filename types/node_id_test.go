@@ -0,0 +1,122 @@
+package types
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNodeIDHashGenerationSensitive(t *testing.T) {
+	a := WithGeneration(42, 3)
+	if a.Hash() != WithGeneration(42, 3).Hash() {
+		t.Error("equal NodeIDs should hash equally")
+	}
+	if a.Hash() == a.Rebirth().Hash() {
+		t.Error("rebirth should hash differently")
+	}
+	if NewNodeID(1).Hash() == WithGeneration(0, 1).Hash() {
+		t.Error("base and generation should not be interchangeable")
+	}
+}
+
+func TestNodeIDShardDistribution(t *testing.T) {
+	const shards, nodes = 8, 8000
+	counts := make([]int, shards)
+	for i := uint64(0); i < nodes; i++ {
+		s := NewNodeID(i).Shard(shards)
+		if s < 0 || s >= shards {
+			t.Fatalf("shard %d out of range", s)
+		}
+		counts[s]++
+	}
+
+	// Each shard should be within 10% of the fair share
+	fair := nodes / shards
+	for s, c := range counts {
+		if c < fair*9/10 || c > fair*11/10 {
+			t.Errorf("shard %d got %d nodes, fair share %d", s, c, fair)
+		}
+	}
+
+	if got := NewNodeID(7).Shard(0); got != 0 {
+		t.Errorf("Shard(0) = %d, want 0", got)
+	}
+}
+
+func TestNodeIDFromUUIDRoundTrip(t *testing.T) {
+	uuid := [16]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+
+	table := NewUUIDTable()
+	id := table.NodeID(uuid)
+	if id != NodeIDFromUUID(uuid) || id.Generation != 0 {
+		t.Errorf("table minted %v, want %v", id, NodeIDFromUUID(uuid))
+	}
+	if got, ok := table.UUID(id); !ok || got != uuid {
+		t.Errorf("UUID() = %x, %v, want %x", got, ok, uuid)
+	}
+	if got, ok := table.UUID(id.Rebirth()); !ok || got != uuid {
+		t.Errorf("reborn UUID() = %x, %v, want %x", got, ok, uuid)
+	}
+	if _, ok := table.UUID(NewNodeID(7)); ok {
+		t.Error("plain NodeID should have no UUID")
+	}
+
+	// the wire form is the same identity, map keys included
+	wire := WithGeneration(id.Base, id.Generation)
+	if wire != id {
+		t.Errorf("NodeID rebuilt from Base %#v differs from %#v", wire, id)
+	}
+	if got, ok := table.UUID(wire); !ok || got != uuid {
+		t.Errorf("wire NodeID UUID() = %x, %v, want %x", got, ok, uuid)
+	}
+}
+
+func TestParseNodeIDFromString(t *testing.T) {
+	uuid := [16]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	valid := []struct {
+		in   string
+		want NodeID
+	}{
+		{"0000000000000001.g0", NewNodeID(1)},
+		{WithGeneration(0xdeadbeef, 3).String(), WithGeneration(0xdeadbeef, 3)},
+		{"6ba7b810-9dad-11d1-80b4-00c04fd430c8", NodeIDFromUUID(uuid)},
+		{"6BA7B810-9DAD-11D1-80B4-00C04FD430C8", NodeIDFromUUID(uuid)},
+	}
+	for _, tt := range valid {
+		got, err := ParseNodeIDFromString(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("%q: got %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{
+		"",
+		"1",
+		".g0",
+		"0000000000000001.g",
+		"0000000000000001.gx",
+		"zz.g0",
+		"00000000000000001.g0",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c",
+		"6ba7b810x9dad-11d1-80b4-00c04fd430c8",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430cg",
+	} {
+		if _, err := ParseNodeIDFromString(in); !errors.Is(err, ErrInvalidNodeID) {
+			t.Errorf("%q: expected ErrInvalidNodeID, got %v", in, err)
+		}
+	}
+}
+
+func TestSortNodeIDs(t *testing.T) {
+	ids := []NodeID{WithGeneration(2, 0), WithGeneration(1, 3), WithGeneration(1, 0), WithGeneration(2, 1)}
+	SortNodeIDs(ids)
+
+	want := []NodeID{WithGeneration(1, 0), WithGeneration(1, 3), WithGeneration(2, 0), WithGeneration(2, 1)}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("got %v, want %v", ids, want)
+		}
+	}
+	if want[0].Less(want[0]) || !want[0].Less(want[1]) || want[2].Less(want[1]) {
+		t.Error("Less is not a strict base-then-generation order")
+	}
+}
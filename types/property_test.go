@@ -0,0 +1,108 @@
+package types
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// quickConfig runs each property over 1000 generated inputs
+var quickConfig = &quick.Config{MaxCount: 1000}
+
+// validTriple is a generated (alive, dead, unknown) summing to 1
+type validTriple struct {
+	Alive, Dead, Unknown float64
+}
+
+// Generate implements quick.Generator
+func (validTriple) Generate(r *rand.Rand, _ int) reflect.Value {
+	alive := r.Float64()
+	dead := r.Float64() * (1 - alive)
+	return reflect.ValueOf(validTriple{
+		Alive:   alive,
+		Dead:    dead,
+		Unknown: 1 - alive - dead,
+	})
+}
+
+func (v validTriple) belief(t *testing.T) Belief {
+	t.Helper()
+	b, err := NewBelief(v.Alive, v.Dead, v.Unknown)
+	if err != nil {
+		t.Fatalf("NewBelief%v: %v", v, err)
+	}
+	return b
+}
+
+func TestBeliefPropertiesQuick(t *testing.T) {
+	property := func(v validTriple) bool {
+		b := v.belief(t)
+		if !b.IsValid() {
+			t.Logf("invalid: %v", v)
+			return false
+		}
+
+		data, err := json.Marshal(b)
+		if err != nil {
+			t.Logf("marshal %v: %v", v, err)
+			return false
+		}
+		var decoded Belief
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Logf("unmarshal %s: %v", data, err)
+			return false
+		}
+		if decoded != b {
+			t.Logf("round trip not exact: %v -> %s -> %v", v, data, decoded)
+			return false
+		}
+
+		var want BeliefState
+		switch {
+		case v.Alive > v.Dead+DominantMargin && v.Alive > v.Unknown+DominantMargin:
+			want = StateAlive
+		case v.Dead > v.Alive+DominantMargin && v.Dead > v.Unknown+DominantMargin:
+			want = StateDead
+		default:
+			want = StateUnknown
+		}
+		if b.Dominant() != want {
+			t.Logf("dominant %s, want %s for %v", b.Dominant(), want, v)
+			return false
+		}
+		return true
+	}
+
+	if err := quick.Check(property, quickConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWeightedCombinePropertiesQuick(t *testing.T) {
+	property := func(x, y validTriple, weight float64) bool {
+		combined := x.belief(t).WeightedCombine(y.belief(t), weight)
+		if !combined.IsValid() {
+			t.Logf("invalid combination of %v and %v at %f: %s", x, y, weight, combined)
+			return false
+		}
+		return true
+	}
+
+	weights := func(args []reflect.Value, r *rand.Rand) {
+		args[0] = validTriple{}.Generate(r, 0)
+		args[1] = validTriple{}.Generate(r, 0)
+		args[2] = reflect.ValueOf(r.Float64())
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 1000, Values: weights}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUnmarshalJSONRejectsInvalid(t *testing.T) {
+	var b Belief
+	if err := json.Unmarshal([]byte(`{"alive":0.9,"dead":0.9,"unknown":0}`), &b); err == nil {
+		t.Error("expected error for belief not summing to 1")
+	}
+}
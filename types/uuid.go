@@ -0,0 +1,91 @@
+package types
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidNodeID is returned by ParseNodeIDFromString for malformed input.
+var ErrInvalidNodeID = errors.New("invalid node id")
+
+// NodeIDFromUUID creates a NodeID for a node identified by an RFC 4122 UUID.
+// The Base is the FNV-1a 64-bit hash of the 16 UUID bytes and the
+// generation starts at 0.
+//
+// The mapping is one-way: the NodeID carries Base and Generation only, so
+// it is the same identity as the NodeID decoded from the wire. Callers that
+// need the UUID back keep a UUIDTable.
+func NodeIDFromUUID(uuid [16]byte) NodeID {
+	return NodeID{Base: fnv64a(uuid[:])}
+}
+
+// UUIDTable remembers the UUIDs NodeIDs were created from.
+// It is owned by the caller that mints the NodeIDs; a zero UUIDTable is
+// not usable, create one with NewUUIDTable. Safe for concurrent use.
+type UUIDTable struct {
+	mu    sync.RWMutex
+	uuids map[uint64][16]byte
+}
+
+// NewUUIDTable creates an empty table.
+func NewUUIDTable() *UUIDTable {
+	return &UUIDTable{uuids: make(map[uint64][16]byte)}
+}
+
+// NodeID returns NodeIDFromUUID(uuid) and remembers uuid for its Base.
+func (t *UUIDTable) NodeID(uuid [16]byte) NodeID {
+	id := NodeIDFromUUID(uuid)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.uuids[id.Base] = uuid
+	return id
+}
+
+// UUID returns the UUID id was created from, in any generation.
+// ok is false if no UUID with id's Base went through this table.
+func (t *UUIDTable) UUID(id NodeID) (uuid [16]byte, ok bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	uuid, ok = t.uuids[id.Base]
+	return uuid, ok
+}
+
+// ParseNodeIDFromString parses either the String form of a NodeID
+// ("0000000000000001.g0") or a UUID ("6ba7b810-9dad-11d1-80b4-00c04fd430c8").
+// UUIDs are mapped with NodeIDFromUUID.
+func ParseNodeIDFromString(s string) (NodeID, error) {
+	if uuid, ok := parseUUID(s); ok {
+		return NodeIDFromUUID(uuid), nil
+	}
+
+	base, gen, ok := strings.Cut(s, ".g")
+	if !ok || base == "" || len(base) > 16 || gen == "" {
+		return NodeID{}, fmt.Errorf("%w: %q", ErrInvalidNodeID, s)
+	}
+	b, err := strconv.ParseUint(base, 16, 64)
+	if err != nil {
+		return NodeID{}, fmt.Errorf("%w: %q: %w", ErrInvalidNodeID, s, err)
+	}
+	g, err := strconv.ParseUint(gen, 10, 64)
+	if err != nil {
+		return NodeID{}, fmt.Errorf("%w: %q: %w", ErrInvalidNodeID, s, err)
+	}
+	return WithGeneration(b, g), nil
+}
+
+// parseUUID parses the canonical 8-4-4-4-12 hex UUID form.
+func parseUUID(s string) ([16]byte, bool) {
+	var uuid [16]byte
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return uuid, false
+	}
+	digits := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36]
+	if _, err := hex.Decode(uuid[:], []byte(digits)); err != nil {
+		return uuid, false
+	}
+	return uuid, true
+}
@@ -1,17 +1,70 @@
 package witness
 
 import (
+	"errors"
 	"math"
+	"runtime"
+	"sync"
 
+	styxtime "github.com/styx-oracle/styx/time"
 	"github.com/styx-oracle/styx/types"
 )
 
 // WitnessReport is a belief report from a single witness
 type WitnessReport struct {
-	Witness types.NodeID
-	Target  types.NodeID
-	Belief  types.Belief
-	Trust   TrustScore
+	Witness   types.NodeID
+	Target    types.NodeID
+	Belief    types.Belief
+	Trust     TrustScore
+	Timestamp styxtime.LogicalTimestamp
+	// Origin is the timestamp the observation was first given, by the
+	// witness itself or by the first Oracle to store it
+	// relays keep it, so it tells copies of one observation from new ones
+	Origin styxtime.LogicalTimestamp
+	// Signature over SigningPayload, required once the witness has a public key
+	Signature []byte
+	// RelayPath lists nodes that forwarded the report, oldest first
+	// empty for reports received directly from Witness
+	RelayPath []types.NodeID
+	// Quality is how reliable the underlying observation was, in (0,1]
+	// e.g. erratic latency or local jitter lower it, 0 = not reported
+	Quality float64
+	// AliveInterval is the witness's own 90% credible interval [low, high]
+	// around its alive value, zero value = point estimate only
+	AliveInterval [2]float64
+	// EffectiveTrust is the registry trust of Witness when the report was
+	// aggregated, set by Aggregate on the reports in AggregateResult
+	EffectiveTrust TrustScore
+}
+
+// MinQuality is the lowest quality a report counts with
+// like MinTrust a noisy observation still carries some weight
+const MinQuality = 0.1
+
+// QualityFactor returns the weight multiplier for the report quality
+// unreported quality (0) counts fully, anything else is clamped to
+// [MinQuality, 1]
+func (r WitnessReport) QualityFactor() float64 {
+	if r.Quality == 0 || math.IsNaN(r.Quality) {
+		return 1
+	}
+	return math.Max(MinQuality, math.Min(r.Quality, 1))
+}
+
+// IntervalFactor returns the weight multiplier for the self reported alive
+// interval, 1 - width, so an unsure witness counts for less
+// no interval or a malformed one falls back to the point estimate (1)
+func (r WitnessReport) IntervalFactor() float64 {
+	low, high := r.AliveInterval[0], r.AliveInterval[1]
+	if r.AliveInterval == [2]float64{} || !(low >= 0 && low <= high && high <= 1) {
+		return 1
+	}
+	return math.Max(MinQuality, 1-(high-low))
+}
+
+// reliability is the combined per report weight multiplier
+func (r WitnessReport) reliability() float64 {
+	return r.QualityFactor() * r.IntervalFactor()
 }
 
 // Aggregator combines multiple witness reports into a single belief
@@ -19,12 +72,45 @@ type WitnessReport struct {
 // - P10: Disagreement is preserved
 // - P11: Correlated witnesses weaken confidence
 type Aggregator struct {
-	registry *Registry
+	registry          *Registry
+	collusion         *CollusionDetector
+	parallelThreshold int
+	maxWitnessShare   float64
+	mode              AggregationMode
+	preverified       bool
 }
 
+// ParallelAggregateThreshold is the report count above which aggregation
+// fans out across goroutines, below it the goroutine overhead dominates
+const ParallelAggregateThreshold = 4096
+
 // NewAggregator creates an aggregator with a witness registry
 func NewAggregator(registry *Registry) *Aggregator {
-	return &Aggregator{registry: registry}
+	return &Aggregator{
+		registry:          registry,
+		parallelThreshold: ParallelAggregateThreshold,
+	}
+}
+
+// WithParallelThreshold sets the report count that switches to parallel
+// aggregation, 0 or less keeps aggregation sequential
+func (a *Aggregator) WithParallelThreshold(n int) *Aggregator {
+	a.parallelThreshold = n
+	return a
+}
+
+// WithCollusionDetector penalizes witnesses that report in lockstep
+// P11: a colluding group carries the weight of a single witness
+func (a *Aggregator) WithCollusionDetector(d *CollusionDetector) *Aggregator {
+	a.collusion = d
+	return a
+}
+
+// WithPreverifiedReports skips signature checks, for callers that verify
+// reports when they take them in and may restamp them afterwards
+func (a *Aggregator) WithPreverifiedReports() *Aggregator {
+	a.preverified = true
+	return a
 }
 
 // AggregateResult contains the combined belief and disagreement info
@@ -33,58 +119,221 @@ type AggregateResult struct {
 	Disagreement float64 // 0 = all agree, 1 = max disagreement
 	WitnessCount int
 	Reports      []WitnessReport
+	// 90% credible intervals [low, high] around the alive and dead masses
+	AliveInterval [2]float64
+	DeadInterval  [2]float64
+	// Diagnostic is set when the math went wrong (zero or non-finite weights,
+	// NaN averages) and a safe value was substituted, "" otherwise
+	// a diagnostic points at a bug or corrupt input, not at the target
+	Diagnostic string
+	// AliveMass, DeadMass and AbstainMass split the voting weight by the
+	// dominant state of each report, fractions of the total weight summing
+	// to 1 (all 0 without usable weight)
+	// a 60% alive belief from a 30% alive bloc next to a 60% abstaining
+	// bloc reads very differently from one the whole fleet agrees on
+	AliveMass   float64
+	DeadMass    float64
+	AbstainMass float64
+}
+
+// setMasses fills the bloc masses from sums
+func (r *AggregateResult) setMasses(s beliefSums) {
+	if s.weight <= 0 {
+		return
+	}
+	r.AliveMass = s.aliveBloc / s.weight
+	r.DeadMass = s.deadBloc / s.weight
+	r.AbstainMass = s.abstainBloc / s.weight
+}
+
+// MostTrusted returns the report whose witness had the highest trust at
+// aggregation time, zero values if there were no reports
+func (r AggregateResult) MostTrusted() (WitnessReport, TrustScore) {
+	return r.extremeTrust(func(t, best TrustScore) bool { return t > best })
+}
+
+// LeastTrusted returns the report whose witness had the lowest trust at
+// aggregation time, zero values if there were no reports
+func (r AggregateResult) LeastTrusted() (WitnessReport, TrustScore) {
+	return r.extremeTrust(func(t, best TrustScore) bool { return t < best })
+}
+
+// extremeTrust returns the first report no other report beats
+func (r AggregateResult) extremeTrust(beats func(t, best TrustScore) bool) (WitnessReport, TrustScore) {
+	if len(r.Reports) == 0 {
+		return WitnessReport{}, 0
+	}
+	best := r.Reports[0]
+	for _, rep := range r.Reports[1:] {
+		if beats(rep.EffectiveTrust, best.EffectiveTrust) {
+			best = rep
+		}
+	}
+	return best, best.EffectiveTrust
+}
+
+// DisagreementThreshold is the disagreement above which uncertainty is widened
+// for ReferenceWitnesses witnesses, see AdaptiveThreshold
+const DisagreementThreshold = 0.3
+
+// SmallSampleFactor controls how far thresholds move with witness count
+const SmallSampleFactor = 0.5
+
+// ReferenceWitnesses is the witness count at which a threshold equals its base
+const ReferenceWitnesses = 36
+
+// AdaptiveThreshold scales a disagreement threshold by witness count
+// The disagreement estimate has a 1/sqrt(n) band, so the threshold moves by
+// the difference between that band and the one at ReferenceWitnesses
+// Fewer witnesses lower it, 1 of 3 dissenting is a signal
+// More witnesses raise it towards base*(1+SmallSampleFactor/6), a slightly
+// bigger minority in a large cluster is noise rather than a partition
+func AdaptiveThreshold(base float64, witnesses int) float64 {
+	if witnesses < 1 {
+		return base
+	}
+	band := 1/math.Sqrt(ReferenceWitnesses) - 1/math.Sqrt(float64(witnesses))
+	return base * (1 + SmallSampleFactor*band)
+}
+
+// IntervalZ90 is the normal quantile for a two sided 90% interval
+const IntervalZ90 = 1.645
+
+// IntervalWidth returns the width of the alive interval
+// 1 = no idea, 0 = fully pinned down
+func (r AggregateResult) IntervalWidth() float64 {
+	return r.AliveInterval[1] - r.AliveInterval[0]
+}
+
+// credibleInterval approximates a 90% Beta(alpha, beta) interval
+// Spread comes from the Beta variance, centred on the point estimate so the
+// interval always contains it even after P10/P11 discounting
+func credibleInterval(point, alpha, beta float64) [2]float64 {
+	n := alpha + beta
+	if n <= 0 {
+		return [2]float64{0, 1}
+	}
+	sd := math.Sqrt(alpha * beta / (n * n * (n + 1)))
+	return [2]float64{
+		math.Max(0, point-IntervalZ90*sd),
+		math.Min(1, point+IntervalZ90*sd),
+	}
+}
+
+// floored returns b with unknown raised to types.UnknownFloor, P8 holds for
+// a lone report as for an aggregate
+func floored(b types.Belief) types.Belief {
+	alive, dead, unknown := types.ApplyUnknownFloor(b.Alive().Value(), b.Dead().Value(), b.Unknown().Value())
+	if fb, err := types.NewBelief(alive, dead, unknown); err == nil {
+		return fb
+	}
+	return b
 }
 
 // Aggregate combines multiple witness reports
 // P10: Disagreement preserved - we track it, dont hide it
 // P11: Correlated witnesses (similar reports) reduce confidence
 func (a *Aggregator) Aggregate(reports []WitnessReport) AggregateResult {
+	// Forged or tampered reports never vote
+	reports = a.withTrust(a.verified(reports))
+
 	if len(reports) == 0 {
 		return AggregateResult{
-			Belief: types.UnknownBelief(),
+			Belief:        types.UnknownBelief(),
+			AliveInterval: [2]float64{0, 1},
+			DeadInterval:  [2]float64{0, 1},
 		}
 	}
 
 	if len(reports) == 1 {
-		return AggregateResult{
-			Belief:       reports[0].Belief,
-			Disagreement: 0,
-			WitnessCount: 1,
-			Reports:      reports,
+		b := floored(reports[0].Belief)
+		trust := a.reportWeight(reports[0], nil, nil)
+		alpha, beta := b.Alive().Value()*trust, b.Dead().Value()*trust
+		result := AggregateResult{
+			Belief:        b,
+			Disagreement:  0,
+			WitnessCount:  1,
+			Reports:       reports,
+			AliveInterval: credibleInterval(b.Alive().Value(), alpha, beta),
+			DeadInterval:  credibleInterval(b.Dead().Value(), beta, alpha),
 		}
+		var sums beliefSums
+		sums.addReport(b, trust)
+		result.setMasses(sums)
+		return result
 	}
 
 	// Calculate weighted average of beliefs
-	var totalWeight float64
-	var aliveSum, deadSum, unknownSum float64
-
-	for _, r := range reports {
-		trust := float64(a.registry.GetTrust(r.Witness))
-		totalWeight += trust
+	var present []types.NodeID
+	if a.collusion != nil {
+		present = make([]types.NodeID, len(reports))
+		for i, r := range reports {
+			present[i] = r.Witness
+		}
+	}
 
-		aliveSum += r.Belief.Alive().Value() * trust
-		deadSum += r.Belief.Dead().Value() * trust
-		unknownSum += r.Belief.Unknown().Value() * trust
+	scale := a.shareScale(reports, present)
+	parallel := a.parallelThreshold > 0 && len(reports) > a.parallelThreshold
+	var sums beliefSums
+	if parallel {
+		sums = parallelReduce(reports, func(part []WitnessReport) beliefSums {
+			return a.weightedSums(part, present, scale)
+		}, beliefSums.add)
+	} else {
+		sums = a.weightedSums(reports, present, scale)
 	}
+	totalWeight, aliveSum, deadSum, unknownSum := sums.weight, sums.alive, sums.dead, sums.unknown
 
+	unknown := AggregateResult{
+		Belief:        types.UnknownBelief(),
+		WitnessCount:  len(reports),
+		Reports:       reports,
+		AliveInterval: [2]float64{0, 1},
+		DeadInterval:  [2]float64{0, 1},
+	}
+	if !finite(totalWeight, aliveSum, deadSum, unknownSum) {
+		unknown.Diagnostic = DiagNonFiniteWeight
+		return unknown
+	}
+	// the belief may be unusable but who voted for what is still known
+	unknown.setMasses(sums)
 	if totalWeight < 0.001 {
-		return AggregateResult{
-			Belief:       types.UnknownBelief(),
-			WitnessCount: len(reports),
-			Reports:      reports,
-		}
+		unknown.Diagnostic = DiagZeroWeight
+		return unknown
 	}
 
 	avgAlive := aliveSum / totalWeight
 	avgDead := deadSum / totalWeight
 	avgUnknown := unknownSum / totalWeight
+	if a.mode == ModeWeightedMedian {
+		avgAlive, avgDead, avgUnknown = a.weightedMedian(reports, present, scale)
+	}
+	if !finite(avgAlive, avgDead, avgUnknown) {
+		unknown.Diagnostic = DiagNonFiniteBelief
+		return unknown
+	}
 
 	// P10: Calculate disagreement (variance across witnesses)
-	disagreement := a.calculateDisagreement(reports, avgAlive, avgDead)
-
 	// P11: Correlated witnesses reduce confidence
+	var disagreement, correlation float64
+	if parallel {
+		disagreement, correlation = a.parallelSpread(reports, avgAlive, avgDead)
+	} else {
+		disagreement = a.calculateDisagreement(reports, avgAlive, avgDead)
+		correlation = a.detectCorrelation(reports)
+	}
+	var diagnostic string
+	if !finite(correlation) {
+		// treat as uncorrelated, disagreement still widens the result
+		correlation = 0
+		diagnostic = DiagNonFiniteSpread
+	}
+	if !finite(disagreement) {
+		disagreement = 1
+		diagnostic = DiagNonFiniteSpread
+	}
+
 	// If witnesses are too similar, increase unknown
-	correlation := a.detectCorrelation(reports)
 	if correlation > 0.9 {
 		// Too correlated - reduce confidence
 		factor := 0.7
@@ -94,7 +343,7 @@ func (a *Aggregator) Aggregate(reports []WitnessReport) AggregateResult {
 	}
 
 	// P10: High disagreement increases unknown
-	if disagreement > 0.3 {
+	if disagreement > AdaptiveThreshold(DisagreementThreshold, len(reports)) {
 		// Significant disagreement - widen uncertainty
 		reduction := disagreement * 0.5
 		avgAlive *= (1 - reduction)
@@ -103,24 +352,189 @@ func (a *Aggregator) Aggregate(reports []WitnessReport) AggregateResult {
 	}
 
 	// Ensure valid belief
-	if avgUnknown < 0.05 {
-		avgUnknown = 0.05
-		excess := 0.05 - (1.0 - avgAlive - avgDead)
-		avgAlive -= excess / 2
-		avgDead -= excess / 2
-	}
+	avgAlive, avgDead, avgUnknown = types.ApplyUnknownFloor(avgAlive, avgDead, avgUnknown)
 
 	belief, err := types.NewBelief(avgAlive, avgDead, avgUnknown)
 	if err != nil {
 		belief = types.UnknownBelief()
+		diagnostic = DiagInvalidBelief
+	}
+
+	result := AggregateResult{
+		Belief:        belief,
+		Disagreement:  disagreement,
+		WitnessCount:  len(reports),
+		Reports:       reports,
+		AliveInterval: credibleInterval(belief.Alive().Value(), aliveSum, deadSum),
+		DeadInterval:  credibleInterval(belief.Dead().Value(), deadSum, aliveSum),
+		Diagnostic:    diagnostic,
+	}
+	result.setMasses(sums)
+	return result
+}
+
+// Aggregation diagnostics, see AggregateResult.Diagnostic
+const (
+	DiagZeroWeight      = "total witness weight is zero"
+	DiagNonFiniteWeight = "witness weights are not finite"
+	DiagNonFiniteBelief = "averaged belief is not finite"
+	DiagNonFiniteSpread = "disagreement or correlation is not finite"
+	DiagInvalidBelief   = "averaged belief is invalid"
+)
+
+// finite reports whether every value is neither NaN nor infinite
+func finite(values ...float64) bool {
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// beliefSums are trust weighted belief totals
+type beliefSums struct {
+	weight, alive, dead, unknown float64
+	// weight of reports by dominant state, see AggregateResult.AliveMass
+	aliveBloc, deadBloc, abstainBloc float64
+}
+
+func (s beliefSums) add(o beliefSums) beliefSums {
+	return beliefSums{
+		s.weight + o.weight, s.alive + o.alive, s.dead + o.dead, s.unknown + o.unknown,
+		s.aliveBloc + o.aliveBloc, s.deadBloc + o.deadBloc, s.abstainBloc + o.abstainBloc,
+	}
+}
+
+// addReport folds one belief with weight into the sums
+func (s *beliefSums) addReport(b types.Belief, weight float64) {
+	s.weight += weight
+	s.alive += b.Alive().Value() * weight
+	s.dead += b.Dead().Value() * weight
+	s.unknown += b.Unknown().Value() * weight
+	switch b.Dominant() {
+	case types.StateAlive:
+		s.aliveBloc += weight
+	case types.StateDead:
+		s.deadBloc += weight
+	default:
+		s.abstainBloc += weight
 	}
+}
 
-	return AggregateResult{
-		Belief:       belief,
-		Disagreement: disagreement,
-		WitnessCount: len(reports),
-		Reports:      reports,
+// weightedSums totals beliefs weighted by witness trust
+// scale, if set, holds the per witness share cap factors
+func (a *Aggregator) weightedSums(reports []WitnessReport, present []types.NodeID, scale map[types.NodeID]float64) beliefSums {
+	var sums beliefSums
+	for _, r := range reports {
+		sums.addReport(r.Belief, a.reportWeight(r, present, scale))
 	}
+	return sums
+}
+
+// reportWeight is the aggregation weight of one report
+func (a *Aggregator) reportWeight(r WitnessReport, present []types.NodeID, scale map[types.NodeID]float64) float64 {
+	trust := float64(r.EffectiveTrust)
+	if a.collusion != nil {
+		// P11: lockstep witnesses share one witness worth of weight
+		trust *= a.collusion.Penalty(r.Witness, present)
+	}
+	if scale != nil {
+		trust *= scale[r.Witness]
+	}
+	// noisy or self declared unsure observations count for less
+	return trust * r.reliability()
+}
+
+// parallelSpread computes disagreement and correlation like
+// calculateDisagreement and detectCorrelation, one chunk per CPU
+func (a *Aggregator) parallelSpread(reports []WitnessReport, avgAlive, avgDead float64) (disagreement, correlation float64) {
+	first := reports[0].Belief
+	type spread struct{ variance, diff float64 }
+
+	total := parallelReduce(reports, func(part []WitnessReport) spread {
+		var s spread
+		for _, r := range part {
+			alive, dead := r.Belief.Alive().Value(), r.Belief.Dead().Value()
+			s.variance += (alive-avgAlive)*(alive-avgAlive) + (dead-avgDead)*(dead-avgDead)
+			// first vs itself adds zero, no need to skip it
+			s.diff += math.Abs(first.Alive().Value()-alive) + math.Abs(first.Dead().Value()-dead)
+		}
+		return s
+	}, func(x, y spread) spread { return spread{x.variance + y.variance, x.diff + y.diff} })
+
+	disagreement = math.Min(math.Sqrt(total.variance/float64(len(reports))), 1.0)
+	avgDiff := total.diff / float64(len(reports)-1)
+	correlation = 1.0 - math.Min(avgDiff*2, 1.0)
+	return disagreement, correlation
+}
+
+// parallelReduce maps contiguous chunks of reports on separate goroutines
+// and merges the partial results in chunk order
+func parallelReduce[T any](reports []WitnessReport, mapFn func([]WitnessReport) T, merge func(T, T) T) T {
+	workers := runtime.GOMAXPROCS(0)
+	chunk := (len(reports) + workers - 1) / workers
+	parts := make([]T, (len(reports)+chunk-1)/chunk)
+
+	var wg sync.WaitGroup
+	for i := range parts {
+		lo := i * chunk
+		hi := min(lo+chunk, len(reports))
+		wg.Add(1)
+		go func(i int, part []WitnessReport) {
+			defer wg.Done()
+			parts[i] = mapFn(part)
+		}(i, reports[lo:hi])
+	}
+	wg.Wait()
+
+	result := parts[0]
+	for _, p := range parts[1:] {
+		result = merge(result, p)
+	}
+	return result
+}
+
+// verified drops reports failing signature verification
+// a signature from a witness without a key has nothing to fail against,
+// the report counts like an unsigned one
+// returns the input slice untouched when every report passes
+func (a *Aggregator) verified(reports []WitnessReport) []WitnessReport {
+	if a.preverified {
+		return reports
+	}
+	for i, r := range reports {
+		if a.passes(r) {
+			continue
+		}
+		kept := append(make([]WitnessReport, 0, len(reports)), reports[:i]...)
+		for _, rest := range reports[i+1:] {
+			if a.passes(rest) {
+				kept = append(kept, rest)
+			}
+		}
+		return kept
+	}
+	return reports
+}
+
+func (a *Aggregator) passes(r WitnessReport) bool {
+	err := a.registry.VerifyReport(r)
+	return err == nil || errors.Is(err, ErrUnknownSigner)
+}
+
+// withTrust returns a copy of reports with EffectiveTrust filled in
+// the input is never modified, callers may share it
+func (a *Aggregator) withTrust(reports []WitnessReport) []WitnessReport {
+	if len(reports) == 0 {
+		return reports
+	}
+	stamped := make([]WitnessReport, len(reports))
+	for i, r := range reports {
+		r.EffectiveTrust = a.registry.GetTrust(r.Witness)
+		stamped[i] = r
+	}
+	return stamped
 }
 
 // calculateDisagreement measures variance in witness opinions
@@ -137,6 +551,10 @@ func (a *Aggregator) calculateDisagreement(reports []WitnessReport, avgAlive, av
 		variance += diffAlive*diffAlive + diffDead*diffDead
 	}
 	variance /= float64(len(reports))
+	if !finite(variance) {
+		// non-finite inputs, assume the worst rather than hide it
+		return 1.0
+	}
 
 	// Normalize to [0,1]
 	return math.Min(math.Sqrt(variance), 1.0)
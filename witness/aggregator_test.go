@@ -0,0 +1,429 @@
+package witness
+
+import (
+	"math"
+	"testing"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+func setUnknownFloor(t *testing.T, floor float64) {
+	t.Helper()
+	prev := types.UnknownFloor()
+	if err := types.SetUnknownFloor(floor); err != nil {
+		t.Fatalf("SetUnknownFloor(%f): %v", floor, err)
+	}
+	t.Cleanup(func() { types.SetUnknownFloor(prev) })
+}
+
+func report(witness uint64, alive, dead, unknown float64) WitnessReport {
+	return WitnessReport{
+		Witness: types.NewNodeID(witness),
+		Target:  types.NewNodeID(99),
+		Belief:  types.MustBelief(alive, dead, unknown),
+	}
+}
+
+// TestAggregateHonorsUnknownFloor checks Property 8 with a raised floor
+func TestAggregateHonorsUnknownFloor(t *testing.T) {
+	setUnknownFloor(t, 0.5)
+
+	agg := NewAggregator(NewRegistry())
+	result := agg.Aggregate([]WitnessReport{
+		report(1, 0.99, 0.0, 0.01),
+		report(2, 0.7, 0.29, 0.01),
+		report(3, 0.98, 0.01, 0.01),
+	})
+
+	if result.Belief.Unknown().Value() < 0.5-types.BeliefSumEpsilon {
+		t.Errorf("unknown below floor: %f", result.Belief.Unknown().Value())
+	}
+	if !result.Belief.IsValid() {
+		t.Errorf("invalid belief: %s", result.Belief)
+	}
+}
+
+func TestAggregateFloorsSingleReport(t *testing.T) {
+	setUnknownFloor(t, 0.2)
+
+	result := NewAggregator(NewRegistry()).Aggregate([]WitnessReport{report(1, 0.99, 0.0, 0.01)})
+	if u := result.Belief.Unknown().Value(); u < 0.2-types.BeliefSumEpsilon {
+		t.Errorf("lone report unknown %f below floor", u)
+	}
+	if !result.Belief.IsValid() || result.Belief.Dominant() != types.StateAlive {
+		t.Errorf("floored lone report %s", result.Belief)
+	}
+}
+
+func TestSetUnknownFloorRejectsInvalid(t *testing.T) {
+	for _, floor := range []float64{0, -0.1, 1.0, 1.5} {
+		if err := types.SetUnknownFloor(floor); err == nil {
+			t.Errorf("expected error for floor %f", floor)
+		}
+	}
+	if types.UnknownFloor() != types.DefaultUnknownFloor {
+		t.Errorf("floor changed by rejected values: %f", types.UnknownFloor())
+	}
+}
+
+func agreeingReports(n int) []WitnessReport {
+	reports := make([]WitnessReport, n)
+	for i := range reports {
+		reports[i] = report(uint64(i+1), 0.8, 0.1, 0.1)
+	}
+	return reports
+}
+
+func TestAggregateIntervalNarrowsWithWitnesses(t *testing.T) {
+	agg := NewAggregator(NewRegistry())
+
+	few := agg.Aggregate(agreeingReports(2))
+	many := agg.Aggregate(agreeingReports(20))
+
+	if many.IntervalWidth() >= few.IntervalWidth() {
+		t.Errorf("20 witnesses width %f not narrower than 2 witnesses %f",
+			many.IntervalWidth(), few.IntervalWidth())
+	}
+}
+
+func TestAggregateIntervalContainsPointEstimate(t *testing.T) {
+	agg := NewAggregator(NewRegistry())
+	cases := [][]WitnessReport{
+		nil,
+		{report(1, 0.9, 0.05, 0.05)},
+		{report(1, 0.9, 0.05, 0.05), report(2, 0.05, 0.9, 0.05)},
+		{report(1, 0.7, 0.2, 0.1), report(2, 0.6, 0.1, 0.3), report(3, 0.2, 0.2, 0.6)},
+		agreeingReports(50),
+	}
+
+	for i, reports := range cases {
+		r := agg.Aggregate(reports)
+		alive, dead := r.Belief.Alive().Value(), r.Belief.Dead().Value()
+		if alive < r.AliveInterval[0] || alive > r.AliveInterval[1] {
+			t.Errorf("case %d: alive %f outside %v", i, alive, r.AliveInterval)
+		}
+		if dead < r.DeadInterval[0] || dead > r.DeadInterval[1] {
+			t.Errorf("case %d: dead %f outside %v", i, dead, r.DeadInterval)
+		}
+	}
+}
+
+// splitAtScale builds n reports where a third dissent mildly
+func splitAtScale(n int) []WitnessReport {
+	reports := make([]WitnessReport, n)
+	for i := range reports {
+		if i%3 == 2 {
+			reports[i] = report(uint64(i+1), 0.3, 0.6, 0.1)
+		} else {
+			reports[i] = report(uint64(i+1), 0.7, 0.2, 0.1)
+		}
+	}
+	return reports
+}
+
+func TestAggregateDisagreementThresholdAdaptsToWitnessCount(t *testing.T) {
+	agg := NewAggregator(NewRegistry())
+	small := agg.Aggregate(splitAtScale(3))
+	large := agg.Aggregate(splitAtScale(300))
+
+	if math.Abs(small.Disagreement-large.Disagreement) > 1e-9 {
+		t.Fatalf("fixture should disagree equally: %f vs %f", small.Disagreement, large.Disagreement)
+	}
+	// Same proportional split: 3 witnesses widen uncertainty, 300 do not
+	if small.Belief.Unknown().Value() <= large.Belief.Unknown().Value() {
+		t.Errorf("small set unknown %f not wider than large set %f",
+			small.Belief.Unknown().Value(), large.Belief.Unknown().Value())
+	}
+}
+
+func TestAdaptiveThreshold(t *testing.T) {
+	if got := AdaptiveThreshold(0.4, 0); got != 0.4 {
+		t.Errorf("no witnesses: got %f, want base", got)
+	}
+	if got := AdaptiveThreshold(0.4, ReferenceWitnesses); math.Abs(got-0.4) > 1e-12 {
+		t.Errorf("n=%d: got %f, want base", ReferenceWitnesses, got)
+	}
+	prev := 0.0
+	for _, n := range []int{1, 3, 10, 100, 1000} {
+		got := AdaptiveThreshold(0.4, n)
+		if got <= prev || got >= 0.4*(1+SmallSampleFactor/6) {
+			t.Errorf("n=%d: threshold %f not increasing within its band", n, got)
+		}
+		prev = got
+	}
+	// large clusters need a bigger minority than base
+	if got := AdaptiveThreshold(0.4, 1000); got <= 0.4 {
+		t.Errorf("n=1000: threshold %f did not tighten past base", got)
+	}
+}
+
+func TestMaxWitnessShareStopsSingleWitnessOverride(t *testing.T) {
+	reg := NewRegistry()
+	target := types.NewNodeID(100)
+	loud := types.NewNodeID(1)
+	for i := 0; i < 5; i++ {
+		reg.RecordCorrect(loud)
+	}
+	reports := []WitnessReport{{Witness: loud, Target: target, Belief: types.MustBelief(0.95, 0.03, 0.02)}}
+	for i, b := range []types.Belief{
+		types.MustBelief(0.05, 0.9, 0.05),
+		types.MustBelief(0.1, 0.85, 0.05),
+		types.MustBelief(0.02, 0.9, 0.08),
+	} {
+		id := types.NewNodeID(uint64(10 + i))
+		for j := 0; j < 5; j++ {
+			reg.RecordWrong(id)
+		}
+		reports = append(reports, WitnessReport{Witness: id, Target: target, Belief: b})
+	}
+	if reg.GetTrust(loud) != MaxTrust {
+		t.Fatalf("loud witness trust %v, want MaxTrust", reg.GetTrust(loud))
+	}
+
+	// Uncapped, 1.0 trust outweighs three witnesses at 0.3
+	if b := NewAggregator(reg).Aggregate(reports).Belief; b.Alive().Value() <= b.Dead().Value() {
+		t.Fatalf("uncapped: expected the trusted witness to win, got %s", b)
+	}
+
+	b := NewAggregator(reg).WithMaxWitnessShare(0.4).Aggregate(reports).Belief
+	if b.Dead().Value() <= b.Alive().Value() {
+		t.Errorf("capped: one witness still overrides three, got %s", b)
+	}
+}
+
+func TestCapSharesBoundsEveryWitness(t *testing.T) {
+	weights := map[types.NodeID]float64{
+		types.NewNodeID(1): 10,
+		types.NewNodeID(2): 5,
+		types.NewNodeID(3): 1,
+		types.NewNodeID(4): 1,
+	}
+	capped := capShares(weights, 0.3)
+	var total float64
+	for _, w := range capped {
+		total += w
+	}
+	for id, w := range capped {
+		if w > 0.3*total+1e-9 {
+			t.Errorf("%s holds %.3f of the weight, cap 0.3", id, w/total)
+		}
+	}
+	if capped[types.NewNodeID(3)] != 1 {
+		t.Errorf("light witness weight changed: %f", capped[types.NewNodeID(3)])
+	}
+}
+
+func TestLowQualityReportCountsLess(t *testing.T) {
+	target := types.NewNodeID(100)
+	alive := types.MustBelief(0.85, 0.05, 0.1)
+	dead := types.MustBelief(0.05, 0.85, 0.1)
+	agg := NewAggregator(NewRegistry())
+
+	clean := WitnessReport{Witness: types.NewNodeID(1), Target: target, Belief: alive, Quality: 1}
+	erratic := WitnessReport{Witness: types.NewNodeID(2), Target: target, Belief: dead, Quality: 0.5}
+	b := agg.Aggregate([]WitnessReport{clean, erratic}).Belief
+	if b.Alive().Value() <= b.Dead().Value() {
+		t.Errorf("erratic report weighed as much as the clean one: %s", b)
+	}
+
+	// same belief swapped, the clean report wins again
+	clean.Belief, erratic.Belief = dead, alive
+	b = agg.Aggregate([]WitnessReport{clean, erratic}).Belief
+	if b.Dead().Value() <= b.Alive().Value() {
+		t.Errorf("erratic report weighed as much as the clean one: %s", b)
+	}
+
+	if (WitnessReport{}).QualityFactor() != 1 {
+		t.Error("unreported quality should count fully")
+	}
+}
+
+func TestWideSelfReportedIntervalCountsLess(t *testing.T) {
+	target := types.NewNodeID(100)
+	agg := NewAggregator(NewRegistry())
+	alive := types.MustBelief(0.8, 0.1, 0.1)
+	dissent := WitnessReport{Witness: types.NewNodeID(2), Target: target, Belief: types.MustBelief(0.1, 0.8, 0.1)}
+
+	with := func(interval [2]float64) types.Belief {
+		r := WitnessReport{Witness: types.NewNodeID(1), Target: target, Belief: alive, AliveInterval: interval}
+		return agg.Aggregate([]WitnessReport{r, dissent}).Belief
+	}
+	wide := with([2]float64{0.6, 0.9})
+	narrow := with([2]float64{0.79, 0.81})
+	point := with([2]float64{})
+
+	if wide.Alive().Value() >= narrow.Alive().Value() {
+		t.Errorf("wide interval alive %.3f not below narrow %.3f", wide.Alive().Value(), narrow.Alive().Value())
+	}
+	if narrow.Alive().Value() > point.Alive().Value() {
+		t.Errorf("narrow interval outweighs a point estimate: %.3f > %.3f", narrow.Alive().Value(), point.Alive().Value())
+	}
+	if f := (WitnessReport{AliveInterval: [2]float64{0.9, 0.6}}).IntervalFactor(); f != 1 {
+		t.Errorf("malformed interval factor %f, want 1", f)
+	}
+}
+
+func TestWeightedMedianIgnoresLowTrustOutlier(t *testing.T) {
+	reg := NewRegistry()
+	target := types.NewNodeID(100)
+	reports := []WitnessReport{
+		{Witness: types.NewNodeID(1), Target: target, Belief: types.MustBelief(0.8, 0.1, 0.1)},
+		{Witness: types.NewNodeID(2), Target: target, Belief: types.MustBelief(0.82, 0.08, 0.1)},
+		{Witness: types.NewNodeID(3), Target: target, Belief: types.MustBelief(0.78, 0.12, 0.1)},
+		{Witness: types.NewNodeID(4), Target: target, Belief: types.MustBelief(0.0, 0.99, 0.01)},
+	}
+	for i := 0; i < 10; i++ {
+		reg.RecordWrong(types.NewNodeID(4))
+	}
+
+	mean := NewAggregator(reg).Aggregate(reports)
+	median := NewAggregator(reg).WithMode(ModeWeightedMedian).Aggregate(reports)
+
+	// P10 still widens uncertainty for the dissent, but the direction is
+	// the trusted majority's 0.8 : 0.1
+	ratio := func(b types.Belief) float64 { return b.Alive().Value() / b.Dead().Value() }
+	if r := ratio(median.Belief); math.Abs(r-8) > 1e-6 {
+		t.Errorf("median alive:dead %.3f, want the majority's 8", r)
+	}
+	if ratio(mean.Belief) >= ratio(median.Belief) {
+		t.Errorf("mean %s not dragged by the outlier like median %s", mean.Belief, median.Belief)
+	}
+}
+
+func TestWeightedMedianOf(t *testing.T) {
+	if m := weightedMedianOf([]float64{0.1, 0.9}, []float64{1, 1}); m != 0.5 {
+		t.Errorf("tie median %f, want 0.5", m)
+	}
+	if m := weightedMedianOf([]float64{0.9, 0.1, 0.5}, []float64{3, 1, 1}); m != 0.9 {
+		t.Errorf("heavy value median %f, want 0.9", m)
+	}
+}
+
+func TestMostAndLeastTrusted(t *testing.T) {
+	reg := NewRegistry()
+	trusted, shaky := types.NewNodeID(1), types.NewNodeID(2)
+	for i := 0; i < 4; i++ {
+		reg.RecordCorrect(trusted) // 0.8 -> 1.0
+	}
+	for i := 0; i < 3; i++ {
+		reg.RecordWrong(shaky) // 0.8 -> 0.5
+	}
+	reg.Register(types.NewNodeID(3)) // default trust
+
+	reports := []WitnessReport{
+		report(3, 0.7, 0.2, 0.1),
+		report(1, 0.8, 0.1, 0.1),
+		report(2, 0.6, 0.3, 0.1),
+	}
+	result := NewAggregator(reg).Aggregate(reports)
+
+	most, trust := result.MostTrusted()
+	if most.Witness != trusted || math.Abs(float64(trust)-float64(MaxTrust)) > 1e-9 {
+		t.Errorf("MostTrusted = %s (%v), want %s (%v)", most.Witness, trust, trusted, MaxTrust)
+	}
+	least, trust := result.LeastTrusted()
+	if least.Witness != shaky || math.Abs(float64(trust)-0.5) > 1e-9 {
+		t.Errorf("LeastTrusted = %s (%v), want %s (0.5)", least.Witness, trust, shaky)
+	}
+	if reports[1].EffectiveTrust != 0 {
+		t.Error("Aggregate modified the caller's reports")
+	}
+
+	if r, trust := (AggregateResult{}).MostTrusted(); trust != 0 || r.Witness != (types.NodeID{}) {
+		t.Errorf("empty result: got %v, %v", r, trust)
+	}
+}
+
+func TestAggregateGuardsNonFiniteMath(t *testing.T) {
+	setTrust := func(reg *Registry, id uint64, trust float64) {
+		reg.Register(types.NewNodeID(id))
+		reg.witnesses[types.NewNodeID(id)].Trust = TrustScore(trust)
+	}
+	reports := []WitnessReport{report(1, 0.8, 0.1, 0.1), report(2, 0.7, 0.2, 0.1)}
+
+	zero := NewRegistry()
+	setTrust(zero, 1, 0)
+	setTrust(zero, 2, 0)
+	result := NewAggregator(zero).Aggregate(reports)
+	if result.Diagnostic != DiagZeroWeight || !result.Belief.Equal(types.UnknownBelief()) {
+		t.Errorf("zero-trust cohort: got %q %v", result.Diagnostic, result.Belief)
+	}
+
+	inf := NewRegistry()
+	setTrust(inf, 1, math.Inf(1))
+	setTrust(inf, 2, 0.8)
+	result = NewAggregator(inf).Aggregate(reports)
+	if result.Diagnostic != DiagNonFiniteWeight || !result.Belief.IsValid() {
+		t.Errorf("infinite trust: got %q %v", result.Diagnostic, result.Belief)
+	}
+
+	// Identical beliefs max out correlation, which is legitimate math
+	same := []WitnessReport{report(1, 0.8, 0.1, 0.1), report(2, 0.8, 0.1, 0.1), report(3, 0.8, 0.1, 0.1)}
+	result = NewAggregator(NewRegistry()).Aggregate(same)
+	if result.Diagnostic != "" || math.IsNaN(result.Disagreement) {
+		t.Errorf("identical beliefs: got %q disagreement %v", result.Diagnostic, result.Disagreement)
+	}
+
+	a := NewAggregator(NewRegistry())
+	if d := a.calculateDisagreement(reports, math.NaN(), 0.1); d != 1 {
+		t.Errorf("NaN average: disagreement %v, want 1", d)
+	}
+}
+
+func TestAggregateMassesMatchComposition(t *testing.T) {
+	reports := []WitnessReport{
+		report(1, 0.9, 0.05, 0.05),
+		report(2, 0.85, 0.05, 0.1),
+		report(3, 0.8, 0.1, 0.1),
+		report(4, 0.05, 0.9, 0.05),
+	}
+	for i := range 6 {
+		reports = append(reports, report(uint64(10+i), 0.1+0.01*float64(i), 0.05, 0.85-0.01*float64(i)))
+	}
+
+	result := NewAggregator(NewRegistry()).Aggregate(reports)
+	want := [3]float64{0.3, 0.1, 0.6}
+	got := [3]float64{result.AliveMass, result.DeadMass, result.AbstainMass}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Fatalf("masses alive/dead/abstain %v, want %v", got, want)
+		}
+	}
+
+	// trust shifts the masses: a distrusted dissenter weighs less
+	reg := NewRegistry()
+	for range 7 {
+		reg.RecordWrong(types.NewNodeID(4))
+	}
+	result = NewAggregator(reg).Aggregate(reports[:4])
+	if result.DeadMass >= 0.25 || math.Abs(result.AliveMass+result.DeadMass+result.AbstainMass-1) > 1e-9 {
+		t.Errorf("masses %f/%f/%f after distrusting the dead witness", result.AliveMass, result.DeadMass, result.AbstainMass)
+	}
+
+	if single := NewAggregator(NewRegistry()).Aggregate(reports[:1]); single.AliveMass != 1 {
+		t.Errorf("single alive report: alive mass %f", single.AliveMass)
+	}
+
+	// a lone witness without trust carries no voting weight
+	distrusted := NewRegistry()
+	distrusted.Register(types.NewNodeID(1))
+	distrusted.witnesses[types.NewNodeID(1)].Trust = 0
+	if single := NewAggregator(distrusted).Aggregate(reports[:1]); single.AliveMass+single.DeadMass+single.AbstainMass != 0 {
+		t.Errorf("zero-trust single report has masses %f/%f/%f", single.AliveMass, single.DeadMass, single.AbstainMass)
+	}
+
+	// too little weight for a belief still splits the votes it has
+	faint := NewRegistry()
+	for _, id := range []uint64{1, 4} {
+		faint.Register(types.NewNodeID(id))
+		faint.witnesses[types.NewNodeID(id)].Trust = 0.0001
+	}
+	pair := []WitnessReport{reports[0], reports[3]}
+	if result := NewAggregator(faint).Aggregate(pair); result.Diagnostic != DiagZeroWeight ||
+		math.Abs(result.AliveMass-0.5) > 1e-9 || math.Abs(result.DeadMass-0.5) > 1e-9 {
+		t.Errorf("faint split: %q masses %f/%f", result.Diagnostic, result.AliveMass, result.DeadMass)
+	}
+	if empty := NewAggregator(NewRegistry()).Aggregate(nil); empty.AliveMass+empty.DeadMass+empty.AbstainMass != 0 {
+		t.Errorf("empty aggregation has masses %+v", empty)
+	}
+}
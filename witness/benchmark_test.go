@@ -0,0 +1,56 @@
+package witness
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// randomReports builds n reports with varied beliefs from distinct witnesses
+func randomReports(n int, seed int64) []WitnessReport {
+	rng := rand.New(rand.NewSource(seed))
+	reports := make([]WitnessReport, n)
+	for i := range reports {
+		alive := rng.Float64() * 0.9
+		dead := rng.Float64() * (0.95 - alive)
+		reports[i] = report(uint64(i+1), alive, dead, 1-alive-dead)
+	}
+	return reports
+}
+
+func TestParallelAggregateMatchesSequential(t *testing.T) {
+	reports := randomReports(10_000, 1)
+	reg := NewRegistry()
+
+	seq := NewAggregator(reg).WithParallelThreshold(0).Aggregate(reports)
+	par := NewAggregator(reg).WithParallelThreshold(1).Aggregate(reports)
+
+	const tol = 1e-9
+	pairs := []struct {
+		name     string
+		seq, par float64
+	}{
+		{"alive", seq.Belief.Alive().Value(), par.Belief.Alive().Value()},
+		{"dead", seq.Belief.Dead().Value(), par.Belief.Dead().Value()},
+		{"unknown", seq.Belief.Unknown().Value(), par.Belief.Unknown().Value()},
+		{"disagreement", seq.Disagreement, par.Disagreement},
+		{"alive interval low", seq.AliveInterval[0], par.AliveInterval[0]},
+	}
+	for _, p := range pairs {
+		if math.Abs(p.seq-p.par) > tol {
+			t.Errorf("%s: sequential %v, parallel %v", p.name, p.seq, p.par)
+		}
+	}
+}
+
+func benchmarkAggregate(b *testing.B, n, threshold int) {
+	reports := randomReports(n, 1)
+	agg := NewAggregator(NewRegistry()).WithParallelThreshold(threshold)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		agg.Aggregate(reports)
+	}
+}
+
+func BenchmarkAggregate10KSequential(b *testing.B) { benchmarkAggregate(b, 10_000, 0) }
+func BenchmarkAggregate10KParallel(b *testing.B)   { benchmarkAggregate(b, 10_000, 1) }
@@ -0,0 +1,109 @@
+package witness
+
+import "github.com/styx-oracle/styx/types"
+
+// WitnessClass groups witnesses sharing a trust floor
+type WitnessClass string
+
+const (
+	// ClassDefault witnesses bottom out at MinTrust
+	ClassDefault WitnessClass = ""
+	// ClassNoisy is a known flaky but useful witness, it keeps a modest
+	// floor so its observations never become irrelevant
+	ClassNoisy WitnessClass = "noisy"
+	// ClassSuspect is a witness suspected of lying, driven close to zero
+	// influence (but never zero)
+	ClassSuspect WitnessClass = "suspect"
+)
+
+const (
+	// NoisyMinTrust is the default floor of ClassNoisy
+	NoisyMinTrust TrustScore = 0.25
+	// SuspectMinTrust is the default floor of ClassSuspect
+	SuspectMinTrust TrustScore = 0.01
+)
+
+// defaultClassFloors are the floors of the built in classes
+var defaultClassFloors = map[WitnessClass]TrustScore{
+	ClassDefault: MinTrust,
+	ClassNoisy:   NoisyMinTrust,
+	ClassSuspect: SuspectMinTrust,
+}
+
+// SetClass puts a witness in class, its trust is lifted to the class floor
+// if it was below
+func (r *Registry) SetClass(id types.NodeID, class WitnessClass) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w := r.getOrCreate(id)
+	if w == nil {
+		return
+	}
+	w.Class = class
+	r.version++
+	if floor := r.floor(w); w.Trust < floor {
+		w.Trust = floor
+		w.smooth()
+	}
+}
+
+// Class returns the witness class, ClassDefault if unknown
+func (r *Registry) Class(id types.NodeID) WitnessClass {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if w, ok := r.witnesses[id]; ok {
+		return w.Class
+	}
+	return ClassDefault
+}
+
+// SetClassFloor overrides the trust floor of class, floors are capped at
+// MaxTrust and 0 or less restores the default
+// witnesses already below the new floor are lifted to it
+// classes without a default or override use MinTrust
+func (r *Registry) SetClassFloor(class WitnessClass, floor TrustScore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if floor <= 0 {
+		delete(r.classFloors, class)
+		r.version++
+		return
+	}
+	if r.classFloors == nil {
+		r.classFloors = make(map[WitnessClass]TrustScore)
+	}
+	floor = min(floor, MaxTrust)
+	r.classFloors[class] = floor
+	r.version++
+	for _, w := range r.witnesses {
+		if w.Class == class && w.Trust < floor {
+			w.Trust = floor
+			w.smooth()
+		}
+	}
+}
+
+// MinTrustFor returns the trust floor of a witness
+func (r *Registry) MinTrustFor(id types.NodeID) TrustScore {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if w, ok := r.witnesses[id]; ok {
+		return r.floor(w)
+	}
+	return MinTrust
+}
+
+// floor returns the trust floor of w, caller must hold r.mu
+func (r *Registry) floor(w *WitnessRecord) TrustScore {
+	if floor, ok := r.classFloors[w.Class]; ok {
+		return floor
+	}
+	if floor, ok := defaultClassFloors[w.Class]; ok {
+		return floor
+	}
+	return MinTrust
+}
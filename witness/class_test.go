@@ -0,0 +1,47 @@
+package witness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+func TestClassFloorsSeparateNoisyFromSuspect(t *testing.T) {
+	reg := NewRegistry()
+	reg.SetTrustHalfLife(time.Hour)
+	noisy, suspect, plain := types.NewNodeID(1), types.NewNodeID(2), types.NewNodeID(3)
+	reg.SetClass(noisy, ClassNoisy)
+	reg.SetClass(suspect, ClassSuspect)
+
+	start := time.Now()
+	for _, id := range []types.NodeID{noisy, suspect, plain} {
+		reg.RecordReportAt(id, types.UnknownBelief(), start)
+		for range 20 {
+			reg.RecordWrong(id)
+		}
+	}
+	reg.DecayByTime(start.Add(100 * time.Hour))
+
+	for id, want := range map[types.NodeID]TrustScore{noisy: NoisyMinTrust, suspect: SuspectMinTrust, plain: MinTrust} {
+		if got := reg.GetTrust(id); got != want {
+			t.Errorf("%s (%q): trust %v, want floor %v", id, reg.Class(id), got, want)
+		}
+		if got := reg.MinTrustFor(id); got != want {
+			t.Errorf("%s: MinTrustFor %v, want %v", id, got, want)
+		}
+	}
+	if reg.ScoreSummary().MinTrustReached != 3 {
+		t.Errorf("summary %+v, want all 3 at their floor", reg.ScoreSummary())
+	}
+
+	// raising a floor lifts witnesses already below it, 0 restores the default
+	reg.SetClassFloor(ClassSuspect, 0.05)
+	if got := reg.GetTrust(suspect); got != 0.05 {
+		t.Errorf("after raising the suspect floor trust %v, want 0.05", got)
+	}
+	reg.SetClassFloor(ClassSuspect, 0)
+	if got := reg.MinTrustFor(suspect); got != SuspectMinTrust {
+		t.Errorf("reset suspect floor %v, want %v", got, SuspectMinTrust)
+	}
+}
@@ -0,0 +1,287 @@
+package witness
+
+import (
+	"sync"
+
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+)
+
+// Defaults for collusion detection
+const (
+	// DefaultCollusionWindow is how close two reports must be to count as
+	// lockstep, in reports about the same target
+	DefaultCollusionWindow uint64 = 2
+	// DefaultCollusionMinTargets is how many targets a pair must co-report before being flagged
+	DefaultCollusionMinTargets = 3
+	// DefaultCollusionMinCoReports is how many lockstep reports a pair must
+	// make in total before being flagged, one round is not a habit
+	DefaultCollusionMinCoReports = 5
+	// DefaultCollusionMinWitnesses is how many witnesses a target needs
+	// before lockstep about it counts: with fewer, a steady round of honest
+	// reporters puts every pair within the window of each other
+	DefaultCollusionMinWitnesses = 2*int(DefaultCollusionWindow) + 2
+	// DefaultCollusionRatio is the fraction of a pair's targets that must be co-reported
+	DefaultCollusionRatio = 0.8
+	// DefaultCollusionHorizon is how long (logical time) a report keeps
+	// counting toward collusion, older ones are forgotten
+	DefaultCollusionHorizon uint64 = 4096
+)
+
+type witnessPair struct {
+	a, b types.NodeID
+}
+
+func makePair(x, y types.NodeID) witnessPair {
+	if x.Less(y) {
+		return witnessPair{a: x, b: y}
+	}
+	return witnessPair{a: y, b: x}
+}
+
+// targetTimeline is the report sequence of one target
+// seq counts reports about the target, last holds the seq of each witness
+// report still inside the window, witnesses when each witness last
+// reported about it
+type targetTimeline struct {
+	seq       uint64
+	at        styxtime.LogicalTimestamp
+	last      map[types.NodeID]uint64
+	witnesses map[types.NodeID]styxtime.LogicalTimestamp
+}
+
+// coReports is how often a pair reported about one target in lockstep and
+// when they last did
+type coReports struct {
+	at styxtime.LogicalTimestamp
+	n  int
+}
+
+// CollusionDetector flags witnesses whose reports arrive in lockstep
+// P11: Independent witnesses rarely report about many targets inside the
+// same tight window. Consistent lockstep timing across targets suggests a
+// shared upstream source, even when belief values differ
+// the window is counted in reports about each target, so traffic about
+// other targets neither hides nor fakes lockstep
+// lockstep only counts on targets with enough witnesses that adjacent
+// reports are not forced, and a pair needs repeated lockstep to be flagged
+type CollusionDetector struct {
+	mu           sync.RWMutex
+	window       uint64
+	minTargets   int
+	minCoReports int
+	minWitnesses int
+	ratio        float64
+	horizon      uint64
+	// target -> its report sequence
+	timelines map[types.NodeID]*targetTimeline
+	// witness -> target -> when it last reported about it
+	targets map[types.NodeID]map[types.NodeID]styxtime.LogicalTimestamp
+	// pair -> target -> their lockstep reports about it
+	together map[witnessPair]map[types.NodeID]coReports
+	// now is the latest report time seen, swept when entries were last expired
+	now, swept styxtime.LogicalTimestamp
+	// version counts changes that can move a penalty, see Version
+	version uint64
+}
+
+// NewCollusionDetector creates a detector with default thresholds
+func NewCollusionDetector() *CollusionDetector {
+	return &CollusionDetector{
+		window:       DefaultCollusionWindow,
+		minTargets:   DefaultCollusionMinTargets,
+		minCoReports: DefaultCollusionMinCoReports,
+		minWitnesses: DefaultCollusionMinWitnesses,
+		ratio:        DefaultCollusionRatio,
+		horizon:      DefaultCollusionHorizon,
+		timelines:    make(map[types.NodeID]*targetTimeline),
+		targets:      make(map[types.NodeID]map[types.NodeID]styxtime.LogicalTimestamp),
+		together:     make(map[witnessPair]map[types.NodeID]coReports),
+	}
+}
+
+// Observe records the timing of a report
+func (d *CollusionDetector) Observe(r WitnessReport) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if r.Timestamp > d.now {
+		d.now = r.Timestamp
+	}
+
+	reported := d.targets[r.Witness]
+	if reported == nil {
+		reported = make(map[types.NodeID]styxtime.LogicalTimestamp)
+		d.targets[r.Witness] = reported
+	}
+	if _, ok := reported[r.Target]; !ok {
+		d.version++
+	}
+	reported[r.Target] = d.now
+
+	tl := d.timelines[r.Target]
+	if tl == nil {
+		tl = &targetTimeline{
+			last:      make(map[types.NodeID]uint64),
+			witnesses: make(map[types.NodeID]styxtime.LogicalTimestamp),
+		}
+		d.timelines[r.Target] = tl
+	}
+	tl.seq++
+	tl.at = d.now
+	if _, ok := tl.witnesses[r.Witness]; !ok && len(tl.witnesses)+1 == d.minWitnesses {
+		// lockstep about the target starts to count
+		d.version++
+	}
+	tl.witnesses[r.Witness] = d.now
+
+	for other, seq := range tl.last {
+		if tl.seq-seq > d.window {
+			// out of the window for every later report too
+			delete(tl.last, other)
+			continue
+		}
+		if other == r.Witness {
+			continue
+		}
+		d.coReport(makePair(r.Witness, other), r.Target)
+	}
+	tl.last[r.Witness] = tl.seq
+
+	d.expire()
+}
+
+// coReport records a lockstep report of pair about target
+// caller must hold d.mu for writing
+func (d *CollusionDetector) coReport(pair witnessPair, target types.NodeID) {
+	together := d.together[pair]
+	if together == nil {
+		together = make(map[types.NodeID]coReports)
+		d.together[pair] = together
+	}
+	c := together[target]
+	together[target] = coReports{at: d.now, n: c.n + 1}
+	// a new target or reaching minCoReports can flag the pair
+	if c.n == 0 {
+		d.version++
+	} else if _, n := d.crowdedLockstep(together); n == d.minCoReports {
+		d.version++
+	}
+}
+
+// crowdedLockstep returns on how many targets a pair reported in lockstep
+// and how often, counting only targets with at least minWitnesses
+// witnesses in the horizon
+// caller must hold d.mu
+func (d *CollusionDetector) crowdedLockstep(together map[types.NodeID]coReports) (targets, reports int) {
+	for target, c := range together {
+		if tl := d.timelines[target]; tl == nil || len(tl.witnesses) < d.minWitnesses {
+			continue
+		}
+		targets++
+		reports += c.n
+	}
+	return targets, reports
+}
+
+// expire forgets reports older than the horizon, once per horizon
+// caller must hold d.mu for writing
+func (d *CollusionDetector) expire() {
+	if d.now < d.swept+styxtime.LogicalTimestamp(d.horizon) {
+		return
+	}
+	cutoff := d.now - styxtime.LogicalTimestamp(d.horizon)
+	d.swept = d.now
+
+	for target, tl := range d.timelines {
+		if tl.at < cutoff {
+			delete(d.timelines, target)
+			continue
+		}
+		if forgetBefore(tl.witnesses, cutoff) {
+			d.version++
+		}
+	}
+	for id, reported := range d.targets {
+		if forgetBefore(reported, cutoff) {
+			d.version++
+		}
+		if len(reported) == 0 {
+			delete(d.targets, id)
+		}
+	}
+	for pair, together := range d.together {
+		for target, c := range together {
+			if c.at < cutoff {
+				delete(together, target)
+				d.version++
+			}
+		}
+		if len(together) == 0 {
+			delete(d.together, pair)
+		}
+	}
+}
+
+// forgetBefore deletes the targets seen before cutoff, true if any was
+func forgetBefore(seen map[types.NodeID]styxtime.LogicalTimestamp, cutoff styxtime.LogicalTimestamp) bool {
+	forgot := false
+	for target, at := range seen {
+		if at < cutoff {
+			delete(seen, target)
+			forgot = true
+		}
+	}
+	return forgot
+}
+
+// Version changes whenever an observation can move a Penalty
+// callers caching aggregates compare it to tell whether they are stale
+func (d *CollusionDetector) Version() uint64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.version
+}
+
+// Colluding returns true if two witnesses consistently report in lockstep
+func (d *CollusionDetector) Colluding(x, y types.NodeID) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.colluding(x, y)
+}
+
+func (d *CollusionDetector) colluding(x, y types.NodeID) bool {
+	if x == y {
+		return false
+	}
+	shared, reports := d.crowdedLockstep(d.together[makePair(x, y)])
+	if shared < d.minTargets || reports < d.minCoReports {
+		return false
+	}
+	fewest := len(d.targets[x])
+	if n := len(d.targets[y]); n < fewest {
+		fewest = n
+	}
+	return float64(shared) >= d.ratio*float64(fewest)
+}
+
+// Penalty returns the weight factor for a witness given the other witnesses present
+// A witness colluding with k others gets 1/(k+1), so a lockstep group
+// carries the weight of a single independent witness
+func (d *CollusionDetector) Penalty(id types.NodeID, present []types.NodeID) float64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	colluders := 0
+	seen := make(map[types.NodeID]bool, len(present))
+	for _, other := range present {
+		if seen[other] {
+			continue
+		}
+		seen[other] = true
+		if d.colluding(id, other) {
+			colluders++
+		}
+	}
+	return 1.0 / float64(colluders+1)
+}
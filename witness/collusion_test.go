@@ -0,0 +1,189 @@
+package witness
+
+import (
+	"testing"
+
+	styxtime "github.com/styx-oracle/styx/time"
+	"github.com/styx-oracle/styx/types"
+)
+
+func timedReport(witness, target uint64, ts uint64, b types.Belief) WitnessReport {
+	return WitnessReport{
+		Witness:   types.NewNodeID(witness),
+		Target:    types.NewNodeID(target),
+		Belief:    b,
+		Timestamp: styxtime.LogicalTimestamp(ts),
+	}
+}
+
+// observeOthers has four witnesses seen only on target report about it,
+// pushing the next report out of the lockstep window and giving the target
+// enough witnesses for lockstep about it to count
+func observeOthers(d *CollusionDetector, target, ts uint64, b types.Belief) {
+	for k := range uint64(4) {
+		d.Observe(timedReport(1000*(k+1)+target, target, ts+k, b))
+	}
+}
+
+func TestCollusionDetectorFlagsLockstepPair(t *testing.T) {
+	d := NewCollusionDetector()
+	b := types.MustBelief(0.8, 0.1, 0.1)
+
+	// Witnesses 1 and 2 always report together, witness 3 reports on its own schedule
+	for target := uint64(100); target < 105; target++ {
+		base := target * 20
+		d.Observe(timedReport(1, target, base, b))
+		d.Observe(timedReport(2, target, base+1, b))
+		observeOthers(d, target, base+2, b)
+		d.Observe(timedReport(3, target, base+10, b))
+	}
+
+	w1, w2, w3 := types.NewNodeID(1), types.NewNodeID(2), types.NewNodeID(3)
+	if !d.Colluding(w1, w2) {
+		t.Error("synchronized pair not flagged")
+	}
+	if d.Colluding(w1, w3) || d.Colluding(w2, w3) {
+		t.Error("independent witness flagged as colluding")
+	}
+
+	present := []types.NodeID{w1, w2, w3}
+	if p := d.Penalty(w1, present); p != 0.5 {
+		t.Errorf("expected penalty 0.5 for colluder, got %f", p)
+	}
+	if p := d.Penalty(w3, present); p != 1.0 {
+		t.Errorf("expected no penalty for independent witness, got %f", p)
+	}
+}
+
+func TestAggregateDiscountsColludingWitnesses(t *testing.T) {
+	d := NewCollusionDetector()
+	alive := types.MustBelief(0.8, 0.1, 0.1)
+	dead := types.MustBelief(0.1, 0.8, 0.1)
+
+	var reports []WitnessReport
+	for target := uint64(100); target < 105; target++ {
+		base := target * 20
+		reports = []WitnessReport{
+			timedReport(1, target, base, dead),
+			timedReport(2, target, base+1, dead),
+			timedReport(3, target, base+10, alive),
+		}
+		for _, r := range reports[:2] {
+			d.Observe(r)
+		}
+		observeOthers(d, target, base+2, alive)
+		d.Observe(reports[2])
+	}
+
+	plain := NewAggregator(NewRegistry()).Aggregate(reports)
+	guarded := NewAggregator(NewRegistry()).WithCollusionDetector(d).Aggregate(reports)
+
+	if plain.Belief.Dead().Value() <= plain.Belief.Alive().Value() {
+		t.Fatalf("expected colluding majority to win without detection: %s", plain.Belief)
+	}
+	diff := guarded.Belief.Dead().Value() - guarded.Belief.Alive().Value()
+	if diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("colluding pair should weigh as one witness: %s", guarded.Belief)
+	}
+}
+
+func TestCollusionWindowIgnoresOtherTargets(t *testing.T) {
+	d := NewCollusionDetector()
+	b := types.MustBelief(0.8, 0.1, 0.1)
+
+	// a busy oracle stamps many reports about other targets between the pair
+	ts := uint64(1)
+	for target := uint64(100); target < 105; target++ {
+		d.Observe(timedReport(1, target, ts, b))
+		for other := uint64(500); other < 510; other++ {
+			ts++
+			d.Observe(timedReport(other, other, ts, b))
+		}
+		ts++
+		d.Observe(timedReport(2, target, ts, b))
+		observeOthers(d, target, ts+1, b)
+		ts += 5
+	}
+
+	if !d.Colluding(types.NewNodeID(1), types.NewNodeID(2)) {
+		t.Error("pair in lockstep on every target not flagged behind other traffic")
+	}
+}
+
+func TestCollusionDetectorForgetsOldPairs(t *testing.T) {
+	d := NewCollusionDetector()
+	b := types.MustBelief(0.8, 0.1, 0.1)
+
+	for target := uint64(100); target < 105; target++ {
+		d.Observe(timedReport(1, target, target, b))
+		d.Observe(timedReport(2, target, target, b))
+		observeOthers(d, target, target, b)
+	}
+	w1, w2 := types.NewNodeID(1), types.NewNodeID(2)
+	if !d.Colluding(w1, w2) {
+		t.Fatal("synchronized pair not flagged")
+	}
+	version := d.Version()
+
+	// only unrelated witnesses report for a whole horizon
+	for i := range uint64(2) {
+		ts := 105 + (i+1)*DefaultCollusionHorizon
+		d.Observe(timedReport(3, 200, ts, b))
+	}
+
+	if d.Colluding(w1, w2) {
+		t.Error("pair still flagged after the horizon")
+	}
+	if d.Version() == version {
+		t.Error("expiry did not bump the version")
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if len(d.together) != 0 || len(d.targets) != 1 || len(d.timelines) != 1 {
+		t.Errorf("kept %d pairs, %d witnesses, %d timelines", len(d.together), len(d.targets), len(d.timelines))
+	}
+}
+
+func TestCollusionIgnoresHonestRoundRobin(t *testing.T) {
+	b := types.MustBelief(0.8, 0.1, 0.1)
+
+	// up to DefaultCollusionMinWitnesses-1 witnesses taking turns in a fixed
+	// order are always within the window of each other
+	for _, n := range []uint64{3, uint64(DefaultCollusionMinWitnesses - 1)} {
+		d := NewCollusionDetector()
+		ts := uint64(1)
+		for round := 0; round < 10; round++ {
+			for target := uint64(100); target < 110; target++ {
+				for w := uint64(1); w <= n; w++ {
+					d.Observe(timedReport(w, target, ts, b))
+					ts++
+				}
+			}
+		}
+
+		present := make([]types.NodeID, n)
+		for i := range present {
+			present[i] = types.NewNodeID(uint64(i + 1))
+		}
+		for _, x := range present {
+			if p := d.Penalty(x, present); p != 1.0 {
+				t.Errorf("%d witnesses: round robin reporter %s penalized to %f", n, x, p)
+			}
+		}
+	}
+}
+
+func TestCollusionNeedsRepeatedLockstep(t *testing.T) {
+	d := NewCollusionDetector()
+	b := types.MustBelief(0.8, 0.1, 0.1)
+
+	// one lockstep round on each of DefaultCollusionMinTargets targets
+	for target := uint64(100); target < 100+DefaultCollusionMinTargets; target++ {
+		d.Observe(timedReport(1, target, target*20, b))
+		d.Observe(timedReport(2, target, target*20+1, b))
+		observeOthers(d, target, target*20+2, b)
+	}
+	if d.Colluding(types.NewNodeID(1), types.NewNodeID(2)) {
+		t.Errorf("pair flagged after %d lockstep reports, need %d", DefaultCollusionMinTargets, DefaultCollusionMinCoReports)
+	}
+}
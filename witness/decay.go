@@ -0,0 +1,67 @@
+package witness
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// SetTrustHalfLife makes trust of silent witnesses decay with wall clock
+// time, halving every halfLife without a report, see DecayByTime
+// 0 or less disables time based decay (the default)
+func (r *Registry) SetTrustHalfLife(halfLife time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.trustHalfLife = halfLife
+}
+
+// DecayByTime decays the trust of every witness by 0.5^(elapsed/halfLife),
+// elapsed being the time since its last report
+// P12: a witness gone silent loses trust like one that lies
+// decay already applied is not applied again, so calling this often
+// compounds to the same trust as calling it once, trust never drops below
+// the witness class floor and witnesses that never reported are left alone
+func (r *Registry) DecayByTime(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.trustHalfLife <= 0 {
+		return
+	}
+	for _, w := range r.witnesses {
+		if w.LastReportAt.IsZero() {
+			continue
+		}
+		since := w.LastReportAt
+		if w.decayedAt.After(since) {
+			since = w.decayedAt
+		}
+		elapsed := now.Sub(since)
+		if elapsed <= 0 {
+			continue
+		}
+		w.Trust = TrustScore(float64(w.Trust) * math.Pow(0.5, float64(elapsed)/float64(r.trustHalfLife)))
+		if floor := r.floor(w); w.Trust < floor {
+			w.Trust = floor
+		}
+		w.decayedAt = now
+		w.smooth()
+		r.version++
+	}
+}
+
+// StartAutoDecay runs DecayByTime every period until ctx is done
+func (r *Registry) StartAutoDecay(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				r.DecayByTime(now)
+			}
+		}
+	}()
+}
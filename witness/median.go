@@ -0,0 +1,82 @@
+package witness
+
+import (
+	"sort"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+// AggregationMode selects how witness beliefs are combined
+type AggregationMode int
+
+const (
+	// ModeWeightedMean averages beliefs weighted by trust (default)
+	ModeWeightedMean AggregationMode = iota
+	// ModeWeightedMedian takes the trust weighted median of alive and dead
+	// an extreme report moves the result only if it holds half the trust
+	ModeWeightedMedian
+)
+
+func (m AggregationMode) String() string {
+	switch m {
+	case ModeWeightedMean:
+		return "WEIGHTED_MEAN"
+	case ModeWeightedMedian:
+		return "WEIGHTED_MEDIAN"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// WithMode sets how beliefs are combined, ModeWeightedMean by default
+// disagreement, correlation and the unknown floor apply in every mode
+func (a *Aggregator) WithMode(m AggregationMode) *Aggregator {
+	a.mode = m
+	return a
+}
+
+// weightedMedian returns the trust weighted median alive and dead masses
+// unknown takes the rest, if the two medians sum past 1 they are scaled down
+func (a *Aggregator) weightedMedian(reports []WitnessReport, present []types.NodeID, scale map[types.NodeID]float64) (alive, dead, unknown float64) {
+	weights := make([]float64, len(reports))
+	aliveVals := make([]float64, len(reports))
+	deadVals := make([]float64, len(reports))
+	for i, r := range reports {
+		weights[i] = a.reportWeight(r, present, scale)
+		aliveVals[i] = r.Belief.Alive().Value()
+		deadVals[i] = r.Belief.Dead().Value()
+	}
+
+	alive = weightedMedianOf(aliveVals, weights)
+	dead = weightedMedianOf(deadVals, weights)
+	if sum := alive + dead; sum > 1 {
+		alive, dead = alive/sum, dead/sum
+	}
+	return alive, dead, 1 - alive - dead
+}
+
+// weightedMedianOf returns the smallest value at which the cumulative
+// weight reaches half the total, averaging the two middle values on an
+// exact tie
+func weightedMedianOf(values, weights []float64) float64 {
+	idx := make([]int, len(values))
+	var total float64
+	for i := range idx {
+		idx[i] = i
+		total += weights[i]
+	}
+	sort.Slice(idx, func(i, j int) bool { return values[idx[i]] < values[idx[j]] })
+
+	half := total / 2
+	var cum float64
+	for n, i := range idx {
+		cum += weights[i]
+		if cum > half {
+			return values[i]
+		}
+		if cum == half && n+1 < len(idx) {
+			return (values[i] + values[idx[n+1]]) / 2
+		}
+	}
+	return values[idx[len(idx)-1]]
+}
@@ -0,0 +1,41 @@
+package witness
+
+import "github.com/styx-oracle/styx/types"
+
+// Root returns the witness that made the original observation
+// Relays never change it
+func (r WitnessReport) Root() types.NodeID {
+	return r.Witness
+}
+
+// Hops returns how many relays the report passed through
+func (r WitnessReport) Hops() int {
+	return len(r.RelayPath)
+}
+
+// HasVisited reports whether id already originated or relayed this report
+func (r WitnessReport) HasVisited(id types.NodeID) bool {
+	if r.Witness == id {
+		return true
+	}
+	for _, hop := range r.RelayPath {
+		if hop == id {
+			return true
+		}
+	}
+	return false
+}
+
+// SameObservation reports whether two reports are copies of one observation
+// reached over different relay paths: same root, target and Origin
+// a steady witness repeating its belief makes new observations, only a
+// report without an Origin (older peers) falls back to comparing beliefs
+func (r WitnessReport) SameObservation(other WitnessReport) bool {
+	if r.Root() != other.Root() || r.Target != other.Target {
+		return false
+	}
+	if r.Origin == 0 || other.Origin == 0 {
+		return r.Belief.Equal(other.Belief)
+	}
+	return r.Origin == other.Origin
+}
@@ -1,7 +1,9 @@
 package witness
 
 import (
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/styx-oracle/styx/types"
 )
@@ -13,6 +15,7 @@ const (
 	// MaxTrust is full trust in a witness
 	MaxTrust TrustScore = 1.0
 	// MinTrust is minimum trust (but never zero - always some weight)
+	// the floor of ClassDefault witnesses, see WitnessClass
 	MinTrust TrustScore = 0.1
 	// DefaultTrust for new witnesses
 	DefaultTrust TrustScore = 0.8
@@ -20,6 +23,9 @@ const (
 	DecayRate = 0.1
 	// RecoveryRate per correct report
 	RecoveryRate = 0.05
+	// TrustSmoothing is the EWMA factor of WitnessRecord.SmoothedTrust,
+	// the weight of the newest trust value
+	TrustSmoothing = 0.2
 )
 
 // WitnessRecord tracks a single witness node
@@ -29,6 +35,31 @@ type WitnessRecord struct {
 	CorrectReports int
 	WrongReports   int
 	LastReport     types.Belief
+	PublicKey      []byte       // nil = unsigned reports accepted
+	Zone           string       // failure domain (rack, AZ), "" = unknown
+	Class          WitnessClass // picks the trust floor, see SetClass
+	HalfLife       uint64       // evidence half-life, 0 = evidence.DefaultHalfLife
+	Smoothed       TrustScore   // EWMA of Trust over updates, see SmoothedTrust
+	LastReportAt   time.Time    // wall clock time of the last RecordReport
+
+	decayedAt time.Time // last DecayByTime applied to this witness
+}
+
+// SmoothedTrust returns the trust for display: an exponential moving
+// average over trust updates, so one correct or wrong report moves it only
+// a little
+// aggregation keeps using the responsive Trust
+func (w WitnessRecord) SmoothedTrust() TrustScore {
+	if w.Smoothed == 0 {
+		// record built without the registry
+		return w.Trust
+	}
+	return w.Smoothed
+}
+
+// smooth folds the current Trust into the moving average
+func (w *WitnessRecord) smooth() {
+	w.Smoothed = TrustSmoothing*w.Trust + (1-TrustSmoothing)*w.SmoothedTrust()
 }
 
 // Registry tracks all known witnesses and their trust levels
@@ -36,28 +67,170 @@ type WitnessRecord struct {
 type Registry struct {
 	mu        sync.RWMutex
 	witnesses map[types.NodeID]*WitnessRecord
+	evicted   map[types.NodeID]bool
+	verifier  Verifier
+
+	// trustHalfLife is the silence time halving trust, 0 = no time decay
+	trustHalfLife time.Duration
+	// classFloors overrides the trust floor per class, see SetClassFloor
+	classFloors map[WitnessClass]TrustScore
+	// version counts changes that move aggregation weights, see Version
+	version uint64
 }
 
 // NewRegistry creates empty witness registry
 func NewRegistry() *Registry {
 	return &Registry{
 		witnesses: make(map[types.NodeID]*WitnessRecord),
+		evicted:   make(map[types.NodeID]bool),
+		verifier:  Ed25519Verifier{},
+	}
+}
+
+// SetVerifier replaces the signature verifier (Ed25519 by default)
+func (r *Registry) SetVerifier(v Verifier) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.verifier = v
+}
+
+// SetPublicKey stores a witness public key
+// From then on reports from that witness must be signed with the matching key
+func (r *Registry) SetPublicKey(id types.NodeID, key []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if w := r.getOrCreate(id); w != nil {
+		w.PublicKey = append([]byte(nil), key...)
+		r.version++
+	}
+}
+
+// PublicKey returns the key a witness signs with, nil without one
+func (r *Registry) PublicKey(id types.NodeID) []byte {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if w, ok := r.witnesses[id]; ok && w.PublicKey != nil {
+		return append([]byte(nil), w.PublicKey...)
+	}
+	return nil
+}
+
+// VerifyReport checks a report signature against the witness public key
+// Witnesses without a key may send unsigned reports
+func (r *Registry) VerifyReport(report WitnessReport) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var key []byte
+	if w, ok := r.witnesses[report.Witness]; ok {
+		key = w.PublicKey
+	}
+
+	if key == nil {
+		if report.Signature != nil {
+			return ErrUnknownSigner
+		}
+		return nil
+	}
+	if report.Signature == nil {
+		return ErrMissingSignature
+	}
+	if !r.verifier.Verify(key, report.SigningPayload(), report.Signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// SetZone records the failure domain a witness runs in
+// witnesses in one zone can share a failure, see finality zone quorum
+func (r *Registry) SetZone(id types.NodeID, zone string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if w := r.getOrCreate(id); w != nil {
+		w.Zone = zone
+	}
+}
+
+// Zone returns the witness zone, "" if unknown
+func (r *Registry) Zone(id types.NodeID) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if w, ok := r.witnesses[id]; ok {
+		return w.Zone
+	}
+	return ""
+}
+
+// SetHalfLife sets how fast evidence from a witness decays
+// chatty witnesses want a short half-life, slow beacons a long one
+// 0 restores the default
+func (r *Registry) SetHalfLife(id types.NodeID, halfLife uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if w := r.getOrCreate(id); w != nil {
+		w.HalfLife = halfLife
+		r.version++
 	}
 }
 
+// Version changes whenever a change can move aggregation weights: trust,
+// evidence half-life, class or eviction of any witness
+// callers caching aggregates compare it to tell whether they are stale
+func (r *Registry) Version() uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.version
+}
+
+// HalfLife returns the witness evidence half-life, 0 if it uses the default
+func (r *Registry) HalfLife(id types.NodeID) uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if w, ok := r.witnesses[id]; ok {
+		return w.HalfLife
+	}
+	return 0
+}
+
 // Register adds a new witness with default trust
+// an evicted witness registering again is back with fresh trust
 func (r *Registry) Register(id types.NodeID) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	delete(r.evicted, id)
+	r.version++
 	if _, exists := r.witnesses[id]; !exists {
 		r.witnesses[id] = &WitnessRecord{
-			ID:    id,
-			Trust: DefaultTrust,
+			ID:       id,
+			Trust:    DefaultTrust,
+			Smoothed: DefaultTrust,
 		}
 	}
 }
 
+// Admit registers a witness seen for the first time, such as a witness
+// sending its first report, and returns true
+// an evicted witness stays evicted until an explicit Register, Admit
+// returns false for it and changes nothing
+func (r *Registry) Admit(id types.NodeID) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.getOrCreate(id) != nil
+}
+
+// IsRegistered reports whether id has a living record
+func (r *Registry) IsRegistered(id types.NodeID) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.witnesses[id]
+	return ok
+}
+
 // GetTrust returns trust score for a witness
 func (r *Registry) GetTrust(id types.NodeID) TrustScore {
 	r.mu.RLock()
@@ -76,11 +249,16 @@ func (r *Registry) RecordCorrect(id types.NodeID) {
 	defer r.mu.Unlock()
 
 	w := r.getOrCreate(id)
+	if w == nil {
+		return
+	}
 	w.CorrectReports++
+	r.version++
 	w.Trust += TrustScore(RecoveryRate)
 	if w.Trust > MaxTrust {
 		w.Trust = MaxTrust
 	}
+	w.smooth()
 }
 
 // RecordWrong marks a witness report as wrong
@@ -90,20 +268,79 @@ func (r *Registry) RecordWrong(id types.NodeID) {
 	defer r.mu.Unlock()
 
 	w := r.getOrCreate(id)
+	if w == nil {
+		return
+	}
 	w.WrongReports++
+	r.version++
 	w.Trust -= TrustScore(DecayRate)
-	if w.Trust < MinTrust {
-		w.Trust = MinTrust
+	if floor := r.floor(w); w.Trust < floor {
+		w.Trust = floor
 	}
+	w.smooth()
 }
 
 // RecordReport stores the latest report from a witness
+// and marks it active now for time based trust decay
 func (r *Registry) RecordReport(id types.NodeID, belief types.Belief) {
+	r.RecordReportAt(id, belief, time.Now())
+}
+
+// RecordReportAt is RecordReport for a report received at the given time
+func (r *Registry) RecordReportAt(id types.NodeID, belief types.Belief, at time.Time) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	w := r.getOrCreate(id)
-	w.LastReport = belief
+	if w := r.getOrCreate(id); w != nil {
+		w.LastReport = belief
+		w.LastReportAt = at
+	}
+}
+
+// Evict removes a witness that left the cluster, e.g. because it died
+// its record is dropped and it is remembered as evicted until it
+// registers again, setters and report bookkeeping ignore it until then
+// returns false if it was not registered
+func (r *Registry) Evict(id types.NodeID) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.witnesses[id]; !ok {
+		return false
+	}
+	delete(r.witnesses, id)
+	r.evicted[id] = true
+	r.version++
+	return true
+}
+
+// IsEvicted reports whether a witness was evicted and not registered since
+func (r *Registry) IsEvicted(id types.NodeID) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.evicted[id]
+}
+
+// HasEvictions reports whether any witness is currently evicted
+func (r *Registry) HasEvictions() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.evicted) > 0
+}
+
+// Snapshot returns copies of all living witness records ordered by ID
+func (r *Registry) Snapshot() []WitnessRecord {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := make([]WitnessRecord, 0, len(r.witnesses))
+	for _, w := range r.witnesses {
+		rec := *w
+		rec.PublicKey = append([]byte(nil), w.PublicKey...)
+		records = append(records, rec)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID.Less(records[j].ID) })
+	return records
 }
 
 // AllWitnesses returns all registered witness IDs
@@ -125,18 +362,25 @@ func (r *Registry) GetRecord(id types.NodeID) *WitnessRecord {
 
 	if w, ok := r.witnesses[id]; ok {
 		copy := *w
+		copy.PublicKey = append([]byte(nil), w.PublicKey...)
 		return &copy
 	}
 	return nil
 }
 
+// getOrCreate returns the record of id, creating it on first use
+// nil for an evicted witness, only Register brings it back
 func (r *Registry) getOrCreate(id types.NodeID) *WitnessRecord {
 	if w, ok := r.witnesses[id]; ok {
 		return w
 	}
+	if r.evicted[id] {
+		return nil
+	}
 	w := &WitnessRecord{
-		ID:    id,
-		Trust: DefaultTrust,
+		ID:       id,
+		Trust:    DefaultTrust,
+		Smoothed: DefaultTrust,
 	}
 	r.witnesses[id] = w
 	return w
@@ -0,0 +1,135 @@
+package witness
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+func TestSmoothedTrustLagsButTracks(t *testing.T) {
+	reg := NewRegistry()
+	id := types.NewNodeID(1)
+	reg.Register(id)
+
+	if rec := reg.GetRecord(id); rec.SmoothedTrust() != DefaultTrust {
+		t.Fatalf("fresh witness smoothed trust = %v, want %v", rec.SmoothedTrust(), DefaultTrust)
+	}
+
+	// one event moves raw trust fully but the display only a little
+	reg.RecordWrong(id)
+	rec := reg.GetRecord(id)
+	rawStep := float64(DefaultTrust - rec.Trust)
+	if shownStep := float64(DefaultTrust - rec.SmoothedTrust()); shownStep <= 0 || shownStep >= rawStep/2 {
+		t.Errorf("smoothed moved %v for a raw step of %v", shownStep, rawStep)
+	}
+
+	// a run of wrong reports: raw trust drops fast, smoothed follows behind
+	prev := rec.SmoothedTrust()
+	for i := range 6 {
+		reg.RecordWrong(id)
+		rec := reg.GetRecord(id)
+		if rec.SmoothedTrust() <= rec.Trust {
+			t.Errorf("wrong %d: smoothed %v not lagging above raw %v", i, rec.SmoothedTrust(), rec.Trust)
+		}
+		if rec.SmoothedTrust() >= prev {
+			t.Errorf("wrong %d: smoothed %v did not follow raw trust down", i, rec.SmoothedTrust())
+		}
+		prev = rec.SmoothedTrust()
+	}
+
+	// holding raw trust steady, smoothed converges to it
+	for range 40 {
+		reg.RecordWrong(id)
+	}
+	rec = reg.GetRecord(id)
+	if math.Abs(float64(rec.SmoothedTrust()-rec.Trust)) > 0.01 {
+		t.Errorf("smoothed %v did not converge to raw %v", rec.SmoothedTrust(), rec.Trust)
+	}
+}
+
+func TestDecayByTime(t *testing.T) {
+	reg := NewRegistry()
+	const halfLife = time.Hour
+	reg.SetTrustHalfLife(halfLife)
+	silent, active, never := types.NewNodeID(1), types.NewNodeID(2), types.NewNodeID(3)
+	start := time.Now()
+	reg.RecordReportAt(silent, types.UnknownBelief(), start)
+	reg.Register(never)
+
+	// halfway there in one call, the rest in another: same as one call
+	reg.DecayByTime(start.Add(halfLife))
+	reg.RecordReportAt(active, types.UnknownBelief(), start.Add(2*halfLife))
+	reg.DecayByTime(start.Add(2 * halfLife))
+
+	want := DefaultTrust * 0.25
+	if got := reg.GetTrust(silent); math.Abs(float64(got-want)) > 1e-9 {
+		t.Errorf("silent for 2 half-lives: trust %v, want %v (75%% less)", got, want)
+	}
+	if got := reg.GetTrust(active); got != DefaultTrust {
+		t.Errorf("active witness decayed to %v", got)
+	}
+	if got := reg.GetTrust(never); got != DefaultTrust {
+		t.Errorf("witness without reports decayed to %v", got)
+	}
+
+	// never below the floor
+	reg.DecayByTime(start.Add(100 * halfLife))
+	if got := reg.GetTrust(silent); got != MinTrust {
+		t.Errorf("long silence: trust %v, want MinTrust", got)
+	}
+}
+
+func TestStartAutoDecay(t *testing.T) {
+	reg := NewRegistry()
+	reg.SetTrustHalfLife(10 * time.Millisecond)
+	id := types.NewNodeID(1)
+	reg.RecordReport(id, types.UnknownBelief())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reg.StartAutoDecay(ctx, time.Millisecond)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for reg.GetTrust(id) >= DefaultTrust {
+		if time.Now().After(deadline) {
+			t.Fatal("trust did not decay in the background")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestEvictionSticksUntilRegister(t *testing.T) {
+	reg := NewRegistry()
+	id := types.NewNodeID(1)
+	reg.Register(id)
+	reg.RecordWrong(id)
+	if !reg.Evict(id) {
+		t.Fatal("registered witness should evict")
+	}
+
+	// bookkeeping and setters must not bring it back
+	if reg.Admit(id) {
+		t.Error("Admit revived an evicted witness")
+	}
+	reg.RecordReport(id, types.UnknownBelief())
+	reg.RecordCorrect(id)
+	reg.SetHalfLife(id, 10)
+	reg.SetZone(id, "a")
+	reg.SetPublicKey(id, make([]byte, 32))
+	reg.SetClass(id, ClassNoisy)
+	if !reg.IsEvicted(id) || reg.GetRecord(id) != nil || len(reg.Snapshot()) != 0 {
+		t.Fatalf("evicted witness revived: evicted=%v record=%v", reg.IsEvicted(id), reg.GetRecord(id))
+	}
+
+	reg.Register(id)
+	rec := reg.GetRecord(id)
+	if reg.IsEvicted(id) || rec == nil || rec.Trust != DefaultTrust || rec.HalfLife != 0 {
+		t.Errorf("Register should bring it back fresh, got %+v", rec)
+	}
+	if !reg.Admit(types.NewNodeID(2)) || reg.GetRecord(types.NewNodeID(2)) == nil {
+		t.Error("Admit should register a new witness")
+	}
+}
@@ -0,0 +1,44 @@
+package witness
+
+import (
+	"crypto/ed25519"
+	"errors"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+// Signature errors
+var (
+	ErrMissingSignature = errors.New("report from keyed witness is unsigned")
+	ErrInvalidSignature = errors.New("report signature is invalid")
+	ErrUnknownSigner    = errors.New("signed report from witness without a public key")
+)
+
+// Verifier checks a signature over a message with a witness public key
+// Swappable so tests can stub cryptography
+type Verifier interface {
+	Verify(publicKey, message, signature []byte) bool
+}
+
+// Ed25519Verifier is the default Verifier
+type Ed25519Verifier struct{}
+
+// Verify checks an Ed25519 signature
+func (Ed25519Verifier) Verify(publicKey, message, signature []byte) bool {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return false
+	}
+	return ed25519.Verify(ed25519.PublicKey(publicKey), message, signature)
+}
+
+// SigningPayload is the canonical byte encoding signed by a witness
+// Covers witness, target, belief and timestamp
+func (r WitnessReport) SigningPayload() []byte {
+	return types.AppendSigningPayload(make([]byte, 0, 8*8), r.Witness, r.Target, r.Belief, r.Timestamp.Value())
+}
+
+// Sign returns a copy of the report signed with an Ed25519 private key
+func (r WitnessReport) Sign(key ed25519.PrivateKey) WitnessReport {
+	r.Signature = ed25519.Sign(key, r.SigningPayload())
+	return r
+}
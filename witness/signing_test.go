@@ -0,0 +1,110 @@
+package witness
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+func signingKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return pub, priv
+}
+
+func TestVerifyReportSignatures(t *testing.T) {
+	pub, priv := signingKey(t)
+	_, otherPriv := signingKey(t)
+
+	reg := NewRegistry()
+	reg.SetPublicKey(types.NewNodeID(1), pub)
+
+	signed := report(1, 0.8, 0.1, 0.1).Sign(priv)
+	if err := reg.VerifyReport(signed); err != nil {
+		t.Errorf("valid signature rejected: %v", err)
+	}
+
+	tampered := signed
+	tampered.Belief = types.MustBelief(0.1, 0.8, 0.1)
+	if err := reg.VerifyReport(tampered); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("tampered belief: got %v, want ErrInvalidSignature", err)
+	}
+
+	wrongKey := report(1, 0.8, 0.1, 0.1).Sign(otherPriv)
+	if err := reg.VerifyReport(wrongKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("wrong key: got %v, want ErrInvalidSignature", err)
+	}
+
+	if err := reg.VerifyReport(report(1, 0.8, 0.1, 0.1)); !errors.Is(err, ErrMissingSignature) {
+		t.Errorf("unsigned: got %v, want ErrMissingSignature", err)
+	}
+
+	// Witnesses without keys keep working unsigned
+	if err := reg.VerifyReport(report(2, 0.8, 0.1, 0.1)); err != nil {
+		t.Errorf("unkeyed unsigned report rejected: %v", err)
+	}
+	if err := reg.VerifyReport(report(2, 0.8, 0.1, 0.1).Sign(priv)); !errors.Is(err, ErrUnknownSigner) {
+		t.Errorf("unkeyed signed report: got %v, want ErrUnknownSigner", err)
+	}
+}
+
+func TestAggregateDropsForgedReports(t *testing.T) {
+	pub, _ := signingKey(t)
+	_, forger := signingKey(t)
+
+	reg := NewRegistry()
+	reg.SetPublicKey(types.NewNodeID(1), pub)
+	agg := NewAggregator(reg)
+
+	// Forged dead vote from keyed witness 1 must not count
+	result := agg.Aggregate([]WitnessReport{
+		report(1, 0.0, 0.9, 0.1).Sign(forger),
+		report(2, 0.8, 0.1, 0.1),
+	})
+	if len(result.Reports) != 1 || result.Reports[0].Witness != types.NewNodeID(2) {
+		t.Fatalf("forged report aggregated: %+v", result.Reports)
+	}
+	if result.Belief.Dead().Value() > 0.2 {
+		t.Errorf("forged dead vote leaked into belief: %v", result.Belief)
+	}
+}
+
+func TestAggregateKeepsUnkeyedSignedReports(t *testing.T) {
+	_, priv := signingKey(t)
+	agg := NewAggregator(NewRegistry())
+
+	// nothing to verify against, the report counts like an unsigned one
+	result := agg.Aggregate([]WitnessReport{
+		report(1, 0.8, 0.1, 0.1).Sign(priv),
+		report(2, 0.7, 0.2, 0.1),
+	})
+	if len(result.Reports) != 2 {
+		t.Errorf("unkeyed signed report dropped: %+v", result.Reports)
+	}
+}
+
+type stubVerifier bool
+
+func (s stubVerifier) Verify(publicKey, message, signature []byte) bool { return bool(s) }
+
+func TestSetVerifierStub(t *testing.T) {
+	reg := NewRegistry()
+	reg.SetPublicKey(types.NewNodeID(1), []byte("key"))
+
+	r := report(1, 0.8, 0.1, 0.1)
+	r.Signature = []byte("sig")
+
+	reg.SetVerifier(stubVerifier(true))
+	if err := reg.VerifyReport(r); err != nil {
+		t.Errorf("stub accept: %v", err)
+	}
+	reg.SetVerifier(stubVerifier(false))
+	if err := reg.VerifyReport(r); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("stub reject: got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package witness
+
+import "github.com/styx-oracle/styx/types"
+
+// Trust bands used by ScoreSummary
+// high is strictly above HighTrustThreshold, low strictly below
+// LowTrustThreshold, everything in between is medium
+// trust within types.ConfidenceEpsilon of a threshold is on it, so float
+// error in trust updates (0.8 - 0.1) does not move a witness across a band
+const (
+	HighTrustThreshold TrustScore = 0.7
+	LowTrustThreshold  TrustScore = 0.4
+)
+
+// HealthyHighTrustWitnesses is how many high trust witnesses IsHealthy wants
+const HealthyHighTrustWitnesses = 3
+
+// TrustSummary is a fleet wide view of witness trust for operators
+// a falling AverageTrust or a growing LowTrust means the pool as a whole is
+// becoming less trustworthy
+// EvictedCount counts witnesses evicted and not registered since,
+// they are not part of the other counts
+type TrustSummary struct {
+	TotalWitnesses  int     `json:"total_witnesses"`
+	HighTrust       int     `json:"high_trust"`
+	MediumTrust     int     `json:"medium_trust"`
+	LowTrust        int     `json:"low_trust"`
+	MinTrustReached int     `json:"min_trust_reached"`
+	AverageTrust    float64 `json:"average_trust"`
+	CorrectReports  int     `json:"correct_reports"`
+	WrongReports    int     `json:"wrong_reports"`
+	EvictedCount    int     `json:"evicted_count"`
+}
+
+// ScoreSummary buckets the living witnesses by trust
+// a witness at its class floor is also counted as LowTrust
+func (r *Registry) ScoreSummary() TrustSummary {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s := TrustSummary{
+		TotalWitnesses: len(r.witnesses),
+		EvictedCount:   len(r.evicted),
+	}
+	var sum float64
+	for _, w := range r.witnesses {
+		sum += float64(w.Trust)
+		switch {
+		case w.Trust > HighTrustThreshold+types.ConfidenceEpsilon:
+			s.HighTrust++
+		case w.Trust < LowTrustThreshold-types.ConfidenceEpsilon:
+			s.LowTrust++
+		default:
+			s.MediumTrust++
+		}
+		if w.Trust <= r.floor(w) {
+			s.MinTrustReached++
+		}
+		s.CorrectReports += w.CorrectReports
+		s.WrongReports += w.WrongReports
+	}
+	if s.TotalWitnesses > 0 {
+		s.AverageTrust = sum / float64(s.TotalWitnesses)
+	}
+	return s
+}
+
+// IsHealthy reports whether at least HealthyHighTrustWitnesses witnesses
+// are in the high trust band
+func (r *Registry) IsHealthy() bool {
+	return r.ScoreSummary().HighTrust >= HealthyHighTrustWitnesses
+}
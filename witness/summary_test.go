@@ -0,0 +1,92 @@
+package witness
+
+import (
+	"math"
+	"testing"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+func TestScoreSummary(t *testing.T) {
+	reg := NewRegistry()
+	id := func(n uint64) types.NodeID { return types.NewNodeID(n) }
+	wrong := func(n uint64, times int) {
+		for range times {
+			reg.RecordWrong(id(n))
+		}
+	}
+
+	// 3 at default trust (0.8) and 1 at full trust: high
+	for n := uint64(1); n <= 4; n++ {
+		reg.Register(id(n))
+	}
+	for range 4 {
+		reg.RecordCorrect(id(4))
+	}
+	// 2 at 0.5: medium
+	wrong(5, 3)
+	wrong(6, 3)
+	// 1 at 0.3: low
+	wrong(7, 5)
+	// 3 floored at MinTrust: low and min trust reached
+	wrong(8, 8)
+	wrong(9, 10)
+	wrong(10, 20)
+	// evicted witnesses are counted apart
+	reg.Register(id(11))
+	reg.Evict(id(11))
+
+	s := reg.ScoreSummary()
+	want := TrustSummary{
+		TotalWitnesses:  10,
+		HighTrust:       4,
+		MediumTrust:     2,
+		LowTrust:        4,
+		MinTrustReached: 3,
+		CorrectReports:  4,
+		WrongReports:    49,
+		EvictedCount:    1,
+	}
+	wantAvg := (3*0.8 + 1.0 + 2*0.5 + 0.3 + 3*0.1) / 10
+	if math.Abs(s.AverageTrust-wantAvg) > 1e-9 {
+		t.Errorf("AverageTrust = %f, want %f", s.AverageTrust, wantAvg)
+	}
+	s.AverageTrust = 0
+	if s != want {
+		t.Errorf("ScoreSummary = %+v, want %+v", s, want)
+	}
+	if !reg.IsHealthy() {
+		t.Error("4 high trust witnesses should be healthy")
+	}
+
+	wrong(1, 2)
+	wrong(2, 2)
+	if reg.IsHealthy() {
+		t.Errorf("2 high trust witnesses reported healthy: %+v", reg.ScoreSummary())
+	}
+	if s := NewRegistry().ScoreSummary(); s != (TrustSummary{}) {
+		t.Errorf("empty registry summary = %+v", s)
+	}
+}
+
+func TestScoreSummaryBandEdges(t *testing.T) {
+	reg := NewRegistry()
+	set := func(n uint64, trust float64) {
+		reg.Register(types.NewNodeID(n))
+		reg.witnesses[types.NewNodeID(n)].Trust = TrustScore(trust)
+	}
+	// float error on a threshold (0.8 - 0.1, 1.2 - 0.8 at run time) stays
+	// on the threshold: medium
+	set(1, 0.7000000000000001)
+	set(2, 0.3999999999999999)
+	set(3, 0.7)
+	set(4, 0.4)
+	// clearly past a threshold
+	set(5, 0.7001)
+	set(6, 0.3999)
+
+	s := reg.ScoreSummary()
+	if s.HighTrust != 1 || s.MediumTrust != 4 || s.LowTrust != 1 {
+		t.Errorf("high %d medium %d low %d, want 1 4 1", s.HighTrust, s.MediumTrust, s.LowTrust)
+	}
+}
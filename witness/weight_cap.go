@@ -0,0 +1,97 @@
+package witness
+
+import (
+	"sort"
+
+	"github.com/styx-oracle/styx/types"
+)
+
+// WithMaxWitnessShare caps the fraction of total aggregation weight any one
+// witness can hold, so no single witness decides the belief while others
+// report, however trusted it is
+// complements P12 trust decay: the cap holds before a liar is caught
+// 0 or less disables the cap, values of 1 or more never bind
+func (a *Aggregator) WithMaxWitnessShare(share float64) *Aggregator {
+	a.maxWitnessShare = share
+	return a
+}
+
+// shareScale returns per witness factors bringing every witness under the
+// share cap, nil when the cap is off or nothing exceeds it
+func (a *Aggregator) shareScale(reports []WitnessReport, present []types.NodeID) map[types.NodeID]float64 {
+	if a.maxWitnessShare <= 0 || a.maxWitnessShare >= 1 {
+		return nil
+	}
+
+	perReport := make(map[types.NodeID]float64)
+	totals := make(map[types.NodeID]float64)
+	for _, r := range reports {
+		w, seen := perReport[r.Witness]
+		if !seen {
+			w = float64(r.EffectiveTrust)
+			if a.collusion != nil {
+				w *= a.collusion.Penalty(r.Witness, present)
+			}
+			perReport[r.Witness] = w
+		}
+		totals[r.Witness] += w * r.reliability()
+	}
+	if len(totals) < 2 {
+		// a lone witness holds everything, there is nobody to balance against
+		return nil
+	}
+
+	capped := capShares(totals, a.maxWitnessShare)
+	if capped == nil {
+		return nil
+	}
+	scale := make(map[types.NodeID]float64, len(totals))
+	for id, w := range totals {
+		scale[id] = 1
+		if w > 0 {
+			scale[id] = capped[id] / w
+		}
+	}
+	return scale
+}
+
+// capShares lowers the heaviest weights until none exceeds share of the
+// new total, leaving the rest untouched
+// with k witnesses capped at share*T and the others summing to U,
+// T = U / (1 - k*share)
+// if no k works (too few witnesses for the cap) everyone gets equal weight
+// returns nil when no weight exceeds the cap
+func capShares(weights map[types.NodeID]float64, share float64) map[types.NodeID]float64 {
+	ids := make([]types.NodeID, 0, len(weights))
+	var total float64
+	for id, w := range weights {
+		ids = append(ids, id)
+		total += w
+	}
+	sort.Slice(ids, func(i, j int) bool { return weights[ids[i]] > weights[ids[j]] })
+	if total <= 0 || weights[ids[0]] <= share*total {
+		return nil
+	}
+
+	rest := total
+	for k := 0; k < len(ids) && float64(k)*share < 1; k++ {
+		newTotal := rest / (1 - float64(k)*share)
+		if weights[ids[k]] <= share*newTotal {
+			capped := make(map[types.NodeID]float64, len(ids))
+			for i, id := range ids {
+				capped[id] = weights[id]
+				if i < k {
+					capped[id] = share * newTotal
+				}
+			}
+			return capped
+		}
+		rest -= weights[ids[k]]
+	}
+
+	equal := make(map[types.NodeID]float64, len(ids))
+	for _, id := range ids {
+		equal[id] = total / float64(len(ids))
+	}
+	return equal
+}